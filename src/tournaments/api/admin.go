@@ -0,0 +1,71 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+const ADMIN_TOKEN_HEADER = `X-Admin-Token`
+
+var adminTokenParam = paramDoc{Name: ADMIN_TOKEN_HEADER, In: "header", Description: "Token set by --admin-token flag", Required: true, Type: "string"}
+
+//Lets request through only if its X-Admin-Token header matches ApiConf.AdminToken,
+//responds 403 otherwise && if no admin token configured
+func (a *Api) requireAdmin(ctx *gin.Context) {
+	token := ctx.GetHeader(ADMIN_TOKEN_HEADER)
+	if a.conf.AdminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(a.conf.AdminToken)) != 1 {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin token required"})
+		return
+	}
+	ctx.Next()
+}
+
+//POST /admin/reconcile (/admin/reconciliations in v1) with JSON body like {"fix":true}
+//checks balances against operations ledger, tournaments' escrow && orphan rows,
+//"fix" writes "correction" operations making ledger sums of drifted balances equal them
+//&& resets held points to sums of holds;
+//responds 200 with types.ReconciliationReport as "data"
+func (a *Api) reconcile(ctx *gin.Context) {
+	var parsedRequestBody types.ReconcileRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	report, err := a.stor.Reconcile(parsedRequestBody.Fix)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrReconciliationFailed, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": report.(*types.ReconciliationReport)})
+}
+
+//GET /admin/overlays responds with finished tournaments having guaranteed prize pool, newest first,
+//their collected deposits && overlays funded by the house; accepts filters "currency" and "overlaid_only"
+//paged by "limit" and "offset" HTTP query params, "meta" holds sum of overlays of every matching tournament;
+//responds 400 on incorrect params listing every invalid one
+func (a *Api) getOverlays(ctx *gin.Context) {
+	params := &queryParams{ctx: ctx}
+	query := &types.OverlaysQuery{
+		Currency: params.currency("currency", ""),
+		Limit:    params.limit(),
+		Offset:   params.int("offset", 0),
+	}
+	if overlaidOnly := params.bool("overlaid_only"); overlaidOnly != nil {
+		query.OverlaidOnly = *overlaidOnly
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	found, err := a.stor.FetchOverlays(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrOverlaysNotFound, err)
+		return
+	}
+	page := found.(*types.OverlaysPage)
+	ctx.JSON(http.StatusOK, &types.OverlaysResponse{
+		Data: page.Overlays,
+		Meta: &types.OverlaysMeta{Total: page.Total, TotalOverlay: page.TotalOverlay},
+	})
+}
@@ -4,12 +4,17 @@ import (
 	"log"
 
 	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/graph"
 	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
 )
 
 type ApiConf struct {
-	ListenAddr   string
-	RelativePath string
+	ListenAddr     string
+	RelativePath   string
+	V1RelativePath string
+	// Required by admin routes in X-Admin-Token header, admin routes are forbidden if empty
+	AdminToken string
 }
 
 type Api struct {
@@ -17,6 +22,8 @@ type Api struct {
 	engine *gin.Engine
 	stor   types.ApiStorage
 	logger *log.Logger
+	spec   map[string]interface{}
+	schema *graphql.Schema
 }
 
 func NewApi(conf *ApiConf, s interface{}, logger *log.Logger) (a *Api, err error) {
@@ -24,6 +31,9 @@ func NewApi(conf *ApiConf, s interface{}, logger *log.Logger) (a *Api, err error
 	//if !ok {
 	//	return nil, errors.New(`Unacceptable storage passed!`)
 	//}
+	if err = types.CheckValidationTags(types.ValidatedRequests...); err != nil {
+		return nil, err
+	}
 	a = &Api{
 		conf: conf,
 		// Let gin use its Logger() && Recovery() by default
@@ -31,7 +41,12 @@ func NewApi(conf *ApiConf, s interface{}, logger *log.Logger) (a *Api, err error
 		stor:   s.(types.ApiStorage),
 		logger: logger,
 	}
+	if a.schema, err = graph.NewSchema(a.stor, logger); err != nil {
+		return nil, err
+	}
 	a.mountRoutes(a.engine.Group(conf.RelativePath))
+	a.mountV1Routes(a.engine.Group(conf.V1RelativePath))
+	a.spec = a.buildSpec()
 	return a, nil
 }
 
@@ -40,15 +55,118 @@ func (a *Api) Run() error {
 }
 
 func (a *Api) mountRoutes(api *gin.RouterGroup) {
+	api.GET("/openapi.json", a.getOpenApiSpec)
+	api.GET("/docs", a.getApiDocs)
+	api.POST("/graphql", a.postGraphql)
+
 	apiUser := api.Group("/user")
 	apiUser.GET("/balance", a.getUserBalance)
+	apiUser.GET("/balances", a.getBalancesAsOf)
+	apiUser.GET("/wallets", a.getUserWallets)
+	apiUser.GET("/expiringPoints", a.getUserExpiringPoints)
+	apiUser.GET("/notifications", a.getUserNotifications)
+	apiUser.GET("/statement", a.getUserStatement)
 	apiUser.POST("/take", a.takePointsFromUser)
 	apiUser.POST("/fund", a.fundUserWithPoints)
+	apiUser.POST("/transfer", a.transferPoints)
+	apiUser.GET("/transfers", a.getUserTransfers)
+	apiUser.POST("/redeemVoucher", a.redeemVoucher)
+	apiUser.GET("/ratings", a.getUserRatings)
+	apiUser.GET("/ratingHistory", a.getUserRatingHistory)
+	apiUser.GET("/leaderboard", a.getLeaderboard)
+	apiUser.GET("/tickets", a.getUserTickets)
 
 	apiTournament := api.Group("/tournament")
 	apiTournament.GET("/list", a.getTournaments)
 	apiTournament.GET("/info", a.getTournamentInfo)
+	apiTournament.GET("/details", a.getTournamentDetails)
 	apiTournament.POST("/announceTournament", a.announceTournament)
 	apiTournament.POST("/joinTournament", a.joinTournament)
+	apiTournament.POST("/withdrawTournament", a.withdrawTournament)
+	apiTournament.POST("/joinTournamentByTeam", a.joinTournamentByTeam)
+	apiTournament.POST("/withdrawTeam", a.withdrawTeam)
+	apiTournament.POST("/closeRegistration", a.closeTournamentRegistration)
+	apiTournament.POST("/cancelTournament", a.cancelTournament)
 	apiTournament.POST("/resultTournament", a.resultTournament)
+	apiTournament.POST("/generateBracket", a.generateBracket)
+	apiTournament.GET("/bracket", a.getBracket)
+	apiTournament.POST("/matchResult", a.submitMatchResult)
+	apiTournament.POST("/roundResults", a.submitRoundResults)
+	apiTournament.GET("/seasons", a.getSeasons)
+	apiTournament.GET("/season", a.getSeason)
+	apiTournament.GET("/seasonStandings", a.getSeasonStandings)
+
+	apiTeam := api.Group("/team")
+	apiTeam.POST("/create", a.createTeam)
+	apiTeam.GET("/info", a.getTeam)
+
+	apiAdmin := api.Group("/admin", a.requireAdmin)
+	apiAdmin.POST("/reconcile", a.reconcile)
+	apiAdmin.POST("/transferLimit", a.setTransferLimit)
+	apiAdmin.POST("/voucher", a.createVoucher)
+	apiAdmin.POST("/voucherBatch", a.createVoucherBatch)
+	apiAdmin.GET("/voucherRedemptions", a.getVoucherRedemptions)
+	apiAdmin.GET("/overlays", a.getOverlays)
+	apiAdmin.POST("/recomputeRatings", a.recomputeRatings)
+	apiAdmin.POST("/season", a.createSeason)
+}
+
+// Resource-oriented routes, mounted alongside mountRoutes ones
+func (a *Api) mountV1Routes(api *gin.RouterGroup) {
+	api.GET("/openapi.json", a.getOpenApiSpec)
+	api.GET("/docs", a.getApiDocs)
+
+	api.GET("/balances", a.getBalancesAsOf)
+	api.GET("/leaderboards/:metric", a.getLeaderboardV1)
+
+	apiSeasons := api.Group("/seasons")
+	apiSeasons.GET("", a.getSeasons)
+	apiSeasons.GET("/:id", a.getSeasonV1)
+	apiSeasons.GET("/:id/standings", a.getSeasonStandingsV1)
+
+	apiUsers := api.Group("/users")
+	apiUsers.GET("/:id/balance", a.getUserBalanceV1)
+	apiUsers.GET("/:id/wallets", a.getUserWalletsV1)
+	apiUsers.GET("/:id/expiring-points", a.getUserExpiringPointsV1)
+	apiUsers.GET("/:id/notifications", a.getUserNotificationsV1)
+	apiUsers.GET("/:id/statement", a.getUserStatementV1)
+	apiUsers.POST("/:id/balance/top-ups", a.topUpUserBalanceV1)
+	apiUsers.POST("/:id/balance/withdrawals", a.withdrawUserBalanceV1)
+	apiUsers.GET("/:id/transfers", a.getUserTransfersV1)
+	apiUsers.POST("/:id/transfers", a.createTransferV1)
+	apiUsers.POST("/:id/voucher-redemptions", a.createVoucherRedemptionV1)
+	apiUsers.GET("/:id/ratings", a.getUserRatingsV1)
+	apiUsers.GET("/:id/rating-history", a.getUserRatingHistoryV1)
+	apiUsers.GET("/:id/tickets", a.getUserTicketsV1)
+
+	apiTeams := api.Group("/teams")
+	apiTeams.POST("", a.createTeamV1)
+	apiTeams.GET("/:id", a.getTeamV1)
+
+	apiTournaments := api.Group("/tournaments")
+	apiTournaments.GET("", a.getTournamentsV1)
+	apiTournaments.POST("", a.createTournamentV1)
+	apiTournaments.GET("/:id", a.getTournamentV1)
+	apiTournaments.GET("/:id/details", a.getTournamentDetailsV1)
+	apiTournaments.POST("/:id/entries", a.createTournamentEntryV1)
+	apiTournaments.DELETE("/:id/entries/:player_id", a.deleteTournamentEntryV1)
+	apiTournaments.POST("/:id/team-entries", a.createTeamEntryV1)
+	apiTournaments.DELETE("/:id/team-entries/:team_id", a.deleteTeamEntryV1)
+	apiTournaments.POST("/:id/registration-closure", a.createRegistrationClosureV1)
+	apiTournaments.POST("/:id/cancellation", a.createCancellationV1)
+	apiTournaments.POST("/:id/results", a.createTournamentResultsV1)
+	apiTournaments.POST("/:id/bracket", a.createBracketV1)
+	apiTournaments.GET("/:id/bracket", a.getBracketV1)
+	apiTournaments.PUT("/:id/matches/:match_id/result", a.putMatchResultV1)
+	apiTournaments.POST("/:id/rounds/:round/results", a.createRoundResultsV1)
+
+	apiAdmin := api.Group("/admin", a.requireAdmin)
+	apiAdmin.POST("/reconciliations", a.reconcile)
+	apiAdmin.PUT("/users/:id/transfer-limit", a.setTransferLimitV1)
+	apiAdmin.POST("/vouchers", a.createVoucher)
+	apiAdmin.POST("/voucher-batches", a.createVoucherBatch)
+	apiAdmin.GET("/voucher-redemptions", a.getVoucherRedemptions)
+	apiAdmin.GET("/overlays", a.getOverlays)
+	apiAdmin.POST("/rating-recomputations", a.recomputeRatings)
+	apiAdmin.POST("/seasons", a.createSeason)
 }
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Responds with current user balance in "currency" HTTP query param (points by default),
+//or with balance made of operations before "as_of" HTTP query param if given;
+//responds 400 on incorrect "as_of" or "currency", 404 on absent record,
+//200 with full UserPointsBalance or BalanceAsOf as "data" otherwise
+func (a *Api) respondBalance(ctx *gin.Context, userId uint) {
+	var (
+		balance interface{}
+		err     error
+	)
+	params := &queryParams{ctx: ctx}
+	asOf := params.time("as_of")
+	currency := params.currency("currency", types.CURRENCY_POINTS)
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	if asOf.IsZero() {
+		balance, err = a.stor.FetchBalance(userId, currency)
+	} else {
+		balance, err = a.stor.FetchBalanceAsOf(userId, currency, asOf)
+	}
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrBalanceNotFound, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": balance})
+}
+
+//GET /user/balances (/balances in v1)
+//responds with wallet balances of every user as they were before "as_of" (now by default) HTTP query param,
+//of "currency" one if given, ordered by user ID and currency and paged by "limit" && "offset";
+//responds 400 on incorrect params listing every invalid one
+func (a *Api) getBalancesAsOf(ctx *gin.Context) {
+	params := &queryParams{ctx: ctx}
+	query := &types.BalancesAsOfQuery{
+		AsOf:     params.time("as_of"),
+		Currency: params.currency("currency", ""),
+		Limit:    params.limit(),
+		Offset:   params.int("offset", 0),
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	if query.AsOf.IsZero() {
+		query.AsOf = time.Now()
+	}
+	found, err := a.stor.FetchBalancesAsOf(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrBalanceNotFound, err)
+		return
+	}
+	page := found.(*types.BalancesAsOfPage)
+	ctx.JSON(http.StatusOK, &types.BalancesAsOfResponse{
+		Data: page.Balances,
+		Meta: &types.PageMeta{Total: page.Total},
+	})
+}
+
+//Responds with every wallet of user ordered by currency,
+//200 with list of UserPointsBalance as "data", empty if user has no wallets
+func (a *Api) respondWallets(ctx *gin.Context, userId uint) {
+	wallets, err := a.stor.FetchBalances([]uint{userId})
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrBalanceNotFound, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": wallets.([]*types.UserPointsBalance)})
+}
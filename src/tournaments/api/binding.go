@@ -0,0 +1,43 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Reads JSON request body into request, rejecting unknown fields,
+//and validates it by types.Validate.
+//Responds 500 on unreadable body, 400 on malformed JSON,
+//400 with every invalid field listed as "fields" on validation failure;
+//returns false if response is already sent
+func (a *Api) bindRequest(ctx *gin.Context, request interface{}) bool {
+	data, err := ioutil.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, errors.New("Could not read request body"))
+		a.logger.Println(err.Error())
+		return false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(request); err != nil {
+		a.logger.Println(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Incorrect request body provided: " + err.Error()})
+		return false
+	}
+	if decoder.More() {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Incorrect request body provided: unexpected data after JSON value"})
+		return false
+	}
+	if errs := types.Validate(request); errs != nil {
+		a.logger.Println(errs.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": types.ErrRequestValidationFailed.Message, "fields": errs})
+		return false
+	}
+	return true
+}
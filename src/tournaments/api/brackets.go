@@ -0,0 +1,147 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//processes POST JSON body like {"tournament_id":1,"seeding":"random","prizes":[500,300,200]}
+//generates bracket of tournament format closing its registration, prizes of places are paid as bracket completes;
+//responds 400 on invalid request or error, 200 with full Bracket as "data" otherwise
+func (a *Api) generateBracket(ctx *gin.Context) {
+	var parsedRequestBody types.BracketRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondBracketGeneration(ctx, &parsedRequestBody, http.StatusOK)
+}
+
+//POST /tournaments/:id/bracket with JSON body like {"seeding":"manual","seeds":[3,1,2]}
+//responds 400 on incorrect id, invalid request or error, 201 with full Bracket as "data" otherwise
+func (a *Api) createBracketV1(ctx *gin.Context) {
+	var parsedRequestBody types.TournamentBracketRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondBracketGeneration(ctx, parsedRequestBody.BracketRequest(id), http.StatusCreated)
+}
+
+func (a *Api) respondBracketGeneration(ctx *gin.Context, request *types.BracketRequest, status int) {
+	bracket, err := a.stor.GenerateBracket(request)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrBracketNotGenerated, err)
+		return
+	}
+	ctx.JSON(status, gin.H{"data": bracket.(*types.Bracket)})
+}
+
+//Seek by HTTP query "id" param of tournament
+//responds 400 on empty id, 404 on absent bracket, 200 with full Bracket as "data" otherwise
+func (a *Api) getBracket(ctx *gin.Context) {
+	id := ctx.Query("id")
+	if id == "" {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	intId, err := strconv.Atoi(id)
+	if err != nil {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		a.logger.Println(err.Error())
+		return
+	}
+	a.respondBracket(ctx, uint(intId))
+}
+
+//GET /tournaments/:id/bracket
+//responds 400 on incorrect id, 404 on absent bracket, 200 with full Bracket as "data" otherwise
+func (a *Api) getBracketV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondBracket(ctx, id)
+}
+
+func (a *Api) respondBracket(ctx *gin.Context, tournamentId uint) {
+	bracket, err := a.stor.FetchBracket(tournamentId)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrBracketNotFound, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": bracket.(*types.Bracket)})
+}
+
+//processes POST JSON body like {"tournament_id":1,"match_id":3,"winner_id":2,"score1":1,"score2":3},
+//"winner_id" could be omitted for round robin draw;
+//responds 400 on invalid request or error, 200 with full Match as "data" otherwise
+func (a *Api) submitMatchResult(ctx *gin.Context) {
+	var parsedRequestBody types.MatchResultRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondMatchResult(ctx, &parsedRequestBody)
+}
+
+//PUT /tournaments/:id/matches/:match_id/result with JSON body like {"winner_id":2,"score1":1,"score2":3}
+//responds 400 on incorrect ids, invalid request or error, 200 with full Match as "data" otherwise
+func (a *Api) putMatchResultV1(ctx *gin.Context) {
+	var parsedRequestBody types.MatchScoreRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	matchId, ok := a.pathId(ctx, "match_id")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondMatchResult(ctx, parsedRequestBody.MatchResultRequest(id, matchId))
+}
+
+func (a *Api) respondMatchResult(ctx *gin.Context, request *types.MatchResultRequest) {
+	match, err := a.stor.SubmitMatchResult(request)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrMatchResultNotSaved, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": match.(*types.Match)})
+}
+
+//processes POST JSON body like {"tournament_id":1,"round":2,"results":[{"match_id":7,"winner_id":2,"score1":0,"score2":1}]}
+//recording results of matches of the round at once, next swiss round is paired as the round finishes;
+//responds 400 on invalid request or error, 200 with full Bracket as "data" otherwise
+func (a *Api) submitRoundResults(ctx *gin.Context) {
+	var parsedRequestBody types.RoundResultsRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondRoundResults(ctx, &parsedRequestBody)
+}
+
+//POST /tournaments/:id/rounds/:round/results with JSON body like {"results":[{"match_id":7,"score1":1,"score2":1}]}
+//responds 400 on incorrect id or round, invalid request or error, 200 with full Bracket as "data" otherwise
+func (a *Api) createRoundResultsV1(ctx *gin.Context) {
+	var parsedRequestBody types.TournamentRoundResultsRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	round, ok := a.pathId(ctx, "round")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondRoundResults(ctx, parsedRequestBody.RoundResultsRequest(id, int(round)))
+}
+
+func (a *Api) respondRoundResults(ctx *gin.Context, request *types.RoundResultsRequest) {
+	bracket, err := a.stor.SubmitRoundResults(request)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrRoundResultsNotSaved, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": bracket.(*types.Bracket)})
+}
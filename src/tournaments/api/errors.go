@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+var httpStatuses = map[int]int{
+	types.ERROR_KIND_INVALID_REQUEST: http.StatusBadRequest,
+	types.ERROR_KIND_NOT_FOUND:       http.StatusNotFound,
+	types.ERROR_KIND_REJECTED:        http.StatusBadRequest,
+	types.ERROR_KIND_FORBIDDEN:       http.StatusForbidden,
+}
+
+//Logs storage error err && responds with status matching opErr kind
+//and opErr message as "error", err itself if it's an OperationError; its code if any as "code"
+func (a *Api) abortWithOperationError(ctx *gin.Context, opErr *types.OperationError, err error) {
+	a.logger.Println(err.Error())
+	opErr = types.PublicError(err, opErr)
+	body := gin.H{"error": opErr.Message}
+	if opErr.Code != "" {
+		body["code"] = opErr.Code
+	}
+	ctx.AbortWithStatusJSON(httpStatuses[opErr.Kind], body)
+}
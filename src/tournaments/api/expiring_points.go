@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Seek by HTTP query "id" param, see respondBalanceLots
+func (a *Api) getUserExpiringPoints(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	a.respondBalanceLots(ctx, uint(id))
+}
+
+//GET /users/:id/expiring-points, see respondBalanceLots
+func (a *Api) getUserExpiringPointsV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondBalanceLots(ctx, id)
+}
+
+//Responds with lots of user wallet in "currency" HTTP query param (points by default)
+//having points not spent or expired yet, expiring soonest first;
+//responds 400 on incorrect "currency", 200 with list of BalanceLot as "data" otherwise
+func (a *Api) respondBalanceLots(ctx *gin.Context, userId uint) {
+	params := &queryParams{ctx: ctx}
+	currency := params.currency("currency", types.CURRENCY_POINTS)
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	lots, err := a.stor.FetchBalanceLots(userId, currency)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrBalanceNotFound, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": lots.([]*types.BalanceLot)})
+}
+
+//Seek by HTTP query "id" param, see respondNotifications
+func (a *Api) getUserNotifications(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	a.respondNotifications(ctx, uint(id))
+}
+
+//GET /users/:id/notifications, see respondNotifications
+func (a *Api) getUserNotificationsV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondNotifications(ctx, id)
+}
+
+//Responds with user notifications, newest first, paged by "limit" and "offset" HTTP query params;
+//responds 400 on incorrect params listing every invalid one
+func (a *Api) respondNotifications(ctx *gin.Context, userId uint) {
+	params := &queryParams{ctx: ctx}
+	query := &types.NotificationsQuery{
+		UserId: userId,
+		Limit:  params.limit(),
+		Offset: params.int("offset", 0),
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	found, err := a.stor.FetchNotifications(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrNotificationsNotFound, err)
+		return
+	}
+	page := found.(*types.NotificationsPage)
+	ctx.JSON(http.StatusOK, &types.NotificationsResponse{
+		Data: page.Notifications,
+		Meta: &types.PageMeta{Total: page.Total},
+	})
+}
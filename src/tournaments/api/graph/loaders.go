@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"sync"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Batches storage lookups by keys to avoid N+1 queries.
+// Keys are primed when parent resolvers are created, so the first load
+// fetches every primed key with a single storage call
+type batchLoader struct {
+	mu      sync.Mutex
+	primed  map[uint]bool
+	results map[uint]interface{}
+	fetch   func([]uint) (map[uint]interface{}, error)
+}
+
+func newBatchLoader(fetch func([]uint) (map[uint]interface{}, error)) *batchLoader {
+	return &batchLoader{
+		primed:  map[uint]bool{},
+		results: map[uint]interface{}{},
+		fetch:   fetch,
+	}
+}
+
+func (l *batchLoader) prime(keys ...uint) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		if _, ok := l.results[key]; !ok {
+			l.primed[key] = true
+		}
+	}
+}
+
+func (l *batchLoader) load(key uint) (interface{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if result, ok := l.results[key]; ok {
+		return result, nil
+	}
+	l.primed[key] = true
+	keys := make([]uint, 0, len(l.primed))
+	for k := range l.primed {
+		keys = append(keys, k)
+	}
+	fetched, err := l.fetch(keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		l.results[k] = fetched[k]
+		delete(l.primed, k)
+	}
+	return l.results[key], nil
+}
+
+type loaders struct {
+	players  *batchLoader
+	backers  *batchLoader
+	winners  *batchLoader
+	balances *batchLoader
+}
+
+func newLoaders(stor types.ApiStorage) *loaders {
+	return &loaders{
+		players: newBatchLoader(func(tournamentIds []uint) (map[uint]interface{}, error) {
+			fetched, err := stor.FetchTournamentPlayers(tournamentIds)
+			if err != nil {
+				return nil, err
+			}
+			grouped := map[uint][]*types.TournamentPlayer{}
+			for _, player := range fetched.([]*types.TournamentPlayer) {
+				grouped[player.TournamentId] = append(grouped[player.TournamentId], player)
+			}
+			results := map[uint]interface{}{}
+			for _, id := range tournamentIds {
+				results[id] = grouped[id]
+			}
+			return results, nil
+		}),
+		backers: newBatchLoader(func(tournamentIds []uint) (map[uint]interface{}, error) {
+			fetched, err := stor.FetchTournamentBackers(tournamentIds)
+			if err != nil {
+				return nil, err
+			}
+			grouped := map[uint][]*types.TournamentBacker{}
+			for _, backer := range fetched.([]*types.TournamentBacker) {
+				grouped[backer.TournamentId] = append(grouped[backer.TournamentId], backer)
+			}
+			results := map[uint]interface{}{}
+			for _, id := range tournamentIds {
+				results[id] = grouped[id]
+			}
+			return results, nil
+		}),
+		winners: newBatchLoader(func(tournamentIds []uint) (map[uint]interface{}, error) {
+			fetched, err := stor.FetchTournamentWinners(tournamentIds)
+			if err != nil {
+				return nil, err
+			}
+			grouped := map[uint][]*types.TournamentWinner{}
+			for _, winner := range fetched.([]*types.TournamentWinner) {
+				grouped[winner.TournamentId] = append(grouped[winner.TournamentId], winner)
+			}
+			results := map[uint]interface{}{}
+			for _, id := range tournamentIds {
+				results[id] = grouped[id]
+			}
+			return results, nil
+		}),
+		balances: newBatchLoader(func(userIds []uint) (map[uint]interface{}, error) {
+			fetched, err := stor.FetchBalances(userIds)
+			if err != nil {
+				return nil, err
+			}
+			grouped := map[uint][]*types.UserPointsBalance{}
+			for _, balance := range fetched.([]*types.UserPointsBalance) {
+				grouped[balance.UserId] = append(grouped[balance.UserId], balance)
+			}
+			results := map[uint]interface{}{}
+			for _, id := range userIds {
+				results[id] = grouped[id]
+			}
+			return results, nil
+		}),
+	}
+}
@@ -0,0 +1,594 @@
+package graph
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+type rootResolver struct {
+	stor   types.ApiStorage
+	logger *log.Logger
+}
+
+//Logs storage error err && returns opErr to be reported to client, err itself if it's an OperationError
+func (r *rootResolver) operationError(opErr *types.OperationError, err error) error {
+	r.logger.Println(err.Error())
+	return types.PublicError(err, opErr)
+}
+
+func (r *rootResolver) validate(request interface{}) error {
+	if errs := types.Validate(request); errs != nil {
+		return r.validationError(errs)
+	}
+	return nil
+}
+
+func (r *rootResolver) validationError(errs types.ValidationErrors) error {
+	return r.operationError(&types.OperationError{
+		Kind:    types.ErrRequestValidationFailed.Kind,
+		Message: types.ErrRequestValidationFailed.Message + ": " + errs.Error(),
+	}, errs)
+}
+
+func parseId(id graphql.ID) uint {
+	parsed, err := strconv.ParseUint(string(id), 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint(parsed)
+}
+
+func formatId(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+func (r *rootResolver) Tournament(ctx context.Context, args struct{ ID graphql.ID }) (*tournamentResolver, error) {
+	tournament, err := r.stor.FetchTournament(parseId(args.ID))
+	if err != nil {
+		r.logger.Println(err.Error())
+		return nil, nil
+	}
+	return newTournamentResolvers(loadersFrom(ctx), tournament.(*types.Tournament))[0], nil
+}
+
+func (r *rootResolver) Tournaments(ctx context.Context, args struct {
+	Limit  int32
+	Offset int32
+}) ([]*tournamentResolver, error) {
+	tournaments, err := r.stor.FetchTournaments(types.PageLimit(int(args.Limit)), int(args.Offset))
+	if err != nil {
+		r.logger.Println(err.Error())
+		return []*tournamentResolver{}, nil
+	}
+	return newTournamentResolvers(loadersFrom(ctx), tournaments.([]*types.Tournament)...), nil
+}
+
+func (r *rootResolver) Balance(ctx context.Context, args struct {
+	UserId   graphql.ID
+	Currency *string
+}) (*balanceResolver, error) {
+	currency := types.CURRENCY_POINTS
+	if args.Currency != nil {
+		currency = *args.Currency
+	}
+	if !types.IsCurrency(currency) {
+		return nil, r.validationError(types.ValidationErrors{
+			&types.FieldError{Field: "currency", Message: "must be one of " + strings.Join(types.Currencies, ", ")},
+		})
+	}
+	balance, err := loadBalance(loadersFrom(ctx), parseId(args.UserId), currency)
+	if err != nil {
+		return nil, r.operationError(types.ErrBalanceNotFound, err)
+	}
+	return balance, nil
+}
+
+func (r *rootResolver) Wallets(ctx context.Context, args struct{ UserId graphql.ID }) ([]*balanceResolver, error) {
+	loaded, err := loadersFrom(ctx).balances.load(parseId(args.UserId))
+	if err != nil {
+		return nil, r.operationError(types.ErrBalanceNotFound, err)
+	}
+	resolvers := []*balanceResolver{}
+	for _, balance := range loaded.([]*types.UserPointsBalance) {
+		resolvers = append(resolvers, newBalanceResolver(balance))
+	}
+	return resolvers, nil
+}
+
+func (r *rootResolver) AnnounceTournament(ctx context.Context, args struct {
+	Date            *graphql.Time
+	Deposit         int32
+	GameId          *int32
+	MaxPlayers      *int32
+	Currency        *string
+	SponsorId       *graphql.ID
+	PrizePool       *int32
+	GuaranteedPool  *int32
+	Format          *string
+	MinRating       *float64
+	MaxRating       *float64
+	InvitedUserIds  *[]graphql.ID
+	InvitationCodes *int32
+	QualifierId     *graphql.ID
+
+	TicketTournamentId *graphql.ID
+	Tickets            *int32
+	TicketRefundable   *bool
+	TeamSize           *int32
+}) (*tournamentResolver, error) {
+	request := &types.AnnounceTournamentRequest{Deposit: int(args.Deposit)}
+	if args.Currency != nil {
+		request.Currency = *args.Currency
+	}
+	if args.Date != nil {
+		request.Date = args.Date.Time
+	}
+	if args.GameId != nil {
+		request.GameId = int(*args.GameId)
+	}
+	if args.MaxPlayers != nil {
+		request.MaxPlayers = int(*args.MaxPlayers)
+	}
+	if args.SponsorId != nil {
+		request.SponsorId = parseId(*args.SponsorId)
+	}
+	if args.PrizePool != nil {
+		request.PrizePool = int(*args.PrizePool)
+	}
+	if args.GuaranteedPool != nil {
+		request.GuaranteedPool = int(*args.GuaranteedPool)
+	}
+	if args.Format != nil {
+		request.Format = *args.Format
+	}
+	if args.MinRating != nil {
+		request.MinRating = *args.MinRating
+	}
+	if args.MaxRating != nil {
+		request.MaxRating = *args.MaxRating
+	}
+	if args.InvitedUserIds != nil {
+		for _, userId := range *args.InvitedUserIds {
+			request.InvitedUserIds = append(request.InvitedUserIds, parseId(userId))
+		}
+	}
+	if args.InvitationCodes != nil {
+		request.InvitationCodes = int(*args.InvitationCodes)
+	}
+	if args.QualifierId != nil {
+		request.QualifierId = parseId(*args.QualifierId)
+	}
+	if args.TicketTournamentId != nil {
+		request.TicketTournamentId = parseId(*args.TicketTournamentId)
+	}
+	if args.Tickets != nil {
+		request.Tickets = int(*args.Tickets)
+	}
+	if args.TicketRefundable != nil {
+		request.TicketRefundable = *args.TicketRefundable
+	}
+	if args.TeamSize != nil {
+		request.TeamSize = int(*args.TeamSize)
+	}
+	if err := r.validate(request); err != nil {
+		return nil, err
+	}
+	if request.Date.IsZero() {
+		request.Date = time.Now()
+	}
+	tournament, err := r.stor.CreateNewTournament(request)
+	if err != nil {
+		return nil, r.operationError(types.ErrTournamentNotAnnounced, err)
+	}
+	return newTournamentResolvers(loadersFrom(ctx), tournament.(*types.Tournament))[0], nil
+}
+
+func (r *rootResolver) JoinTournament(ctx context.Context, args struct {
+	TournamentId   graphql.ID
+	PlayerId       graphql.ID
+	BackerIds      *[]graphql.ID
+	InvitationCode *string
+	TicketId       *graphql.ID
+}) (*tournamentResolver, error) {
+	request := &types.JoinTournamentRequest{
+		TournamentId: parseId(args.TournamentId),
+		PlayerId:     parseId(args.PlayerId),
+	}
+	if args.InvitationCode != nil {
+		request.InvitationCode = *args.InvitationCode
+	}
+	if args.TicketId != nil {
+		request.TicketId = parseId(*args.TicketId)
+	}
+	if args.BackerIds != nil {
+		for _, backerId := range *args.BackerIds {
+			request.BackerIds = append(request.BackerIds, parseId(backerId))
+		}
+	}
+	if err := r.validate(request); err != nil {
+		return nil, err
+	}
+	if err := r.stor.JoinTournamentAndTakePointsFromUserBalances(request); err != nil {
+		return nil, r.operationError(types.ErrTournamentNotJoined, err)
+	}
+	return r.fetchTournament(ctx, request.TournamentId)
+}
+
+func (r *rootResolver) ResultTournament(ctx context.Context, args struct {
+	TournamentId graphql.ID
+	Winners      []*struct {
+		PlayerId *graphql.ID
+		TeamId   *graphql.ID
+		Prize    int32
+		Ticket   *bool
+	}
+}) (*tournamentResolver, error) {
+	request := &types.ResultTournamentRequest{TournamentId: parseId(args.TournamentId)}
+	for _, winner := range args.Winners {
+		winnerRequest := &types.TournamentWinnerRequest{
+			Prize:  int(winner.Prize),
+			Ticket: winner.Ticket != nil && *winner.Ticket,
+		}
+		if winner.PlayerId != nil {
+			winnerRequest.PlayerId = parseId(*winner.PlayerId)
+		}
+		if winner.TeamId != nil {
+			winnerRequest.TeamId = parseId(*winner.TeamId)
+		}
+		request.Winners = append(request.Winners, winnerRequest)
+	}
+	if err := r.validate(request); err != nil {
+		return nil, err
+	}
+	if err := r.stor.CheckAndSpreadTournamentPrize(request); err != nil {
+		return nil, r.operationError(types.ErrTournamentResultNotSaved, err)
+	}
+	return r.fetchTournament(ctx, request.TournamentId)
+}
+
+func (r *rootResolver) FundUser(ctx context.Context, args struct {
+	PlayerId      graphql.ID
+	Points        int32
+	Currency      *string
+	ExpiresInDays *int32
+}) (*balanceResolver, error) {
+	request := &types.BalanceOperationRequest{PlayerId: parseId(args.PlayerId), Points: int(args.Points)}
+	if args.Currency != nil {
+		request.Currency = *args.Currency
+	}
+	if args.ExpiresInDays != nil {
+		request.ExpiresInDays = int(*args.ExpiresInDays)
+	}
+	if err := r.validate(request); err != nil {
+		return nil, err
+	}
+	balance, err := r.stor.TopUpBalance(request.PlayerId, types.CurrencyOrDefault(request.Currency), request.Points, request.ExpiresAt())
+	if err != nil {
+		return nil, r.operationError(types.ErrBalanceNotReplenished, err)
+	}
+	return newBalanceResolver(balance.(*types.UserPointsBalance)), nil
+}
+
+func (r *rootResolver) TakeFromUser(ctx context.Context, args struct {
+	PlayerId graphql.ID
+	Points   int32
+	Currency *string
+}) (*balanceResolver, error) {
+	request := &types.BalanceOperationRequest{PlayerId: parseId(args.PlayerId), Points: int(args.Points)}
+	if args.Currency != nil {
+		request.Currency = *args.Currency
+	}
+	if err := r.validate(request); err != nil {
+		return nil, err
+	}
+	balance, err := r.stor.TakeAwayBalance(request.PlayerId, types.CurrencyOrDefault(request.Currency), request.Points)
+	if err != nil {
+		return nil, r.operationError(types.ErrBalanceNotTakenAway, err)
+	}
+	return newBalanceResolver(balance.(*types.UserPointsBalance)), nil
+}
+
+func (r *rootResolver) fetchTournament(ctx context.Context, id uint) (*tournamentResolver, error) {
+	tournament, err := r.stor.FetchTournament(id)
+	if err != nil {
+		return nil, r.operationError(types.ErrTournamentNotFound, err)
+	}
+	return newTournamentResolvers(loadersFrom(ctx), tournament.(*types.Tournament))[0], nil
+}
+
+type tournamentResolver struct {
+	loaders    *loaders
+	tournament *types.Tournament
+}
+
+//Creates resolvers priming loaders by tournaments IDs,
+//so players, backers && winners of all of them are fetched at once
+func newTournamentResolvers(l *loaders, tournaments ...*types.Tournament) []*tournamentResolver {
+	resolvers := make([]*tournamentResolver, 0, len(tournaments))
+	for _, tournament := range tournaments {
+		l.players.prime(tournament.ID)
+		l.backers.prime(tournament.ID)
+		l.winners.prime(tournament.ID)
+		resolvers = append(resolvers, &tournamentResolver{loaders: l, tournament: tournament})
+	}
+	return resolvers
+}
+
+func (r *tournamentResolver) ID() graphql.ID {
+	return formatId(r.tournament.ID)
+}
+
+func (r *tournamentResolver) Date() graphql.Time {
+	return graphql.Time{Time: r.tournament.Date}
+}
+
+func (r *tournamentResolver) Deposit() int32 {
+	return int32(r.tournament.Deposit)
+}
+
+func (r *tournamentResolver) GameId() int32 {
+	return int32(r.tournament.GameId)
+}
+
+func (r *tournamentResolver) State() int32 {
+	return int32(r.tournament.State)
+}
+
+func (r *tournamentResolver) MaxPlayers() int32 {
+	return int32(r.tournament.MaxPlayers)
+}
+
+func (r *tournamentResolver) Currency() string {
+	return r.tournament.Currency
+}
+
+func (r *tournamentResolver) SponsorId() *graphql.ID {
+	if r.tournament.SponsorId == 0 {
+		return nil
+	}
+	id := formatId(r.tournament.SponsorId)
+	return &id
+}
+
+func (r *tournamentResolver) PrizePool() int32 {
+	return int32(r.tournament.PrizePool)
+}
+
+func (r *tournamentResolver) PrizePoolReturned() int32 {
+	return int32(r.tournament.PrizePoolReturned)
+}
+
+func (r *tournamentResolver) GuaranteedPool() int32 {
+	return int32(r.tournament.GuaranteedPool)
+}
+
+func (r *tournamentResolver) Overlay() int32 {
+	return int32(r.tournament.Overlay)
+}
+
+func (r *tournamentResolver) Format() *string {
+	if r.tournament.Format == "" {
+		return nil
+	}
+	return &r.tournament.Format
+}
+
+func (r *tournamentResolver) MinRating() float64 {
+	return r.tournament.MinRating
+}
+
+func (r *tournamentResolver) MaxRating() float64 {
+	return r.tournament.MaxRating
+}
+
+func (r *tournamentResolver) InvitationOnly() bool {
+	return r.tournament.InvitationOnly
+}
+
+func (r *tournamentResolver) QualifierId() *graphql.ID {
+	if r.tournament.QualifierId == 0 {
+		return nil
+	}
+	id := formatId(r.tournament.QualifierId)
+	return &id
+}
+
+func (r *tournamentResolver) InvitationCodes() []string {
+	if r.tournament.InvitationCodes == nil {
+		return []string{}
+	}
+	return r.tournament.InvitationCodes
+}
+
+func (r *tournamentResolver) TicketTournamentId() *graphql.ID {
+	if r.tournament.TicketTournamentId == 0 {
+		return nil
+	}
+	id := formatId(r.tournament.TicketTournamentId)
+	return &id
+}
+
+func (r *tournamentResolver) Tickets() int32 {
+	return int32(r.tournament.Tickets)
+}
+
+func (r *tournamentResolver) TicketRefundable() bool {
+	return r.tournament.TicketRefundable
+}
+
+func (r *tournamentResolver) TeamSize() int32 {
+	return int32(r.tournament.TeamSize)
+}
+
+func (r *tournamentResolver) Players() ([]*playerResolver, error) {
+	loaded, err := r.loaders.players.load(r.tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := []*playerResolver{}
+	for _, player := range loaded.([]*types.TournamentPlayer) {
+		r.loaders.balances.prime(player.UserId)
+		resolvers = append(resolvers, &playerResolver{loaders: r.loaders, player: player, currency: r.tournament.Currency})
+	}
+	return resolvers, nil
+}
+
+func (r *tournamentResolver) Winners() ([]*winnerResolver, error) {
+	loaded, err := r.loaders.winners.load(r.tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := []*winnerResolver{}
+	for _, winner := range loaded.([]*types.TournamentWinner) {
+		r.loaders.balances.prime(winner.UserId)
+		resolvers = append(resolvers, &winnerResolver{loaders: r.loaders, winner: winner, currency: r.tournament.Currency})
+	}
+	return resolvers, nil
+}
+
+type playerResolver struct {
+	loaders *loaders
+	player  *types.TournamentPlayer
+	// Tournament currency, balance is the wallet of it
+	currency string
+}
+
+func (r *playerResolver) UserId() graphql.ID {
+	return formatId(r.player.UserId)
+}
+
+func (r *playerResolver) Deposit() int32 {
+	return int32(r.player.UserDeposit)
+}
+
+func (r *playerResolver) Balance() (*balanceResolver, error) {
+	return loadBalance(r.loaders, r.player.UserId, r.currency)
+}
+
+func (r *playerResolver) Backers() ([]*backerResolver, error) {
+	loaded, err := r.loaders.backers.load(r.player.TournamentId)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := []*backerResolver{}
+	for _, backer := range loaded.([]*types.TournamentBacker) {
+		if backer.UserId != r.player.UserId {
+			continue
+		}
+		r.loaders.balances.prime(backer.BackerId)
+		resolvers = append(resolvers, &backerResolver{loaders: r.loaders, backer: backer, currency: r.currency})
+	}
+	return resolvers, nil
+}
+
+type backerResolver struct {
+	loaders  *loaders
+	backer   *types.TournamentBacker
+	currency string
+}
+
+func (r *backerResolver) BackerId() graphql.ID {
+	return formatId(r.backer.BackerId)
+}
+
+func (r *backerResolver) PlayerId() graphql.ID {
+	return formatId(r.backer.UserId)
+}
+
+func (r *backerResolver) Deposit() int32 {
+	return int32(r.backer.BackerDeposit)
+}
+
+func (r *backerResolver) Balance() (*balanceResolver, error) {
+	return loadBalance(r.loaders, r.backer.BackerId, r.currency)
+}
+
+type winnerResolver struct {
+	loaders  *loaders
+	winner   *types.TournamentWinner
+	currency string
+}
+
+func (r *winnerResolver) UserId() graphql.ID {
+	return formatId(r.winner.UserId)
+}
+
+func (r *winnerResolver) Prize() int32 {
+	return int32(r.winner.Prize)
+}
+
+func (r *winnerResolver) TicketId() *graphql.ID {
+	if r.winner.TicketId == 0 {
+		return nil
+	}
+	id := formatId(r.winner.TicketId)
+	return &id
+}
+
+func (r *winnerResolver) TeamId() *graphql.ID {
+	if r.winner.TeamId == 0 {
+		return nil
+	}
+	id := formatId(r.winner.TeamId)
+	return &id
+}
+
+func (r *winnerResolver) Balance() (*balanceResolver, error) {
+	return loadBalance(r.loaders, r.winner.UserId, r.currency)
+}
+
+type balanceResolver struct {
+	balance *types.UserPointsBalance
+}
+
+func newBalanceResolver(balance *types.UserPointsBalance) *balanceResolver {
+	if balance == nil {
+		return nil
+	}
+	return &balanceResolver{balance: balance}
+}
+
+//Picks user wallet of currency among every wallet loaded for user, nil if user has no such wallet
+func loadBalance(l *loaders, userId uint, currency string) (*balanceResolver, error) {
+	loaded, err := l.balances.load(userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, balance := range loaded.([]*types.UserPointsBalance) {
+		if balance.Currency == currency {
+			return newBalanceResolver(balance), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *balanceResolver) UserId() graphql.ID {
+	return formatId(r.balance.UserId)
+}
+
+func (r *balanceResolver) Currency() string {
+	return r.balance.Currency
+}
+
+func (r *balanceResolver) Balance() int32 {
+	return int32(r.balance.Balance)
+}
+
+func (r *balanceResolver) Held() int32 {
+	return int32(r.balance.Held)
+}
+
+func (r *balanceResolver) Available() int32 {
+	return int32(r.balance.Balance - r.balance.Held)
+}
+
+func (r *balanceResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.balance.UpdatedAt}
+}
@@ -0,0 +1,146 @@
+// Copyright 2018 h.lazar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+/*
+	GraphQL schema over tournaments, their players, backers, winners
+	and stakeholders' balances backed by types.ApiStorage
+*/
+package graph
+
+import (
+	"context"
+	"log"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+const SCHEMA = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+scalar Time
+
+type Query {
+	tournament(id: ID!): Tournament
+	tournaments(limit: Int = 20, offset: Int = 0): [Tournament!]!
+	# Wallet of given currency, points by default
+	balance(userId: ID!, currency: String): UserPointsBalance
+	wallets(userId: ID!): [UserPointsBalance!]!
+}
+
+type Mutation {
+	# Sponsor's wallet is debited by prizePool at once, deposit could be 0 for sponsored (free-roll) tournaments
+	# Invited users and invitation codes make tournament invitation-only, generated codes are returned as invitationCodes
+	# ticketTournamentId makes satellite awarding tickets to the tournament
+	# teamSize makes tournament entered by teams of so many members
+	announceTournament(date: Time, deposit: Int!, gameId: Int, maxPlayers: Int, currency: String, sponsorId: ID, prizePool: Int, guaranteedPool: Int, format: String, minRating: Float, maxRating: Float, invitedUserIds: [ID!], invitationCodes: Int, qualifierId: ID, ticketTournamentId: ID, tickets: Int, ticketRefundable: Boolean, teamSize: Int): Tournament!
+	# Players not qualifying get an error with "code" extension, ticketId pays the deposit instead of balances
+	joinTournament(tournamentId: ID!, playerId: ID!, backerIds: [ID!], invitationCode: String, ticketId: ID): Tournament!
+	resultTournament(tournamentId: ID!, winners: [WinnerInput!]!): Tournament!
+	# Funded points expire in expiresInDays unless spent by then if it's given
+	fundUser(playerId: ID!, points: Int!, currency: String, expiresInDays: Int): UserPointsBalance!
+	takeFromUser(playerId: ID!, points: Int!, currency: String): UserPointsBalance!
+}
+
+input WinnerInput {
+	# One of playerId and teamId is required, the latter by team tournaments
+	playerId: ID
+	teamId: ID
+	prize: Int!
+	# Winner of satellite gets a ticket
+	ticket: Boolean
+}
+
+type Tournament {
+	id: ID!
+	date: Time!
+	deposit: Int!
+	gameId: Int!
+	state: Int!
+	maxPlayers: Int!
+	# Currency of deposits and prizes, stakeholders' balances are wallets of it
+	currency: String!
+	# Null if tournament is not sponsored
+	sponsorId: ID
+	prizePool: Int!
+	# Part of prize pool returned to sponsor
+	prizePoolReturned: Int!
+	# Prizes add up to it at least, 0 if nothing is guaranteed
+	guaranteedPool: Int!
+	# Shortfall of guaranteed pool funded by the house
+	overlay: Int!
+	# single_elimination, double_elimination, round_robin or swiss, null if results are submitted as a flat winners list
+	format: String
+	# Bounds of player's rating in tournament game, 0 means unbounded
+	minRating: Float!
+	maxRating: Float!
+	invitationOnly: Boolean!
+	# Only winners of the qualifier may join, null if there is none
+	qualifierId: ID
+	# Codes of invitations generated at announcement, returned by announceTournament only
+	invitationCodes: [String!]!
+	# Satellite awards tickets to the tournament to its top places, null if tournament awards points only
+	ticketTournamentId: ID
+	tickets: Int!
+	# Unused tickets are refunded to points as they expire
+	ticketRefundable: Boolean!
+	# Entries are teams of so many members, 0 for individual entries
+	teamSize: Int!
+	players: [TournamentPlayer!]!
+	winners: [TournamentWinner!]!
+}
+
+type TournamentPlayer {
+	userId: ID!
+	deposit: Int!
+	balance: UserPointsBalance
+	backers: [TournamentBacker!]!
+}
+
+type TournamentBacker {
+	backerId: ID!
+	playerId: ID!
+	deposit: Int!
+	balance: UserPointsBalance
+}
+
+type TournamentWinner {
+	userId: ID!
+	prize: Int!
+	# Ticket won in satellite, null if there is none
+	ticketId: ID
+	# Team of member getting its share of team prize, null for individual winners
+	teamId: ID
+	balance: UserPointsBalance
+}
+
+type UserPointsBalance {
+	userId: ID!
+	currency: String!
+	balance: Int!
+	# Part of balance held for tournaments which registration is not closed yet
+	held: Int!
+	available: Int!
+	updatedAt: Time!
+}
+`
+
+type loadersKey struct{}
+
+func NewSchema(stor types.ApiStorage, logger *log.Logger) (*graphql.Schema, error) {
+	return graphql.ParseSchema(SCHEMA, &rootResolver{stor: stor, logger: logger})
+}
+
+//Returns ctx carrying fresh per-request batch loaders,
+//every query must be executed with such a context
+func WithLoaders(ctx context.Context, stor types.ApiStorage) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders(stor))
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
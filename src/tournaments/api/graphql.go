@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/graph"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//processes POST JSON body like {"query":"{ tournament(id: 1) { players { userId backers { backerId } } } }"}
+//requires "query" field, accepts "operationName", "variables",
+//responds 400 on invalid request, 200 with GraphQL "data" and "errors" otherwise
+func (a *Api) postGraphql(ctx *gin.Context) {
+	var parsedRequestBody types.GraphqlRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	response := a.schema.Exec(
+		graph.WithLoaders(ctx.Request.Context(), a.stor),
+		parsedRequestBody.Query,
+		parsedRequestBody.OperationName,
+		parsedRequestBody.Variables,
+	)
+	ctx.JSON(http.StatusOK, response)
+}
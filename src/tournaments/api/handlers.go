@@ -1,9 +1,7 @@
 package api
 
 import (
-	"encoding/json"
 	"errors"
-	"io/ioutil"
 	"net/http"
 	"strconv"
 	"time"
@@ -29,50 +27,36 @@ func (a *Api) getTournamentInfo(ctx *gin.Context) {
 	}
 	tournament, err := a.stor.FetchTournament(uint(intId))
 	if err != nil {
-		a.logger.Println(err.Error())
-		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Tournament not found"})
+		a.abortWithOperationError(ctx, types.ErrTournamentNotFound, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"data": tournament.(*types.Tournament)})
 }
 
-//Fetch tournaments list
-//accepts "limit" and "offset" HTTP query params
-//responds 404 on absent records,
-//200 with full Tournaments list as "data" otherwise
-func (a *Api) getTournaments(ctx *gin.Context) {
-	var (
-		intLimit  int
-		intOffset int
-		err       error
-	)
-	limit := ctx.DefaultQuery("limit", "20")
-	intLimit, err = strconv.Atoi(limit)
-	if err != nil {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect limit provided"))
-		a.logger.Println(err.Error())
+//Seek by HTTP query "id" param
+//responds 400 on empty id, 404 on absent record,
+//200 with TournamentDetails (players, backers, teams, winners, payouts) as "data" otherwise
+func (a *Api) getTournamentDetails(ctx *gin.Context) {
+	id := ctx.Query("id")
+	if id == "" {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
 		return
 	}
-	offset := ctx.DefaultQuery("offset", "0")
-	intOffset, err = strconv.Atoi(offset)
+	intId, err := strconv.Atoi(id)
 	if err != nil {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect offset provided"))
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
 		a.logger.Println(err.Error())
 		return
 	}
-
-	tournaments, err := a.stor.FetchTournaments(intLimit, intOffset)
+	details, err := a.stor.FetchTournamentDetails(uint(intId))
 	if err != nil {
-		a.logger.Println(err.Error())
-		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Tournaments not found"})
+		a.abortWithOperationError(ctx, types.ErrTournamentNotFound, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"data": tournaments.([]*types.Tournament)})
+	ctx.JSON(http.StatusOK, gin.H{"data": details.(*types.TournamentDetails)})
 }
 
-//Seek by HTTP query "id" param
-//responds 400 on empty id, 404 on absent record,
-//200 with full UserPointsBalance as "data" otherwise
+//Seek by HTTP query "id" param, see respondBalance
 func (a *Api) getUserBalance(ctx *gin.Context) {
 	id := ctx.Query("id")
 	if id == "" {
@@ -85,121 +69,72 @@ func (a *Api) getUserBalance(ctx *gin.Context) {
 		a.logger.Println(err.Error())
 		return
 	}
-	balance, err := a.stor.FetchBalance(uint(intId))
-	if err != nil {
-		a.logger.Println(err.Error())
-		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Balance not found"})
+	a.respondBalance(ctx, uint(intId))
+}
+
+//Seek by HTTP query "id" param, see respondWallets
+func (a *Api) getUserWallets(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"data": balance.(*types.UserPointsBalance)})
+	a.respondWallets(ctx, uint(id))
 }
 
-//processes POST JSON body like {"player_id":1,"points":100}
-//requires "player_id", "points" fields,
-//responds 500 on error, 200 with full UserPointsBalance otherwise
+//processes POST JSON body like {"player_id":1,"points":100}, {"player_id":1,"points":100,"currency":"bonus"}
+//requires "player_id", "points" fields, takes from points wallet unless "currency" given,
+//responds 400 on invalid request, 404 on error, 200 with full UserPointsBalance otherwise
 func (a *Api) takePointsFromUser(ctx *gin.Context) {
 	var parsedRequestBody types.BalanceOperationRequest
-	data, err := ioutil.ReadAll(ctx.Request.Body)
-	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, errors.New("Could not read request body"))
-		a.logger.Println(err.Error())
+	if !a.bindRequest(ctx, &parsedRequestBody) {
 		return
 	}
-	if err := json.Unmarshal(data, &parsedRequestBody); err != nil {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect request body provided"))
-		a.logger.Println(err.Error())
-		return
-	}
-	playerId := parsedRequestBody.PlayerId
-	if playerId == 0 {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect player ID provided"))
-		return
-	}
-	points := parsedRequestBody.Points
-	if points <= 0 {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect points value provided"))
-		return
-	}
-	balance, err := a.stor.TakeAwayBalance(playerId, points)
+	balance, err := a.stor.TakeAwayBalance(parsedRequestBody.PlayerId, types.CurrencyOrDefault(parsedRequestBody.Currency), parsedRequestBody.Points)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Balance not taken away"})
-		a.logger.Println(err.Error())
+		a.abortWithOperationError(ctx, types.ErrBalanceNotTakenAway, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"data": balance.(*types.UserPointsBalance)})
 }
 
-//processes POST JSON body like {"player_id":1,"points":100}
-//requires "player_id", "points" fields,
-//responds 500 on error, 200 with full UserPointsBalance otherwise
+//processes POST JSON body like {"player_id":1,"points":100}, {"player_id":1,"points":100,"currency":"bonus","expires_in_days":30}
+//requires "player_id", "points" fields, funds points wallet unless "currency" given creating the wallet if absent,
+//funded points expire in "expires_in_days" unless spent by then if it's given,
+//responds 400 on invalid request, 404 on error, 200 with full UserPointsBalance otherwise
 func (a *Api) fundUserWithPoints(ctx *gin.Context) {
 	var parsedRequestBody types.BalanceOperationRequest
-	data, err := ioutil.ReadAll(ctx.Request.Body)
-	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, errors.New("Could not read request body: "+err.Error()))
-		a.logger.Println(err.Error())
-		return
-	}
-	if err := json.Unmarshal(data, &parsedRequestBody); err != nil {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect request body provided: "+err.Error()))
-		a.logger.Println(err.Error())
-		return
-	}
-	playerId := parsedRequestBody.PlayerId
-	if playerId == 0 {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect player ID provided"))
-		return
-	}
-	points := parsedRequestBody.Points
-	if points <= 0 {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect points value provided"))
+	if !a.bindRequest(ctx, &parsedRequestBody) {
 		return
 	}
-	balance, err := a.stor.TopUpBalance(playerId, points)
+	balance, err := a.stor.TopUpBalance(parsedRequestBody.PlayerId, types.CurrencyOrDefault(parsedRequestBody.Currency), parsedRequestBody.Points, parsedRequestBody.ExpiresAt())
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Balance not replenished"})
-		a.logger.Println(err.Error())
+		a.abortWithOperationError(ctx, types.ErrBalanceNotReplenished, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"data": balance.(*types.UserPointsBalance)})
 }
 
 //processes POST JSON body like {"deposit":100}, {"deposit":100,"game_id":1}, {"date":"2018-03-18T00:59:00Z","deposit":100,"game_id":1}
-//requires "deposit" field,
+//requires "deposit" field, which could be 0 for free-roll tournaments having "prize_pool" funded by "sponsor_id",
 //accepts "date" and "gameId", fills by default current date and 0 appropriately,
-//responds 500 on error, 200 with full Tournament otherwise
+//"format" of bracket lets results be submitted match by match,
+//"min_rating", "max_rating", "invited_user_ids", "invitation_codes" (number to generate) and "qualifier_id" restrict entries,
+//"ticket_tournament_id" makes satellite awarding "tickets" to the tournament, refunded as they expire if "ticket_refundable",
+//"team_size" makes tournament entered by teams of so many members, see joinTournamentByTeam,
+//responds 400 on invalid request or error, 200 with full Tournament otherwise
 func (a *Api) announceTournament(ctx *gin.Context) {
-	var (
-		parsedRequestBody types.AnnounceTournamentRequest
-		tournament        interface{}
-	)
-	data, err := ioutil.ReadAll(ctx.Request.Body)
-	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, errors.New("Could not read request body"))
-		a.logger.Println(err.Error())
-		return
-	}
-	if err := json.Unmarshal(data, &parsedRequestBody); err != nil {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect request body provided"))
-		a.logger.Println(err.Error())
-		return
-	}
-	if parsedRequestBody.Deposit <= 0 {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect tournament deposit provided"))
+	var parsedRequestBody types.AnnounceTournamentRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
 		return
 	}
-	// TODO(h.lazar) improve this check, pay attention to timezone
 	if parsedRequestBody.Date.IsZero() {
 		parsedRequestBody.Date = time.Now()
-	} else if parsedRequestBody.Date.Before(time.Now()) {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect tournament date provided"))
-		return
 	}
 
-	tournament, err = a.stor.CreateNewTournament(&parsedRequestBody)
+	tournament, err := a.stor.CreateNewTournament(&parsedRequestBody)
 	if err != nil {
-		a.logger.Println(err.Error())
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "could not announce tournament"})
+		a.abortWithOperationError(ctx, types.ErrTournamentNotAnnounced, err)
 		return
 	}
 
@@ -208,25 +143,16 @@ func (a *Api) announceTournament(ctx *gin.Context) {
 
 //processes POST JSON body like {"tournament_id"1,"player_id":2}, {"tournament_id"1,"player_id":2,"backer_ids":[3,4,5]}
 //requires "tournament_id", "player_id" fields,
-//accepts "backer_ids",
-//responds 500 on error, 204 otherwise
+//accepts "backer_ids" (unique, not containing "player_id"), "invitation_code" and "ticket_id" paying the deposit instead of balances,
+//responds 400 on invalid request or error, 403 with "code" if player doesn't qualify, 204 otherwise
 func (a *Api) joinTournament(ctx *gin.Context) {
 	var parsedRequestBody types.JoinTournamentRequest
-	data, err := ioutil.ReadAll(ctx.Request.Body)
-	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, errors.New("Could not read request body"))
-		a.logger.Println(err.Error())
-		return
-	}
-	if err := json.Unmarshal(data, &parsedRequestBody); err != nil {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect request body provided"))
-		a.logger.Println(err.Error())
+	if !a.bindRequest(ctx, &parsedRequestBody) {
 		return
 	}
-	err = a.stor.JoinTournamentAndTakePointsFromUserBalances(&parsedRequestBody)
+	err := a.stor.JoinTournamentAndTakePointsFromUserBalances(&parsedRequestBody)
 	if err != nil {
-		a.logger.Println(err.Error())
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "could not join tournament"})
+		a.abortWithOperationError(ctx, types.ErrTournamentNotJoined, err)
 		return
 	}
 
@@ -234,25 +160,18 @@ func (a *Api) joinTournament(ctx *gin.Context) {
 }
 
 //processes POST JSON body like {"tournament_id":1,"winners":[{"player_id":1,"prize":500}]}
-//requires "tournament_id", "winners" fields,
-//responds 500 on error, 204 otherwise
+//requires "tournament_id", non-empty "winners" with unique "player_id"s and non-negative "prize"s,
+//winners of team tournaments are given by "team_id" instead, their prizes are split between members by shares,
+//winners of satellite having "ticket" set get tickets,
+//responds 400 on invalid request or error, 204 otherwise
 func (a *Api) resultTournament(ctx *gin.Context) {
 	var parsedRequestBody types.ResultTournamentRequest
-	data, err := ioutil.ReadAll(ctx.Request.Body)
-	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, errors.New("Could not read request body"))
-		a.logger.Println(err.Error())
+	if !a.bindRequest(ctx, &parsedRequestBody) {
 		return
 	}
-	if err := json.Unmarshal(data, &parsedRequestBody); err != nil {
-		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect request body provided"))
-		a.logger.Println(err.Error())
-		return
-	}
-	err = a.stor.CheckAndSpreadTournamentPrize(&parsedRequestBody)
+	err := a.stor.CheckAndSpreadTournamentPrize(&parsedRequestBody)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Could not save tournament result"})
-		a.logger.Println(err.Error())
+		a.abortWithOperationError(ctx, types.ErrTournamentResultNotSaved, err)
 		return
 	}
 
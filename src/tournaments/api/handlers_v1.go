@@ -0,0 +1,160 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Parses positive integer path param,
+//responds 400 and returns false if it is incorrect
+func (a *Api) pathId(ctx *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(name), 10, 32)
+	if err != nil || id == 0 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Incorrect " + name + " provided"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+//GET /tournaments/:id
+//responds 400 on incorrect id, 404 on absent record,
+//200 with full Tournament as "data" otherwise
+func (a *Api) getTournamentV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	tournament, err := a.stor.FetchTournament(id)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrTournamentNotFound, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": tournament.(*types.Tournament)})
+}
+
+//GET /tournaments/:id/details
+//responds 400 on incorrect id, 404 on absent record,
+//200 with TournamentDetails (players, backers, teams, winners, payouts) as "data" otherwise
+func (a *Api) getTournamentDetailsV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	details, err := a.stor.FetchTournamentDetails(id)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrTournamentNotFound, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": details.(*types.TournamentDetails)})
+}
+
+//POST /tournaments with JSON body like {"date":"2018-03-18T00:59:00Z","deposit":100,"game_id":1}
+//requires "deposit" field, which could be 0 if "prize_pool" is funded by "sponsor_id", fills by default current date and game 1,
+//responds 400 on invalid request or error,
+//201 with full Tournament as "data" and its Location otherwise
+func (a *Api) createTournamentV1(ctx *gin.Context) {
+	var parsedRequestBody types.AnnounceTournamentRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	if parsedRequestBody.Date.IsZero() {
+		parsedRequestBody.Date = time.Now()
+	}
+	created, err := a.stor.CreateNewTournament(&parsedRequestBody)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrTournamentNotAnnounced, err)
+		return
+	}
+	tournament := created.(*types.Tournament)
+	ctx.Header("Location", a.conf.V1RelativePath+"/tournaments/"+strconv.Itoa(int(tournament.ID)))
+	ctx.JSON(http.StatusCreated, gin.H{"data": tournament})
+}
+
+//POST /tournaments/:id/entries with JSON body like {"player_id":2,"backer_ids":[3,4,5]}
+//requires "player_id" field, accepts "backer_ids", "invitation_code" and "ticket_id",
+//responds 400 on invalid request or error, 403 with "code" if player doesn't qualify, 204 otherwise
+func (a *Api) createTournamentEntryV1(ctx *gin.Context) {
+	var parsedRequestBody types.TournamentEntryRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	if err := a.stor.JoinTournamentAndTakePointsFromUserBalances(parsedRequestBody.JoinTournamentRequest(id)); err != nil {
+		a.abortWithOperationError(ctx, types.ErrTournamentNotJoined, err)
+		return
+	}
+	ctx.String(http.StatusNoContent, ``)
+}
+
+//POST /tournaments/:id/results with JSON body like {"winners":[{"player_id":1,"prize":500}]}
+//requires non-empty "winners", winners of team tournaments are given by "team_id" instead of "player_id",
+//winners of satellite having "ticket" set get tickets,
+//responds 400 on invalid request or error, 204 otherwise
+func (a *Api) createTournamentResultsV1(ctx *gin.Context) {
+	var parsedRequestBody types.TournamentResultsRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	if err := a.stor.CheckAndSpreadTournamentPrize(parsedRequestBody.ResultTournamentRequest(id)); err != nil {
+		a.abortWithOperationError(ctx, types.ErrTournamentResultNotSaved, err)
+		return
+	}
+	ctx.String(http.StatusNoContent, ``)
+}
+
+//GET /users/:id/balance, see respondBalance
+func (a *Api) getUserBalanceV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondBalance(ctx, id)
+}
+
+//GET /users/:id/wallets, see respondWallets
+func (a *Api) getUserWalletsV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondWallets(ctx, id)
+}
+
+//POST /users/:id/balance/top-ups with JSON body like {"points":100} or {"points":100,"currency":"bonus","expires_in_days":30}
+//responds 400 on invalid request, 404 on error,
+//200 with full UserPointsBalance as "data" otherwise
+func (a *Api) topUpUserBalanceV1(ctx *gin.Context) {
+	var parsedRequestBody types.PointsRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	balance, err := a.stor.TopUpBalance(id, types.CurrencyOrDefault(parsedRequestBody.Currency), parsedRequestBody.Points, parsedRequestBody.ExpiresAt())
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrBalanceNotReplenished, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": balance.(*types.UserPointsBalance)})
+}
+
+//POST /users/:id/balance/withdrawals with JSON body like {"points":100} or {"points":100,"currency":"bonus"}
+//responds 400 on invalid request, 404 on error,
+//200 with full UserPointsBalance as "data" otherwise
+func (a *Api) withdrawUserBalanceV1(ctx *gin.Context) {
+	var parsedRequestBody types.PointsRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	balance, err := a.stor.TakeAwayBalance(id, types.CurrencyOrDefault(parsedRequestBody.Currency), parsedRequestBody.Points)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrBalanceNotTakenAway, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": balance.(*types.UserPointsBalance)})
+}
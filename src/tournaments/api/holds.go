@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//processes POST JSON body like {"tournament_id":1,"player_id":2}
+//releases deposits held for player's entry, possible until registration closes;
+//responds 400 on invalid request or error, 204 otherwise
+func (a *Api) withdrawTournament(ctx *gin.Context) {
+	var parsedRequestBody types.WithdrawTournamentRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondWithdrawal(ctx, &parsedRequestBody)
+}
+
+//DELETE /tournaments/:id/entries/:player_id
+//responds 400 on incorrect ids or error, 204 otherwise
+func (a *Api) deleteTournamentEntryV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	playerId, ok := a.pathId(ctx, "player_id")
+	if !ok {
+		return
+	}
+	a.respondWithdrawal(ctx, &types.WithdrawTournamentRequest{TournamentId: id, PlayerId: playerId})
+}
+
+func (a *Api) respondWithdrawal(ctx *gin.Context, request *types.WithdrawTournamentRequest) {
+	if err := a.stor.WithdrawFromTournament(request); err != nil {
+		a.abortWithOperationError(ctx, types.ErrTournamentNotWithdrawn, err)
+		return
+	}
+	ctx.String(http.StatusNoContent, ``)
+}
+
+//processes POST JSON body like {"tournament_id":1}
+//debits every deposit held for tournament, no one could join it since then;
+//responds 400 on invalid request or error, 204 otherwise
+func (a *Api) closeTournamentRegistration(ctx *gin.Context) {
+	var parsedRequestBody types.TournamentIdRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondRegistrationClosure(ctx, parsedRequestBody.TournamentId)
+}
+
+//POST /tournaments/:id/registration-closure
+//responds 400 on incorrect id or error, 204 otherwise
+func (a *Api) createRegistrationClosureV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondRegistrationClosure(ctx, id)
+}
+
+func (a *Api) respondRegistrationClosure(ctx *gin.Context, tournamentId uint) {
+	if err := a.stor.CloseTournamentRegistration(tournamentId); err != nil {
+		a.abortWithOperationError(ctx, types.ErrRegistrationNotClosed, err)
+		return
+	}
+	ctx.String(http.StatusNoContent, ``)
+}
+
+//processes POST JSON body like {"tournament_id":1}
+//releases held deposits && refunds debited ones;
+//responds 400 on invalid request or error, 204 otherwise
+func (a *Api) cancelTournament(ctx *gin.Context) {
+	var parsedRequestBody types.TournamentIdRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondCancellation(ctx, parsedRequestBody.TournamentId)
+}
+
+//POST /tournaments/:id/cancellation
+//responds 400 on incorrect id or error, 204 otherwise
+func (a *Api) createCancellationV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondCancellation(ctx, id)
+}
+
+func (a *Api) respondCancellation(ctx *gin.Context, tournamentId uint) {
+	if err := a.stor.CancelTournament(tournamentId); err != nil {
+		a.abortWithOperationError(ctx, types.ErrTournamentNotCancelled, err)
+		return
+	}
+	ctx.String(http.StatusNoContent, ``)
+}
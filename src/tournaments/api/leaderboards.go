@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Ranked by HTTP query "metric" param, see respondLeaderboard
+func (a *Api) getLeaderboard(ctx *gin.Context) {
+	params := &queryParams{ctx: ctx}
+	a.respondLeaderboard(ctx, params, params.oneOf("metric", types.LEADERBOARD_METRIC_WINNINGS, types.LeaderboardMetrics))
+}
+
+//GET /leaderboards/:metric, see respondLeaderboard
+func (a *Api) getLeaderboardV1(ctx *gin.Context) {
+	params := &queryParams{ctx: ctx}
+	metric := ctx.Param("metric")
+	if !isLeaderboardMetric(metric) {
+		params.fail("metric", "must be one of "+strings.Join(types.LeaderboardMetrics, ", "))
+	}
+	a.respondLeaderboard(ctx, params, metric)
+}
+
+func isLeaderboardMetric(metric string) bool {
+	for _, known := range types.LeaderboardMetrics {
+		if metric == known {
+			return true
+		}
+	}
+	return false
+}
+
+//Responds with page of players ranked by metric in tournaments of game given by "game_id"
+//played within "window" or in tournaments of "season_id" HTTP query params, winnings && net profit are counted in "currency";
+//rank of player given by "user_id" is responded as "me" of "meta" wherever the player is;
+//responds 400 on incorrect params listing every invalid one
+func (a *Api) respondLeaderboard(ctx *gin.Context, params *queryParams, metric string) {
+	query := &types.LeaderboardQuery{
+		Metric:   metric,
+		GameId:   params.int("game_id", 0),
+		Window:   params.oneOf("window", types.LEADERBOARD_WINDOW_ALL, types.LeaderboardWindows),
+		SeasonId: uint(params.int("season_id", 0)),
+		Currency: params.currency("currency", ""),
+		UserId:   uint(params.int("user_id", 0)),
+		Limit:    params.limit(),
+		Offset:   params.int("offset", 0),
+	}
+	if query.SeasonId != 0 {
+		// Season has its own game && dates
+		for _, name := range []string{"game_id", "window"} {
+			if _, ok := ctx.GetQuery(name); ok {
+				params.fail(name, "is not allowed along with season_id")
+			}
+		}
+	}
+	if metric == types.LEADERBOARD_METRIC_RATING {
+		// Ratings are kept per game && are current ones only
+		if query.GameId == 0 {
+			params.fail("game_id", "is required for rating leaderboard")
+		}
+		if query.Window != types.LEADERBOARD_WINDOW_ALL {
+			params.fail("window", "must be "+types.LEADERBOARD_WINDOW_ALL+" for rating leaderboard")
+		}
+		if query.SeasonId != 0 {
+			params.fail("season_id", "is not allowed for rating leaderboard")
+		}
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	found, err := a.stor.FetchLeaderboard(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrLeaderboardNotFound, err)
+		return
+	}
+	page := found.(*types.LeaderboardPage)
+	ctx.JSON(http.StatusOK, &types.LeaderboardResponse{
+		Data: page.Entries,
+		Meta: &types.LeaderboardMeta{Total: page.Total, ComputedAt: page.ComputedAt, Me: page.Me},
+	})
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//GET /tournament/list, see respondTournaments;
+//"limit" is clamped to 1..100 silently as v0 clients used to pass any
+func (a *Api) getTournaments(ctx *gin.Context) {
+	params := &queryParams{ctx: ctx}
+	a.respondTournaments(ctx, params, params.clampedLimit())
+}
+
+//GET /tournaments, see respondTournaments; "limit" out of 1..100 is rejected
+func (a *Api) getTournamentsV1(ctx *gin.Context) {
+	params := &queryParams{ctx: ctx}
+	a.respondTournaments(ctx, params, params.limit())
+}
+
+//Fetch tournaments list
+//accepts filters "state", "game_id", "date_from", "date_to" (RFC3339), "has_free_seats", "player_id",
+//"sort" (one of id, date, deposit, created_at, "-" prefixed for descending order),
+//"limit" and either "cursor" (from "meta") or "offset" HTTP query params,
+//responds 400 on incorrect params listing every invalid one,
+//200 with Tournaments list as "data" (empty if nothing matches),
+//total count && cursors as "meta" and next/prev pages URLs as "links" otherwise
+func (a *Api) respondTournaments(ctx *gin.Context, params *queryParams, limit int) {
+	query := parseTournamentsQuery(params)
+	query.Limit = limit
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	found, err := a.stor.SearchTournaments(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrTournamentsNotFound, err)
+		return
+	}
+	page := found.(*types.TournamentsPage)
+	response := &types.TournamentsListResponse{
+		Data: page.Tournaments,
+		Meta: &types.PageMeta{
+			Total:      page.Total,
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
+		},
+		Links: &types.PageLinks{},
+	}
+	if page.NextCursor != "" {
+		response.Links.Next = pageUrl(ctx.Request.URL, page.NextCursor)
+	}
+	if page.PrevCursor != "" {
+		response.Links.Prev = pageUrl(ctx.Request.URL, page.PrevCursor)
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+func parseTournamentsQuery(params *queryParams) *types.TournamentsQuery {
+	var err error
+	query := &types.TournamentsQuery{
+		Offset:       params.int("offset", 0),
+		GameId:       params.int("game_id", 0),
+		PlayerId:     uint(params.int("player_id", 0)),
+		DateFrom:     params.time("date_from"),
+		DateTo:       params.time("date_to"),
+		HasFreeSeats: params.bool("has_free_seats"),
+		Sort:         params.ctx.DefaultQuery("sort", "id"),
+	}
+	if _, ok := params.ctx.GetQuery("state"); ok {
+		state := uint(params.int("state", 0))
+		query.State = &state
+	}
+	if _, ok := types.TournamentSortFields[strings.TrimPrefix(query.Sort, "-")]; !ok {
+		params.fail("sort", "must be one of id, date, deposit, created_at optionally prefixed by -")
+	}
+	if value, ok := params.ctx.GetQuery("cursor"); ok {
+		if query.Cursor, err = types.DecodeCursor(value); err != nil {
+			params.fail("cursor", err.Error())
+		} else if query.Cursor.Sort != query.Sort {
+			params.fail("cursor", "was issued for another sort")
+		}
+		if query.Offset > 0 {
+			params.fail("offset", "could not be combined with cursor")
+		}
+	}
+	return query
+}
+
+//Returns request URL with cursor param set to cursor and offset param removed
+func pageUrl(requestUrl *url.URL, cursor string) string {
+	params := requestUrl.Query()
+	params.Del("offset")
+	params.Set("cursor", cursor)
+	return requestUrl.Path + "?" + params.Encode()
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Records tournaments query of the last listing
+type listingStorage struct {
+	nopStorage
+	query *types.TournamentsQuery
+}
+
+func (s *listingStorage) SearchTournaments(query *types.TournamentsQuery) (interface{}, error) {
+	s.query = query
+	return &types.TournamentsPage{Tournaments: []*types.Tournament{}}, nil
+}
+
+func TestTournamentsListLimit(t *testing.T) {
+	cases := []struct {
+		name   string
+		url    string
+		status int
+		limit  int
+	}{
+		{"v0 default", "/tournament/v0/tournament/list", http.StatusOK, types.DEFAULT_PAGE_LIMIT},
+		{"v0 clamps too big limit", "/tournament/v0/tournament/list?limit=500", http.StatusOK, types.MAX_PAGE_LIMIT},
+		{"v0 clamps negative limit", "/tournament/v0/tournament/list?limit=-3", http.StatusOK, 1},
+		{"v0 rejects not a number", "/tournament/v0/tournament/list?limit=all", http.StatusBadRequest, 0},
+		{"v1 default", "/tournament/v1/tournaments", http.StatusOK, types.DEFAULT_PAGE_LIMIT},
+		{"v1 accepts limit in range", "/tournament/v1/tournaments?limit=50", http.StatusOK, 50},
+		{"v1 rejects too big limit", "/tournament/v1/tournaments?limit=500", http.StatusBadRequest, 0},
+		{"v1 rejects zero limit", "/tournament/v1/tournaments?limit=0", http.StatusBadRequest, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			storage := &listingStorage{}
+			a, err := NewApi(&ApiConf{RelativePath: "/tournament/v0", V1RelativePath: "/tournament/v1"}, storage, log.New(ioutil.Discard, "", 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			recorder := httptest.NewRecorder()
+			a.engine.ServeHTTP(recorder, httptest.NewRequest("GET", c.url, nil))
+			if recorder.Code != c.status {
+				t.Fatalf("got status %d, want %d: %s", recorder.Code, c.status, recorder.Body.String())
+			}
+			if c.status == http.StatusOK && (storage.query == nil || storage.query.Limit != c.limit) {
+				t.Errorf("got query %+v, want limit %d", storage.query, c.limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,986 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+const OPENAPI_VERSION = `3.0.0`
+const API_TITLE = `Game tournaments API`
+const API_VERSION = `0`
+
+type paramDoc struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Type        string
+}
+
+type operationDoc struct {
+	Summary     string
+	Params      []paramDoc
+	Request     interface{}
+	Response    interface{}
+	NoContent   bool
+	Created     bool
+	Unwrapped   bool // Response is not wrapped into "data"
+	ErrorStatus []int
+}
+
+var statementParams = []paramDoc{
+	currencyParam,
+	{Name: "kind", In: "query", Description: "Comma separated operation kinds: fund, take, join, prize, transfer, fee, refund, expire, voucher, sponsor, sponsor_return, overlay, season_reward, ticket_refund, correction, legacy", Type: "string"},
+	{Name: "date_from", In: "query", Description: "RFC3339 date operations made since, inclusive", Type: "string"},
+	{Name: "date_to", In: "query", Description: "RFC3339 date operations made until, exclusive", Type: "string"},
+	{Name: "tournament_id", In: "query", Description: "Tournament ID operations relate to", Type: "integer"},
+	{Name: "order", In: "query", Description: "desc (default) for newest operations first, asc for oldest first", Type: "string"},
+	{Name: "limit", In: "query", Description: "Page size, 20 by default, 100 at most", Type: "integer"},
+	{Name: "offset", In: "query", Description: "Page offset, 0 by default", Type: "integer"},
+	{Name: "format", In: "query", Description: "json (default), csv or jsonl; csv and jsonl export every matching operation", Type: "string"},
+}
+
+var transfersParams = []paramDoc{
+	{Name: "direction", In: "query", Description: "in for received transfers, out for sent ones, both by default", Type: "string"},
+	{Name: "limit", In: "query", Description: "Page size, 20 by default, 100 at most", Type: "integer"},
+	{Name: "offset", In: "query", Description: "Page offset, 0 by default", Type: "integer"},
+}
+
+var pageParams = []paramDoc{
+	{Name: "limit", In: "query", Description: "Page size, 20 by default, 100 at most", Type: "integer"},
+	{Name: "offset", In: "query", Description: "Page offset, 0 by default", Type: "integer"},
+}
+
+var asOfParam = paramDoc{Name: "as_of", In: "query", Description: "RFC3339 date, balance is made of operations made before it", Type: "string"}
+
+var currencyParam = paramDoc{Name: "currency", In: "query", Description: "Wallet currency: points (default), bonus or tokens", Type: "string"}
+
+var gameIdParam = paramDoc{Name: "game_id", In: "query", Description: "Game ID, any if omitted", Type: "integer"}
+
+var ticketStateParam = paramDoc{Name: "state", In: "query", Description: "issued, used, refunded or expired, any if omitted", Type: "string"}
+
+var leaderboardParams = append([]paramDoc{
+	{Name: "game_id", In: "query", Description: "Game ID, any if omitted, required for rating", Type: "integer"},
+	{Name: "window", In: "query", Description: "Tournaments played since the start of: all (default), week, month or season (calendar quarter), UTC", Type: "string"},
+	{Name: "season_id", In: "query", Description: "Counts tournaments of the season instead of game_id and window", Type: "integer"},
+	{Name: "currency", In: "query", Description: "Currency of winnings and net_profit: points (default), bonus or tokens", Type: "string"},
+	{Name: "user_id", In: "query", Description: "User whose rank is responded as meta.me", Type: "integer"},
+}, pageParams...)
+
+var balancesAsOfParams = []paramDoc{
+	{Name: "as_of", In: "query", Description: "RFC3339 date, balances are made of operations made before it; now by default", Type: "string"},
+	{Name: "currency", In: "query", Description: "Wallet currency: points, bonus or tokens; every wallet by default", Type: "string"},
+	{Name: "limit", In: "query", Description: "Page size, 20 by default, 100 at most", Type: "integer"},
+	{Name: "offset", In: "query", Description: "Page offset, 0 by default", Type: "integer"},
+}
+
+//Describes tournaments list of v0 && v1 differing by limit param only
+func tournamentsListDoc(limitDescription string) *operationDoc {
+	return &operationDoc{
+		Summary: "List tournaments",
+		Params: []paramDoc{
+			{Name: "state", In: "query", Description: "Tournament state, 0 for open, 1 for finished", Type: "integer"},
+			{Name: "game_id", In: "query", Description: "Game ID", Type: "integer"},
+			{Name: "date_from", In: "query", Description: "RFC3339 date tournaments start since, inclusive", Type: "string"},
+			{Name: "date_to", In: "query", Description: "RFC3339 date tournaments start until, exclusive", Type: "string"},
+			{Name: "has_free_seats", In: "query", Description: "Whether tournament could be joined by one more player, or one more team in team tournaments", Type: "boolean"},
+			{Name: "player_id", In: "query", Description: "ID of user joined tournaments as player or team member", Type: "integer"},
+			{Name: "sort", In: "query", Description: "One of id, date, deposit, created_at, prefixed by - for descending order; id by default", Type: "string"},
+			{Name: "limit", In: "query", Description: limitDescription, Type: "integer"},
+			{Name: "cursor", In: "query", Description: "Opaque cursor from meta.next_cursor or meta.prev_cursor of previous response", Type: "string"},
+			{Name: "offset", In: "query", Description: "Page offset if no cursor provided, 0 by default", Type: "integer"},
+		},
+		Response:    &types.TournamentsListResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	}
+}
+
+// Every route mounted by mountRoutes must be described here,
+// TestSpecMatchesRoutes fails otherwise.
+// Keys are like "GET /user/balance" relative to ApiConf.RelativePath
+var apiDocs = map[string]*operationDoc{
+	"GET /openapi.json": {
+		Summary:  "OpenAPI specification of this API",
+		Response: map[string]interface{}{},
+	},
+	"GET /docs": {
+		Summary:  "Human readable API documentation page",
+		Response: "",
+	},
+	"POST /graphql": {
+		Summary:     "GraphQL endpoint over tournaments, players, backers, winners and balances",
+		Request:     &types.GraphqlRequest{},
+		Response:    map[string]interface{}{},
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"GET /user/balance": {
+		Summary:     "Fetch user wallet balance, current or as of given date",
+		Params:      []paramDoc{{Name: "id", In: "query", Description: "User ID", Required: true, Type: "integer"}, currencyParam, asOfParam},
+		Response:    &types.UserPointsBalance{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /user/wallets": {
+		Summary:     "Fetch every wallet of user",
+		Params:      []paramDoc{{Name: "id", In: "query", Description: "User ID", Required: true, Type: "integer"}},
+		Response:    []*types.UserPointsBalance{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /user/expiringPoints": {
+		Summary:     "Fetch lots of user wallet having points to expire, expiring soonest first",
+		Params:      []paramDoc{{Name: "id", In: "query", Description: "User ID", Required: true, Type: "integer"}, currencyParam},
+		Response:    []*types.BalanceLot{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /user/notifications": {
+		Summary:     "Fetch user notifications, newest first",
+		Params:      append([]paramDoc{{Name: "id", In: "query", Description: "User ID", Required: true, Type: "integer"}}, pageParams...),
+		Response:    &types.NotificationsResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /user/balances": {
+		Summary:     "Fetch wallet balances of every user as of given date",
+		Params:      balancesAsOfParams,
+		Response:    &types.BalancesAsOfResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /user/statement": {
+		Summary:     "Fetch user balance operations with running balance",
+		Params:      append([]paramDoc{{Name: "id", In: "query", Description: "User ID", Required: true, Type: "integer"}}, statementParams...),
+		Response:    &types.StatementResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /user/take": {
+		Summary:     "Take points away from user balance",
+		Request:     &types.BalanceOperationRequest{},
+		Response:    &types.UserPointsBalance{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /user/fund": {
+		Summary:     "Fund user balance with points",
+		Request:     &types.BalanceOperationRequest{},
+		Response:    &types.UserPointsBalance{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /user/transfer": {
+		Summary:     "Transfer points between users charging the house fee from sender",
+		Request:     &types.TransferRequest{},
+		Response:    &types.Transfer{},
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"GET /user/transfers": {
+		Summary:     "Fetch transfers sent and received by user, newest first",
+		Params:      append([]paramDoc{{Name: "id", In: "query", Description: "User ID", Required: true, Type: "integer"}}, transfersParams...),
+		Response:    &types.TransfersResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /admin/transferLimit": {
+		Summary:     "Set user's daily transfers limit",
+		Params:      []paramDoc{adminTokenParam},
+		Request:     &types.TransferLimitRequest{},
+		Response:    &types.TransferLimit{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	"POST /user/redeemVoucher": {
+		Summary:     "Redeem voucher crediting its amount to user wallet of its currency",
+		Request:     &types.RedeemVoucherRequest{},
+		Response:    &types.VoucherRedemption{},
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /admin/voucher": {
+		Summary:     "Create voucher, its code is generated if omitted",
+		Params:      []paramDoc{adminTokenParam},
+		Request:     &types.VoucherRequest{},
+		Response:    &types.Voucher{},
+		Created:     true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	"POST /admin/voucherBatch": {
+		Summary:     "Generate batch of vouchers having codes with given prefix",
+		Params:      []paramDoc{adminTokenParam},
+		Request:     &types.VoucherBatchRequest{},
+		Response:    &types.VoucherBatch{},
+		Created:     true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	"GET /admin/voucherRedemptions": {
+		Summary: "Fetch voucher redemptions, newest first",
+		Params: append([]paramDoc{
+			adminTokenParam,
+			{Name: "voucher_id", In: "query", Description: "Voucher ID redemptions of", Type: "integer"},
+			{Name: "user_id", In: "query", Description: "User ID redemptions made by", Type: "integer"},
+		}, pageParams...),
+		Response:    &types.VoucherRedemptionsResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	},
+	"GET /admin/overlays": {
+		Summary: "Fetch finished tournaments having guaranteed prize pool with overlays funded by the house, newest first",
+		Params: append([]paramDoc{
+			adminTokenParam,
+			{Name: "currency", In: "query", Description: "Tournament currency: points, bonus or tokens, any if omitted", Type: "string"},
+			{Name: "overlaid_only", In: "query", Description: "Leave out tournaments guarantees of which were covered by deposits and prize pool", Type: "boolean"},
+		}, pageParams...),
+		Response:    &types.OverlaysResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound},
+	},
+	"GET /tournament/list": tournamentsListDoc("Page size, 20 by default, clamped to 1..100"),
+	"GET /tournament/info": {
+		Summary:     "Fetch tournament",
+		Params:      []paramDoc{{Name: "id", In: "query", Description: "Tournament ID", Required: true, Type: "integer"}},
+		Response:    &types.Tournament{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /tournament/details": {
+		Summary:     "Fetch tournament with players, backers, teams, winners and payouts",
+		Params:      []paramDoc{{Name: "id", In: "query", Description: "Tournament ID", Required: true, Type: "integer"}},
+		Response:    &types.TournamentDetails{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /tournament/announceTournament": {
+		Summary:     "Announce new tournament",
+		Request:     &types.AnnounceTournamentRequest{},
+		Response:    &types.Tournament{},
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournament/joinTournament": {
+		Summary:     "Join tournament holding deposit on player and backers balances or paying it by ticket, responds 403 with \"code\" if player doesn't qualify",
+		Request:     &types.JoinTournamentRequest{},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	"POST /tournament/withdrawTournament": {
+		Summary:     "Withdraw player's entry releasing deposits held on player's and backers' balances",
+		Request:     &types.WithdrawTournamentRequest{},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournament/joinTournamentByTeam": {
+		Summary:     "Join team tournament by team holding deposit on members' balances by their shares, responds 403 with \"code\" if a member doesn't qualify",
+		Request:     &types.TeamEntryRequest{},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	"POST /tournament/withdrawTeam": {
+		Summary:     "Withdraw team entry releasing deposits held on members' balances",
+		Request:     &types.TeamEntryRequest{},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /team/create": {
+		Summary:     "Create team of members paying team entry deposits and getting team prizes by their shares (percents)",
+		Request:     &types.TeamRequest{},
+		Response:    &types.Team{},
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"GET /team/info": {
+		Summary:     "Fetch team with its members",
+		Params:      []paramDoc{{Name: "id", In: "query", Description: "Team ID", Required: true, Type: "integer"}},
+		Response:    &types.Team{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /tournament/closeRegistration": {
+		Summary:     "Close tournament registration debiting every held deposit",
+		Request:     &types.TournamentIdRequest{},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournament/cancelTournament": {
+		Summary:     "Cancel tournament releasing held deposits and refunding debited ones",
+		Request:     &types.TournamentIdRequest{},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournament/resultTournament": {
+		Summary:     "Finish tournament spreading prizes between winners and their backers",
+		Request:     &types.ResultTournamentRequest{},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournament/generateBracket": {
+		Summary:     "Generate bracket of tournament format closing its registration, prizes of places are paid as bracket completes",
+		Request:     &types.BracketRequest{},
+		Response:    &types.Bracket{},
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"GET /tournament/bracket": {
+		Summary:     "Fetch tournament bracket with prizes, matches and standings",
+		Params:      []paramDoc{{Name: "id", In: "query", Description: "Tournament ID", Required: true, Type: "integer"}},
+		Response:    &types.Bracket{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /tournament/matchResult": {
+		Summary:     "Record result of ready match advancing its players, the last one completes bracket paying prizes of places",
+		Request:     &types.MatchResultRequest{},
+		Response:    &types.Match{},
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournament/roundResults": {
+		Summary:     "Record results of matches of a round at once, the next swiss round is paired as the round finishes",
+		Request:     &types.RoundResultsRequest{},
+		Response:    &types.Bracket{},
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"GET /user/ratings": {
+		Summary: "Fetch user's Glicko-2 ratings in games, best first",
+		Params: []paramDoc{
+			{Name: "id", In: "query", Description: "User ID", Required: true, Type: "integer"},
+			gameIdParam,
+		},
+		Response:    []*types.Rating{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /user/ratingHistory": {
+		Summary:     "Fetch changes of user's ratings by tournaments, newest first",
+		Params:      append([]paramDoc{{Name: "id", In: "query", Description: "User ID", Required: true, Type: "integer"}, gameIdParam}, pageParams...),
+		Response:    &types.RatingHistoryResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /user/leaderboard": {
+		Summary:     "Fetch page of players ranked by winnings, net profit, tournaments won or rating, cached for a minute",
+		Params:      append([]paramDoc{{Name: "metric", In: "query", Description: "winnings (default), net_profit, tournaments_won or rating", Type: "string"}}, leaderboardParams...),
+		Response:    &types.LeaderboardResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /user/tickets": {
+		Summary:     "Fetch page of user's satellite tickets, newest first",
+		Params:      append([]paramDoc{{Name: "id", In: "query", Description: "User ID", Required: true, Type: "integer"}, ticketStateParam}, pageParams...),
+		Response:    &types.TicketsResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /tournament/seasons": {
+		Summary:     "Fetch page of seasons, latest starting first",
+		Params:      append([]paramDoc{gameIdParam}, pageParams...),
+		Response:    &types.SeasonsResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /tournament/season": {
+		Summary:     "Fetch season with points of tournament places and rewards of season places",
+		Params:      []paramDoc{{Name: "id", In: "query", Description: "Season ID", Required: true, Type: "integer"}},
+		Response:    &types.Season{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /tournament/seasonStandings": {
+		Summary:     "Fetch page of season standings by points, best first, with rewards paid as season ends",
+		Params:      append([]paramDoc{{Name: "id", In: "query", Description: "Season ID", Required: true, Type: "integer"}}, pageParams...),
+		Response:    &types.SeasonStandingsResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /admin/season": {
+		Summary:     "Create season of a game, its rewards are paid from the house as it ends",
+		Params:      []paramDoc{adminTokenParam},
+		Request:     &types.SeasonRequest{},
+		Response:    &types.Season{},
+		Created:     true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	"POST /admin/recomputeRatings": {
+		Summary:     "Rebuild every rating and its history from finished tournaments",
+		Params:      []paramDoc{adminTokenParam},
+		Response:    &types.RatingsRecomputation{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	"POST /admin/reconcile": {
+		Summary:     "Check balances against operations ledger, tournaments' escrow and orphan rows, optionally writing correction operations for drifted balances",
+		Params:      []paramDoc{adminTokenParam},
+		Request:     &types.ReconcileRequest{},
+		Response:    &types.ReconciliationReport{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+}
+
+// Same as apiDocs for mountV1Routes, keys are relative to ApiConf.V1RelativePath
+var apiV1Docs = map[string]*operationDoc{
+	"GET /openapi.json": apiDocs["GET /openapi.json"],
+	"GET /docs":         apiDocs["GET /docs"],
+	"GET /balances":     apiDocs["GET /user/balances"],
+	"GET /leaderboards/:metric": {
+		Summary:     "Fetch page of players ranked by winnings, net profit, tournaments won or rating, cached for a minute",
+		Params:      append([]paramDoc{{Name: "metric", In: "path", Description: "winnings, net_profit, tournaments_won or rating", Type: "string"}}, leaderboardParams...),
+		Response:    &types.LeaderboardResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /users/:id/balance": {
+		Summary:     "Fetch user wallet balance, current or as of given date",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}, currencyParam, asOfParam},
+		Response:    &types.UserPointsBalance{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /users/:id/wallets": {
+		Summary:     "Fetch every wallet of user",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}},
+		Response:    []*types.UserPointsBalance{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /users/:id/expiring-points": {
+		Summary:     "Fetch lots of user wallet having points to expire, expiring soonest first",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}, currencyParam},
+		Response:    []*types.BalanceLot{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /users/:id/notifications": {
+		Summary:     "Fetch user notifications, newest first",
+		Params:      append([]paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}}, pageParams...),
+		Response:    &types.NotificationsResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /users/:id/statement": {
+		Summary:     "Fetch user balance operations with running balance",
+		Params:      append([]paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}}, statementParams...),
+		Response:    &types.StatementResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /users/:id/balance/top-ups": {
+		Summary:     "Fund user balance with points",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}},
+		Request:     &types.PointsRequest{},
+		Response:    &types.UserPointsBalance{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /users/:id/balance/withdrawals": {
+		Summary:     "Take points away from user balance",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}},
+		Request:     &types.PointsRequest{},
+		Response:    &types.UserPointsBalance{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /users/:id/transfers": {
+		Summary:     "Fetch transfers sent and received by user, newest first",
+		Params:      append([]paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}}, transfersParams...),
+		Response:    &types.TransfersResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /users/:id/transfers": {
+		Summary:     "Transfer points to another user charging the house fee",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Sender user ID", Type: "integer"}},
+		Request:     &types.UserTransferRequest{},
+		Response:    &types.Transfer{},
+		Created:     true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"PUT /admin/users/:id/transfer-limit": {
+		Summary:     "Set user's daily transfers limit",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}, adminTokenParam},
+		Request:     &types.UserTransferLimitRequest{},
+		Response:    &types.TransferLimit{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	"GET /tournaments": tournamentsListDoc("Page size, 20 by default, 100 at most"),
+	"POST /tournaments": {
+		Summary:     "Announce new tournament",
+		Request:     &types.AnnounceTournamentRequest{},
+		Response:    &types.Tournament{},
+		Created:     true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"GET /tournaments/:id": {
+		Summary:     "Fetch tournament",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"}},
+		Response:    &types.Tournament{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /tournaments/:id/details": {
+		Summary:     "Fetch tournament with players, backers, teams, winners and payouts",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"}},
+		Response:    &types.TournamentDetails{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /tournaments/:id/entries": {
+		Summary:     "Join tournament holding deposit on player and backers balances or paying it by ticket, responds 403 with \"code\" if player doesn't qualify",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"}},
+		Request:     &types.TournamentEntryRequest{},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	"POST /tournaments/:id/team-entries": {
+		Summary:     "Join team tournament by team holding deposit on members' balances by their shares, responds 403 with \"code\" if a member doesn't qualify",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"}},
+		Request:     &types.TournamentTeamEntryRequest{},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusForbidden},
+	},
+	"DELETE /tournaments/:id/team-entries/:team_id": {
+		Summary: "Withdraw team entry releasing deposits held on members' balances",
+		Params: []paramDoc{
+			{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"},
+			{Name: "team_id", In: "path", Description: "Team ID", Type: "integer"},
+		},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /teams": {
+		Summary:     "Create team of members paying team entry deposits and getting team prizes by their shares (percents)",
+		Request:     &types.TeamRequest{},
+		Response:    &types.Team{},
+		Created:     true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"GET /teams/:id": {
+		Summary:     "Fetch team with its members",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Team ID", Type: "integer"}},
+		Response:    &types.Team{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"DELETE /tournaments/:id/entries/:player_id": {
+		Summary: "Withdraw player's entry releasing deposits held on player's and backers' balances",
+		Params: []paramDoc{
+			{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"},
+			{Name: "player_id", In: "path", Description: "Player user ID", Type: "integer"},
+		},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournaments/:id/registration-closure": {
+		Summary:     "Close tournament registration debiting every held deposit",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"}},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournaments/:id/cancellation": {
+		Summary:     "Cancel tournament releasing held deposits and refunding debited ones",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"}},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournaments/:id/results": {
+		Summary:     "Finish tournament spreading prizes between winners and their backers",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"}},
+		Request:     &types.TournamentResultsRequest{},
+		NoContent:   true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournaments/:id/bracket": {
+		Summary:     "Generate bracket of tournament format closing its registration, prizes of places are paid as bracket completes",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"}},
+		Request:     &types.TournamentBracketRequest{},
+		Response:    &types.Bracket{},
+		Created:     true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"GET /tournaments/:id/bracket": {
+		Summary:     "Fetch tournament bracket with prizes, matches and standings",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"}},
+		Response:    &types.Bracket{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"PUT /tournaments/:id/matches/:match_id/result": {
+		Summary: "Record result of ready match advancing its players, the last one completes bracket paying prizes of places",
+		Params: []paramDoc{
+			{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"},
+			{Name: "match_id", In: "path", Description: "Match ID", Type: "integer"},
+		},
+		Request:     &types.MatchScoreRequest{},
+		Response:    &types.Match{},
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"POST /tournaments/:id/rounds/:round/results": {
+		Summary: "Record results of matches of a round at once, the next swiss round is paired as the round finishes",
+		Params: []paramDoc{
+			{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"},
+			{Name: "round", In: "path", Description: "Round number starting from 1", Type: "integer"},
+		},
+		Request:     &types.TournamentRoundResultsRequest{},
+		Response:    &types.Bracket{},
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+	"GET /users/:id/ratings": {
+		Summary: "Fetch user's Glicko-2 ratings in games, best first",
+		Params: []paramDoc{
+			{Name: "id", In: "path", Description: "User ID", Type: "integer"},
+			gameIdParam,
+		},
+		Response:    []*types.Rating{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /users/:id/rating-history": {
+		Summary:     "Fetch changes of user's ratings by tournaments, newest first",
+		Params:      append([]paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}, gameIdParam}, pageParams...),
+		Response:    &types.RatingHistoryResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /users/:id/tickets": {
+		Summary:     "Fetch page of user's satellite tickets, newest first",
+		Params:      append([]paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}, ticketStateParam}, pageParams...),
+		Response:    &types.TicketsResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /seasons": apiDocs["GET /tournament/seasons"],
+	"GET /seasons/:id": {
+		Summary:     "Fetch season with points of tournament places and rewards of season places",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "Season ID", Type: "integer"}},
+		Response:    &types.Season{},
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"GET /seasons/:id/standings": {
+		Summary:     "Fetch page of season standings by points, best first, with rewards paid as season ends",
+		Params:      append([]paramDoc{{Name: "id", In: "path", Description: "Season ID", Type: "integer"}}, pageParams...),
+		Response:    &types.SeasonStandingsResponse{},
+		Unwrapped:   true,
+		ErrorStatus: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	"POST /admin/rating-recomputations": apiDocs["POST /admin/recomputeRatings"],
+	"POST /admin/seasons":               apiDocs["POST /admin/season"],
+	"POST /admin/reconciliations":       apiDocs["POST /admin/reconcile"],
+	"POST /admin/vouchers":              apiDocs["POST /admin/voucher"],
+	"POST /admin/voucher-batches":       apiDocs["POST /admin/voucherBatch"],
+	"GET /admin/voucher-redemptions":    apiDocs["GET /admin/voucherRedemptions"],
+	"GET /admin/overlays":               apiDocs["GET /admin/overlays"],
+	"POST /users/:id/voucher-redemptions": {
+		Summary:     "Redeem voucher crediting its amount to user wallet of its currency",
+		Params:      []paramDoc{{Name: "id", In: "path", Description: "User ID", Type: "integer"}},
+		Request:     &types.UserRedeemVoucherRequest{},
+		Response:    &types.VoucherRedemption{},
+		Created:     true,
+		ErrorStatus: []int{http.StatusBadRequest},
+	},
+}
+
+// Route docs of every mounted API version keyed by its base path
+func (a *Api) versionDocs() map[string]map[string]*operationDoc {
+	return map[string]map[string]*operationDoc{
+		a.conf.RelativePath:   apiDocs,
+		a.conf.V1RelativePath: apiV1Docs,
+	}
+}
+
+//Builds OpenAPI 3 document from apiDocs,
+//request && response schemas are generated from api/types by reflection
+func (a *Api) buildSpec() map[string]interface{} {
+	schemas := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"error": map[string]interface{}{"type": "string"},
+				// Machine-readable reason of some failures, like player not qualifying for tournament entry
+				"code":   map[string]interface{}{"type": "string"},
+				"fields": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/components/schemas/FieldError"}},
+			},
+		},
+	}
+	schemas["FieldError"] = schemaOf(reflect.TypeOf(types.FieldError{}), schemas)
+
+	paths := map[string]interface{}{}
+	for _, route := range a.engine.Routes() {
+		doc := a.routeDoc(route.Method, route.Path)
+		if doc == nil {
+			doc = &operationDoc{Summary: "Not documented yet, handled by " + route.Handler}
+		}
+		path := specPath(route.Path)
+		pathItem, ok := paths[path].(map[string]interface{})
+		if !ok {
+			pathItem = map[string]interface{}{}
+			paths[path] = pathItem
+		}
+		pathItem[strings.ToLower(route.Method)] = doc.withPathParams(route.Path).operation(schemas)
+	}
+
+	return map[string]interface{}{
+		"openapi": OPENAPI_VERSION,
+		"info": map[string]interface{}{
+			"title":   API_TITLE,
+			"version": API_VERSION,
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+//Finds description of mounted route in versionDocs, nil if it's not described
+func (a *Api) routeDoc(method string, path string) *operationDoc {
+	for basePath, docs := range a.versionDocs() {
+		if strings.HasPrefix(path, basePath+"/") {
+			if doc, ok := docs[method+" "+strings.TrimPrefix(path, basePath)]; ok {
+				return doc
+			}
+		}
+	}
+	return nil
+}
+
+//Returns copy of doc describing every path param of gin route path not described by doc itself
+func (doc *operationDoc) withPathParams(path string) *operationDoc {
+	described := map[string]bool{}
+	for _, p := range doc.Params {
+		described[p.Name] = true
+	}
+	withParams := *doc
+	withParams.Params = append([]paramDoc{}, doc.Params...)
+	for _, part := range strings.Split(path, "/") {
+		if (strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*")) && !described[part[1:]] {
+			withParams.Params = append(withParams.Params, paramDoc{Name: part[1:], In: "path", Description: "Path parameter", Type: "string"})
+		}
+	}
+	return &withParams
+}
+
+func (doc *operationDoc) operation(schemas map[string]interface{}) map[string]interface{} {
+	operation := map[string]interface{}{"summary": doc.Summary}
+	if len(doc.Params) > 0 {
+		params := []interface{}{}
+		for _, p := range doc.Params {
+			params = append(params, map[string]interface{}{
+				"name":        p.Name,
+				"in":          p.In,
+				"description": p.Description,
+				"required":    p.Required || p.In == "path",
+				"schema":      map[string]interface{}{"type": p.Type},
+			})
+		}
+		operation["parameters"] = params
+	}
+	if doc.Request != nil {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schemaOf(reflect.TypeOf(doc.Request), schemas)},
+			},
+		}
+	}
+	responses := map[string]interface{}{}
+	if doc.NoContent {
+		responses[strconv.Itoa(http.StatusNoContent)] = map[string]interface{}{"description": "Success"}
+	} else if doc.Created {
+		responses[strconv.Itoa(http.StatusCreated)] = map[string]interface{}{
+			"description": "Created",
+			"content":     responseContent(doc.Response, schemas),
+		}
+	} else if doc.Unwrapped {
+		responses[strconv.Itoa(http.StatusOK)] = map[string]interface{}{
+			"description": "Success",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schemaOf(reflect.TypeOf(doc.Response), schemas)},
+			},
+		}
+	} else {
+		responses[strconv.Itoa(http.StatusOK)] = map[string]interface{}{
+			"description": "Success",
+			"content":     responseContent(doc.Response, schemas),
+		}
+	}
+	for _, status := range doc.ErrorStatus {
+		responses[strconv.Itoa(status)] = map[string]interface{}{
+			"description": http.StatusText(status),
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"}},
+			},
+		}
+	}
+	operation["responses"] = responses
+	return operation
+}
+
+func responseContent(response interface{}, schemas map[string]interface{}) map[string]interface{} {
+	switch response.(type) {
+	case string:
+		return map[string]interface{}{"text/html": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}}
+	case map[string]interface{}:
+		return map[string]interface{}{"application/json": map[string]interface{}{"schema": map[string]interface{}{"type": "object"}}}
+	}
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{
+			"schema": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"data": schemaOf(reflect.TypeOf(response), schemas)},
+			},
+		},
+	}
+}
+
+//Generates JSON schema of t, named structs are put into schemas && referenced
+func schemaOf(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+		if _, ok := schemas[t.Name()]; ok {
+			return ref
+		}
+		// Placeholder prevents endless recursion on self-referencing types
+		schemas[t.Name()] = map[string]interface{}{}
+		schemas[t.Name()] = structSchema(t, schemas)
+		return ref
+	}
+	return map[string]interface{}{}
+}
+
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		if field.Anonymous && jsonTag == "" {
+			// Embedded struct fields are flattened the same way encoding/json does
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			embeddedSchema := structSchema(embedded, schemas)
+			for name, property := range embeddedSchema["properties"].(map[string]interface{}) {
+				properties[name] = property
+			}
+			if embeddedRequired, ok := embeddedSchema["required"].([]string); ok {
+				required = append(required, embeddedRequired...)
+			}
+			continue
+		}
+		name := strings.Split(jsonTag, ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		property := schemaOf(field.Type, schemas)
+		for _, rule := range strings.Split(field.Tag.Get(types.VALIDATION_TAG), ",") {
+			ruleParts := strings.SplitN(rule, "=", 2)
+			switch ruleParts[0] {
+			case "required":
+				required = append(required, name)
+			case "min", "max":
+				limit, _ := strconv.Atoi(ruleParts[1])
+				key := map[string]string{"min": "minimum", "max": "maximum"}[ruleParts[0]]
+				if property["type"] == "array" {
+					key = map[string]string{"min": "minItems", "max": "maxItems"}[ruleParts[0]]
+				}
+				if property["type"] == "string" {
+					key = map[string]string{"min": "minLength", "max": "maxLength"}[ruleParts[0]]
+				}
+				property = withKey(property, key, limit)
+			case "oneof":
+				property = withKey(property, "enum", strings.Split(ruleParts[1], "|"))
+			case "unique":
+				property = withKey(property, "uniqueItems", true)
+			}
+		}
+		properties[name] = property
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+func withKey(schema map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	copied := map[string]interface{}{key: value}
+	for k, v := range schema {
+		copied[k] = v
+	}
+	return copied
+}
+
+//Converts gin path params like "/:id" to OpenAPI ones like "/{id}"
+func specPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+//responds 200 with OpenAPI 3 document of this API
+func (a *Api) getOpenApiSpec(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, a.spec)
+}
+
+//responds 200 with self-contained HTML page rendering openapi.json
+func (a *Api) getApiDocs(ctx *gin.Context) {
+	specUrl := strings.TrimSuffix(ctx.Request.URL.Path, "/docs") + "/openapi.json"
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(fmt.Sprintf(API_DOCS_PAGE, API_TITLE, specUrl)))
+}
+
+const API_DOCS_PAGE = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+.op { border: 1px solid #ccc; border-radius: 4px; margin: 1em 0; padding: .5em 1em; }
+.method { font-weight: bold; text-transform: uppercase; display: inline-block; width: 5em; }
+pre { background: #f6f6f6; padding: .5em; overflow: auto; }
+</style>
+</head>
+<body>
+<h1 id="title"></h1>
+<div id="ops"></div>
+<script>
+fetch(%q).then(function (r) { return r.json(); }).then(function (spec) {
+  document.getElementById("title").textContent = spec.info.title + " v" + spec.info.version;
+  var ops = document.getElementById("ops");
+  var resolve = function (s) {
+    if (s && s.$ref) { return resolve(spec.components.schemas[s.$ref.split("/").pop()]); }
+    return s;
+  };
+  Object.keys(spec.paths).sort().forEach(function (path) {
+    Object.keys(spec.paths[path]).forEach(function (method) {
+      var op = spec.paths[path][method];
+      var div = document.createElement("div");
+      div.className = "op";
+      var html = "<p><span class=\"method\">" + method + "</span><code>" + path + "</code> " + op.summary + "</p>";
+      (op.parameters || []).forEach(function (p) {
+        html += "<p><code>" + p.name + "</code> (" + p.in + (p.required ? ", required" : "") + ") " + p.description + "</p>";
+      });
+      if (op.requestBody) {
+        html += "<p>Request body</p><pre>" + JSON.stringify(resolve(op.requestBody.content["application/json"].schema), null, 2) + "</pre>";
+      }
+      html += "<p>Responses: " + Object.keys(op.responses).join(", ") + "</p>";
+      div.innerHTML = html;
+      ops.appendChild(div);
+    });
+  });
+});
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+type nopStorage struct{ types.ApiStorage }
+
+func newTestApi(t *testing.T) *Api {
+	a, err := NewApi(&ApiConf{RelativePath: "/tournament/v0", V1RelativePath: "/tournament/v1"}, &nopStorage{}, log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestSpecMatchesRoutes(t *testing.T) {
+	a := newTestApi(t)
+	mounted := map[string]bool{}
+	for _, route := range a.engine.Routes() {
+		mounted[route.Method+" "+route.Path] = true
+	}
+	documented := map[string]bool{}
+	for basePath, docs := range a.versionDocs() {
+		for key := range docs {
+			parts := strings.SplitN(key, " ", 2)
+			documented[parts[0]+" "+basePath+parts[1]] = true
+		}
+	}
+	problems := []string{}
+	for key := range mounted {
+		if !documented[key] {
+			problems = append(problems, "undocumented route "+key)
+		}
+	}
+	for key := range documented {
+		if !mounted[key] {
+			problems = append(problems, "documented route "+key+" is not mounted")
+		}
+	}
+	sort.Strings(problems)
+	for _, problem := range problems {
+		t.Error(problem)
+	}
+}
+
+func TestSpecPath(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"/tournament/v0/user/balance", "/tournament/v0/user/balance"},
+		{"/tournament/v1/tournaments/:id", "/tournament/v1/tournaments/{id}"},
+		{"/tournament/v1/tournaments/:id/entries/:player_id", "/tournament/v1/tournaments/{id}/entries/{player_id}"},
+		{"/static/*filepath", "/static/{filepath}"},
+	}
+	for _, c := range cases {
+		if actual := specPath(c.path); actual != c.expected {
+			t.Errorf("specPath(%q) = %q, expected %q", c.path, actual, c.expected)
+		}
+	}
+}
+
+func TestSpecDescribesPathParams(t *testing.T) {
+	doc := (&operationDoc{Summary: "Entry"}).withPathParams("/tournaments/:id/entries/:player_id")
+	if len(doc.Params) != 2 || doc.Params[0].Name != "id" || doc.Params[1].Name != "player_id" || doc.Params[1].In != "path" {
+		t.Fatalf("unexpected params %+v", doc.Params)
+	}
+	described := &operationDoc{Params: []paramDoc{{Name: "id", In: "path", Description: "Tournament ID", Type: "integer"}}}
+	if doc := described.withPathParams("/tournaments/:id"); len(doc.Params) != 1 || doc.Params[0].Type != "integer" {
+		t.Fatalf("described param is duplicated or changed: %+v", doc.Params)
+	}
+	if len(described.Params) != 1 {
+		t.Fatal("original doc is changed")
+	}
+}
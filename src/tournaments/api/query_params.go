@@ -0,0 +1,114 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Parses HTTP query params collecting every invalid one into errs
+type queryParams struct {
+	ctx  *gin.Context
+	errs types.ValidationErrors
+}
+
+func (p *queryParams) fail(name, message string) {
+	p.errs = append(p.errs, &types.FieldError{Field: name, Message: message})
+}
+
+func (p *queryParams) int(name string, defaultValue int) int {
+	value, ok := p.ctx.GetQuery(name)
+	if !ok {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		p.fail(name, "must be a non-negative integer")
+	}
+	return parsed
+}
+
+func (p *queryParams) time(name string) time.Time {
+	value, ok := p.ctx.GetQuery(name)
+	if !ok {
+		return time.Time{}
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		p.fail(name, "must be RFC3339 date, like 2018-03-18T00:59:00Z")
+	}
+	return parsed
+}
+
+func (p *queryParams) bool(name string) *bool {
+	value, ok := p.ctx.GetQuery(name)
+	if !ok {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		p.fail(name, "must be true or false")
+	}
+	return &parsed
+}
+
+//Returns one of types.Currencies, defaultValue if param is absent
+func (p *queryParams) currency(name string, defaultValue string) string {
+	value, ok := p.ctx.GetQuery(name)
+	if !ok {
+		return defaultValue
+	}
+	if !types.IsCurrency(value) {
+		p.fail(name, "must be one of "+strings.Join(types.Currencies, ", "))
+	}
+	return value
+}
+
+//Returns one of allowed values, defaultValue if param is absent
+func (p *queryParams) oneOf(name string, defaultValue string, allowed []string) string {
+	value, ok := p.ctx.GetQuery(name)
+	if !ok {
+		return defaultValue
+	}
+	for _, known := range allowed {
+		if value == known {
+			return value
+		}
+	}
+	p.fail(name, "must be one of "+strings.Join(allowed, ", "))
+	return value
+}
+
+func (p *queryParams) limit() int {
+	limit := p.int("limit", types.DEFAULT_PAGE_LIMIT)
+	if limit == 0 || limit > types.MAX_PAGE_LIMIT {
+		p.fail("limit", "must be between 1 and "+strconv.Itoa(types.MAX_PAGE_LIMIT))
+	}
+	return limit
+}
+
+//Returns "limit" param clamped to 1..MAX_PAGE_LIMIT, DEFAULT_PAGE_LIMIT if it's absent
+func (p *queryParams) clampedLimit() int {
+	value, ok := p.ctx.GetQuery("limit")
+	if !ok {
+		return types.DEFAULT_PAGE_LIMIT
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail("limit", "must be an integer")
+	}
+	return types.PageLimit(parsed)
+}
+
+//Responds 400 listing every invalid param if any, returns false then
+func (a *Api) checkQueryParams(ctx *gin.Context, p *queryParams) bool {
+	if p.errs == nil {
+		return true
+	}
+	a.logger.Println(p.errs.Error())
+	ctx.AbortWithStatusJSON(httpStatuses[types.ERROR_KIND_INVALID_REQUEST], gin.H{"error": types.ErrRequestValidationFailed.Message, "fields": p.errs})
+	return false
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Seek by HTTP query "id" param, see respondRatings
+func (a *Api) getUserRatings(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	a.respondRatings(ctx, uint(id))
+}
+
+//GET /users/:id/ratings, see respondRatings
+func (a *Api) getUserRatingsV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondRatings(ctx, id)
+}
+
+//Responds with user ratings in every game or in one given by "game_id" HTTP query param, best first;
+//responds 400 on incorrect params, 200 with list of Rating as "data" otherwise
+func (a *Api) respondRatings(ctx *gin.Context, userId uint) {
+	params := &queryParams{ctx: ctx}
+	query := &types.RatingsQuery{
+		UserId: userId,
+		GameId: params.int("game_id", 0),
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	ratings, err := a.stor.FetchRatings(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrRatingsNotFound, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": ratings.([]*types.Rating)})
+}
+
+//Seek by HTTP query "id" param, see respondRatingHistory
+func (a *Api) getUserRatingHistory(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	a.respondRatingHistory(ctx, uint(id))
+}
+
+//GET /users/:id/rating-history, see respondRatingHistory
+func (a *Api) getUserRatingHistoryV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondRatingHistory(ctx, id)
+}
+
+//Responds with changes of user ratings by tournaments, newest first, filtered by "game_id"
+//and paged by "limit" and "offset" HTTP query params;
+//responds 400 on incorrect params listing every invalid one
+func (a *Api) respondRatingHistory(ctx *gin.Context, userId uint) {
+	params := &queryParams{ctx: ctx}
+	query := &types.RatingHistoryQuery{
+		UserId: userId,
+		GameId: params.int("game_id", 0),
+		Limit:  params.limit(),
+		Offset: params.int("offset", 0),
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	found, err := a.stor.FetchRatingHistory(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrRatingsNotFound, err)
+		return
+	}
+	page := found.(*types.RatingHistoryPage)
+	ctx.JSON(http.StatusOK, &types.RatingHistoryResponse{
+		Data: page.Changes,
+		Meta: &types.PageMeta{Total: page.Total},
+	})
+}
+
+//POST /admin/recomputeRatings (/admin/rating-recomputations in v1)
+//rebuilds every rating && its history from finished tournaments;
+//responds 200 with types.RatingsRecomputation as "data"
+func (a *Api) recomputeRatings(ctx *gin.Context) {
+	recomputation, err := a.stor.RecomputeRatings()
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrRatingsNotRecomputed, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": recomputation.(*types.RatingsRecomputation)})
+}
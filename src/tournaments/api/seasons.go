@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//POST /admin/season (/admin/seasons in v1) with JSON body like
+//{"name":"Spring","game_id":1,"starts_at":"2018-03-01T00:00:00Z","ends_at":"2018-06-01T00:00:00Z","place_points":[10,6,3],"rewards":[1000,500]}
+//players score place_points of places they take in tournaments of the game dated within the season,
+//rewards of season places are paid as season ends;
+//responds 400 on invalid request or error, 201 with full Season as "data" otherwise
+func (a *Api) createSeason(ctx *gin.Context) {
+	var parsedRequestBody types.SeasonRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	season, err := a.stor.CreateSeason(&parsedRequestBody)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrSeasonNotCreated, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": season.(*types.Season)})
+}
+
+//GET /tournament/seasons (/seasons in v1)
+//responds with seasons, latest starting first, filtered by "game_id" and paged by "limit" and "offset" HTTP query params;
+//responds 400 on incorrect params listing every invalid one
+func (a *Api) getSeasons(ctx *gin.Context) {
+	params := &queryParams{ctx: ctx}
+	query := &types.SeasonsQuery{
+		GameId: params.int("game_id", 0),
+		Limit:  params.limit(),
+		Offset: params.int("offset", 0),
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	found, err := a.stor.FetchSeasons(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrSeasonNotFound, err)
+		return
+	}
+	page := found.(*types.SeasonsPage)
+	ctx.JSON(http.StatusOK, &types.SeasonsResponse{
+		Data: page.Seasons,
+		Meta: &types.PageMeta{Total: page.Total},
+	})
+}
+
+//Seek by HTTP query "id" param, see respondSeason
+func (a *Api) getSeason(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	a.respondSeason(ctx, uint(id))
+}
+
+//GET /seasons/:id, see respondSeason
+func (a *Api) getSeasonV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondSeason(ctx, id)
+}
+
+//Responds 404 on absent season, 200 with full Season as "data" otherwise
+func (a *Api) respondSeason(ctx *gin.Context, id uint) {
+	season, err := a.stor.FetchSeason(id)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrSeasonNotFound, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": season.(*types.Season)})
+}
+
+//Seek by HTTP query "id" param, see respondSeasonStandings
+func (a *Api) getSeasonStandings(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	a.respondSeasonStandings(ctx, uint(id))
+}
+
+//GET /seasons/:id/standings, see respondSeasonStandings
+func (a *Api) getSeasonStandingsV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondSeasonStandings(ctx, id)
+}
+
+//Responds with page of season standings, best first, paged by "limit" and "offset" HTTP query params,
+//standings of season over carry rewards paid;
+//responds 400 on incorrect params listing every invalid one, 404 on absent season
+func (a *Api) respondSeasonStandings(ctx *gin.Context, id uint) {
+	params := &queryParams{ctx: ctx}
+	query := &types.SeasonStandingsQuery{
+		SeasonId: id,
+		Limit:    params.limit(),
+		Offset:   params.int("offset", 0),
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	found, err := a.stor.FetchSeasonStandings(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrSeasonNotFound, err)
+		return
+	}
+	page := found.(*types.SeasonStandingsPage)
+	ctx.JSON(http.StatusOK, &types.SeasonStandingsResponse{
+		Data: page.Standings,
+		Meta: &types.PageMeta{Total: page.Total},
+	})
+}
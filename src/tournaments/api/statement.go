@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+const STATEMENT_EXPORT_BATCH_SIZE = 1000
+
+//Seek by HTTP query "id" param, see respondStatement
+func (a *Api) getUserStatement(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	a.respondStatement(ctx, uint(id))
+}
+
+//GET /users/:id/statement, see respondStatement
+func (a *Api) getUserStatementV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondStatement(ctx, id)
+}
+
+//Responds with user balance operations
+//of user wallet in "currency" HTTP query param (points by default),
+//accepts filters "kind" (comma separated fund, take, join, prize, transfer, fee, refund, expire, voucher, sponsor, sponsor_return, overlay, season_reward, ticket_refund, correction, legacy), "date_from", "date_to" (RFC3339), "tournament_id",
+//"order" (desc by default or asc), "limit", "offset"
+//and "format" HTTP query params: "json" (default) responds page as "data" with "meta",
+//"csv" and "jsonl" export every matching operation ignoring "limit" and "offset";
+//responds 400 on incorrect params listing every invalid one
+func (a *Api) respondStatement(ctx *gin.Context, userId uint) {
+	params := &queryParams{ctx: ctx}
+	query := &types.StatementQuery{
+		UserId:       userId,
+		Currency:     params.currency("currency", types.CURRENCY_POINTS),
+		DateFrom:     params.time("date_from"),
+		DateTo:       params.time("date_to"),
+		TournamentId: uint(params.int("tournament_id", 0)),
+		Limit:        params.limit(),
+		Offset:       params.int("offset", 0),
+	}
+	if kinds := ctx.Query("kind"); kinds != "" {
+		for _, kind := range strings.Split(kinds, ",") {
+			if !isOperationKind(kind) {
+				params.fail("kind", "must be comma separated list of "+strings.Join(types.OperationKinds, ", "))
+				break
+			}
+			query.Kinds = append(query.Kinds, kind)
+		}
+	}
+	switch ctx.DefaultQuery("order", "desc") {
+	case "asc":
+		query.Ascending = true
+	case "desc":
+	default:
+		params.fail("order", "must be asc or desc")
+	}
+	format := ctx.DefaultQuery("format", types.STATEMENT_FORMAT_JSON)
+	if format != types.STATEMENT_FORMAT_JSON && format != types.STATEMENT_FORMAT_CSV && format != types.STATEMENT_FORMAT_JSONL {
+		params.fail("format", "must be one of json, csv, jsonl")
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+
+	if format == types.STATEMENT_FORMAT_JSON {
+		found, err := a.stor.FetchStatement(query)
+		if err != nil {
+			a.abortWithOperationError(ctx, types.ErrStatementNotFound, err)
+			return
+		}
+		page := found.(*types.StatementPage)
+		ctx.JSON(http.StatusOK, &types.StatementResponse{
+			Data: page.Entries,
+			Meta: &types.PageMeta{Total: page.Total},
+		})
+		return
+	}
+	a.exportStatement(ctx, query, format)
+}
+
+//Streams every operation matching query as CSV or JSON Lines, fetching them by batches following the last one fetched,
+//so operations written meanwhile don't shift batches
+func (a *Api) exportStatement(ctx *gin.Context, query *types.StatementQuery, format string) {
+	var (
+		csvWriter   *csv.Writer
+		jsonEncoder *json.Encoder
+	)
+	filename := "statement-" + strconv.Itoa(int(query.UserId)) + "." + format
+	if format == types.STATEMENT_FORMAT_CSV {
+		ctx.Header("Content-Type", "text/csv; charset=utf-8")
+		csvWriter = csv.NewWriter(ctx.Writer)
+	} else {
+		ctx.Header("Content-Type", "application/x-ndjson")
+		jsonEncoder = json.NewEncoder(ctx.Writer)
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	query.Limit, query.Offset, query.AfterId = STATEMENT_EXPORT_BATCH_SIZE, 0, 0
+	for {
+		found, err := a.stor.FetchStatement(query)
+		if err != nil {
+			if query.AfterId == 0 {
+				a.abortWithOperationError(ctx, types.ErrStatementNotFound, err)
+				return
+			}
+			// Headers are already sent, so just break the stream
+			a.logger.Println(err.Error())
+			ctx.Abort()
+			return
+		}
+		if query.AfterId == 0 {
+			ctx.Status(http.StatusOK)
+			if csvWriter != nil {
+				csvWriter.Write([]string{"id", "created_at", "kind", "tournament_id", "amount", "running_balance"})
+			}
+		}
+		entries := found.(*types.StatementPage).Entries
+		for _, entry := range entries {
+			if csvWriter != nil {
+				csvWriter.Write([]string{
+					strconv.Itoa(int(entry.ID)),
+					entry.CreatedAt.Format(time.RFC3339),
+					entry.Kind,
+					strconv.Itoa(int(entry.TournamentId)),
+					strconv.Itoa(entry.Amount),
+					strconv.Itoa(entry.RunningBalance),
+				})
+			} else {
+				jsonEncoder.Encode(entry)
+			}
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+		}
+		if len(entries) < query.Limit {
+			return
+		}
+		query.AfterId = entries[len(entries)-1].ID
+	}
+}
+
+func isOperationKind(kind string) bool {
+	for _, known := range types.OperationKinds {
+		if kind == known {
+			return true
+		}
+	}
+	return false
+}
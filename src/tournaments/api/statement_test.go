@@ -0,0 +1,70 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Wallet ledger growing by one operation after every statement page fetched
+type growingLedgerStorage struct {
+	nopStorage
+	lastId  uint
+	fetches int
+}
+
+func (s *growingLedgerStorage) FetchStatement(query *types.StatementQuery) (interface{}, error) {
+	entries := []*types.StatementEntry{}
+	skipped := 0
+	for id := s.lastId; id > 0 && len(entries) < query.Limit; id-- {
+		if query.AfterId > 0 && id >= query.AfterId {
+			continue
+		}
+		if skipped < query.Offset {
+			skipped++
+			continue
+		}
+		entries = append(entries, &types.StatementEntry{ID: id, Kind: types.OPERATION_KIND_FUND, Amount: 1, RunningBalance: int(id)})
+	}
+	s.lastId++
+	s.fetches++
+	return &types.StatementPage{Entries: entries, Total: int(s.lastId)}, nil
+}
+
+func TestStatementExportWhileLedgerGrows(t *testing.T) {
+	operations := uint(2*STATEMENT_EXPORT_BATCH_SIZE + 10)
+	storage := &growingLedgerStorage{lastId: operations}
+	a, err := NewApi(&ApiConf{RelativePath: "/tournament/v0", V1RelativePath: "/tournament/v1"}, storage, log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	a.engine.ServeHTTP(recorder, httptest.NewRequest("GET", "/tournament/v1/users/1/statement?format=jsonl", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	exported := []uint{}
+	scanner := bufio.NewScanner(recorder.Body)
+	for scanner.Scan() {
+		entry := &types.StatementEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			t.Fatal(err)
+		}
+		exported = append(exported, entry.ID)
+	}
+	if uint(len(exported)) != operations {
+		t.Fatalf("got %d operations exported in %d batches, want %d", len(exported), storage.fetches, operations)
+	}
+	for i, id := range exported {
+		if id != operations-uint(i) {
+			t.Fatalf("got operation %d at line %d, want %d", id, i, operations-uint(i))
+		}
+	}
+}
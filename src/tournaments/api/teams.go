@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//processes POST JSON body like {"name":"Red","captain_id":1,"members":[{"user_id":1,"share":50},{"user_id":2,"share":50}]}
+//requires "name", "captain_id" being one of "members" with unique "user_id"s and "share"s (percents) adding up to 100,
+//responds 400 on invalid request or error, 200 with full Team otherwise
+func (a *Api) createTeam(ctx *gin.Context) {
+	var parsedRequestBody types.TeamRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	team, err := a.stor.CreateTeam(&parsedRequestBody)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrTeamNotCreated, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": team.(*types.Team)})
+}
+
+//POST /teams with JSON body like createTeam one,
+//responds 400 on invalid request or error, 201 with full Team as "data" and its Location otherwise
+func (a *Api) createTeamV1(ctx *gin.Context) {
+	var parsedRequestBody types.TeamRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	created, err := a.stor.CreateTeam(&parsedRequestBody)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrTeamNotCreated, err)
+		return
+	}
+	team := created.(*types.Team)
+	ctx.Header("Location", a.conf.V1RelativePath+"/teams/"+strconv.Itoa(int(team.ID)))
+	ctx.JSON(http.StatusCreated, gin.H{"data": team})
+}
+
+//Seek by HTTP query "id" param, see respondTeam
+func (a *Api) getTeam(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	a.respondTeam(ctx, uint(id))
+}
+
+//GET /teams/:id, see respondTeam
+func (a *Api) getTeamV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondTeam(ctx, id)
+}
+
+//Responds 404 on absent team, 200 with full Team as "data" otherwise
+func (a *Api) respondTeam(ctx *gin.Context, id uint) {
+	team, err := a.stor.FetchTeam(id)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrTeamNotFound, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": team.(*types.Team)})
+}
+
+//processes POST JSON body like {"tournament_id":1,"team_id":2}
+//holds tournament deposit on team members' balances by their shares, every member must qualify for the tournament;
+//responds 400 on invalid request or error, 403 with "code" if a member doesn't qualify, 204 otherwise
+func (a *Api) joinTournamentByTeam(ctx *gin.Context) {
+	var parsedRequestBody types.TeamEntryRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondTeamEntry(ctx, &parsedRequestBody)
+}
+
+//POST /tournaments/:id/team-entries with JSON body like {"team_id":2}, see joinTournamentByTeam
+func (a *Api) createTeamEntryV1(ctx *gin.Context) {
+	var parsedRequestBody types.TournamentTeamEntryRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondTeamEntry(ctx, parsedRequestBody.TeamEntryRequest(id))
+}
+
+func (a *Api) respondTeamEntry(ctx *gin.Context, request *types.TeamEntryRequest) {
+	if err := a.stor.JoinTournamentByTeam(request); err != nil {
+		a.abortWithOperationError(ctx, types.ErrTournamentNotJoined, err)
+		return
+	}
+	ctx.String(http.StatusNoContent, ``)
+}
+
+//processes POST JSON body like {"tournament_id":1,"team_id":2}
+//releases deposits held for team entry, possible until registration closes;
+//responds 400 on invalid request or error, 204 otherwise
+func (a *Api) withdrawTeam(ctx *gin.Context) {
+	var parsedRequestBody types.TeamEntryRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondTeamWithdrawal(ctx, &parsedRequestBody)
+}
+
+//DELETE /tournaments/:id/team-entries/:team_id
+//responds 400 on incorrect ids or error, 204 otherwise
+func (a *Api) deleteTeamEntryV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	teamId, ok := a.pathId(ctx, "team_id")
+	if !ok {
+		return
+	}
+	a.respondTeamWithdrawal(ctx, &types.TeamEntryRequest{TournamentId: id, TeamId: teamId})
+}
+
+func (a *Api) respondTeamWithdrawal(ctx *gin.Context, request *types.TeamEntryRequest) {
+	if err := a.stor.WithdrawTeamFromTournament(request); err != nil {
+		a.abortWithOperationError(ctx, types.ErrTournamentNotWithdrawn, err)
+		return
+	}
+	ctx.String(http.StatusNoContent, ``)
+}
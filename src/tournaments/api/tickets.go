@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Seek by HTTP query "id" param, see respondTickets
+func (a *Api) getUserTickets(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	a.respondTickets(ctx, uint(id))
+}
+
+//GET /users/:id/tickets, see respondTickets
+func (a *Api) getUserTicketsV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondTickets(ctx, id)
+}
+
+//Responds with satellite tickets of user, newest first, filtered by "state"
+//and paged by "limit" and "offset" HTTP query params;
+//responds 400 on incorrect params listing every invalid one
+func (a *Api) respondTickets(ctx *gin.Context, userId uint) {
+	params := &queryParams{ctx: ctx}
+	query := &types.TicketsQuery{
+		UserId: userId,
+		State:  params.oneOf("state", "", types.TicketStates),
+		Limit:  params.limit(),
+		Offset: params.int("offset", 0),
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	found, err := a.stor.FetchTickets(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrTicketsNotFound, err)
+		return
+	}
+	page := found.(*types.TicketsPage)
+	ctx.JSON(http.StatusOK, &types.TicketsResponse{
+		Data: page.Tickets,
+		Meta: &types.PageMeta{Total: page.Total},
+	})
+}
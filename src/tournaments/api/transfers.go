@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//processes POST JSON body like {"from_user_id":1,"to_user_id":2,"amount":100,"comment":"backing deal"}
+//requires "from_user_id", "to_user_id", "amount" fields,
+//responds 400 on invalid request or rejected transfer, 200 with full Transfer as "data" otherwise
+func (a *Api) transferPoints(ctx *gin.Context) {
+	var parsedRequestBody types.TransferRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	transfer, err := a.stor.TransferPoints(&parsedRequestBody)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrPointsNotTransferred, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": transfer.(*types.Transfer)})
+}
+
+//POST /users/:id/transfers with JSON body like {"to_user_id":2,"amount":100}
+//responds 400 on invalid request or rejected transfer, 201 with full Transfer as "data" otherwise
+func (a *Api) createTransferV1(ctx *gin.Context) {
+	var parsedRequestBody types.UserTransferRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	request := parsedRequestBody.TransferRequest(id)
+	if errs := types.Validate(request); errs != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": types.ErrRequestValidationFailed.Message, "fields": errs})
+		return
+	}
+	transfer, err := a.stor.TransferPoints(request)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrPointsNotTransferred, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": transfer.(*types.Transfer)})
+}
+
+//Seek by HTTP query "id" param, see respondTransfers
+func (a *Api) getUserTransfers(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Query("id"))
+	if err != nil || id <= 0 {
+		ctx.AbortWithError(http.StatusBadRequest, errors.New("Incorrect ID provided"))
+		return
+	}
+	a.respondTransfers(ctx, uint(id))
+}
+
+//GET /users/:id/transfers, see respondTransfers
+func (a *Api) getUserTransfersV1(ctx *gin.Context) {
+	id, ok := a.pathId(ctx, "id")
+	if !ok {
+		return
+	}
+	a.respondTransfers(ctx, id)
+}
+
+//Responds with transfers sent and received by user, newest first,
+//accepts "direction" (in or out, both by default), "limit" and "offset" HTTP query params;
+//responds 400 on incorrect params listing every invalid one
+func (a *Api) respondTransfers(ctx *gin.Context, userId uint) {
+	params := &queryParams{ctx: ctx}
+	query := &types.TransfersQuery{
+		UserId:    userId,
+		Direction: ctx.Query("direction"),
+		Limit:     params.limit(),
+		Offset:    params.int("offset", 0),
+	}
+	if query.Direction != "" && query.Direction != types.TRANSFER_DIRECTION_IN && query.Direction != types.TRANSFER_DIRECTION_OUT {
+		params.fail("direction", "must be in or out")
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	found, err := a.stor.FetchTransfers(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrTransfersNotFound, err)
+		return
+	}
+	page := found.(*types.TransfersPage)
+	ctx.JSON(http.StatusOK, &types.TransfersResponse{
+		Data: page.Transfers,
+		Meta: &types.PageMeta{Total: page.Total},
+	})
+}
+
+//processes POST JSON body like {"user_id":1,"daily_limit":1000}
+//responds 400 on invalid request, 200 with full TransferLimit as "data" otherwise
+func (a *Api) setTransferLimit(ctx *gin.Context) {
+	var parsedRequestBody types.TransferLimitRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondTransferLimit(ctx, &parsedRequestBody)
+}
+
+//PUT /admin/users/:id/transfer-limit with JSON body like {"daily_limit":1000}
+//responds 400 on invalid request, 200 with full TransferLimit as "data" otherwise
+func (a *Api) setTransferLimitV1(ctx *gin.Context) {
+	var parsedRequestBody types.UserTransferLimitRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondTransferLimit(ctx, &types.TransferLimitRequest{UserId: id, DailyLimit: parsedRequestBody.DailyLimit})
+}
+
+func (a *Api) respondTransferLimit(ctx *gin.Context, request *types.TransferLimitRequest) {
+	limit, err := a.stor.SetTransferLimit(request)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrTransferLimitNotSet, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": limit.(*types.TransferLimit)})
+}
@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// User wallet balance as it was at the start of a day, taken by daily snapshot job,
+// balance at any later moment is the snapshot plus operations made since
+type BalanceSnapshot struct {
+	ID        uint      `json:"id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UserId    uint      `gorm:"unique_index:idx_balance_snapshots_user_id_currency_taken_at" json:"user_id"`
+	Currency  string    `gorm:"not null;default:'points';unique_index:idx_balance_snapshots_user_id_currency_taken_at" json:"currency"`
+	// Sum of user operations in Currency made before TakenAt
+	TakenAt time.Time `gorm:"unique_index:idx_balance_snapshots_user_id_currency_taken_at" json:"taken_at"`
+	Balance int       `json:"balance"`
+}
+
+// User balance at a moment in the past, keys are the same as UserPointsBalance ones
+type BalanceAsOf struct {
+	UserId   uint      `json:"UserId"`
+	Currency string    `json:"Currency"`
+	Balance  int       `json:"Balance"`
+	AsOf     time.Time `json:"as_of"`
+}
+
+type BalancesAsOfQuery struct {
+	AsOf time.Time
+	// One of Currencies, wallets of every currency if empty
+	Currency string
+	Limit    int
+	Offset   int
+}
+
+type BalancesAsOfPage struct {
+	Balances []*BalanceAsOf
+	Total    int
+}
+
+type BalancesAsOfResponse struct {
+	Data []*BalanceAsOf `json:"data"`
+	Meta *PageMeta      `json:"meta"`
+}
@@ -0,0 +1,278 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Tournament formats, tournaments without format take results as a flat winners list
+const (
+	TOURNAMENT_FORMAT_SINGLE_ELIMINATION = "single_elimination"
+	// Players losing in winners bracket drop to losers bracket, champion of each meets in a single grand final
+	TOURNAMENT_FORMAT_DOUBLE_ELIMINATION = "double_elimination"
+	TOURNAMENT_FORMAT_ROUND_ROBIN        = "round_robin"
+	// Players with equal scores are paired round by round avoiding rematches
+	TOURNAMENT_FORMAT_SWISS = "swiss"
+)
+
+var TournamentFormats = []string{
+	TOURNAMENT_FORMAT_SINGLE_ELIMINATION,
+	TOURNAMENT_FORMAT_DOUBLE_ELIMINATION,
+	TOURNAMENT_FORMAT_ROUND_ROBIN,
+	TOURNAMENT_FORMAT_SWISS,
+}
+
+// Ways to order players before bracket generation, seed 1 is the strongest one
+const (
+	SEEDING_JOIN_ORDER = "join_order"
+	SEEDING_RANDOM     = "random"
+	// Seeds are listed in request
+	SEEDING_MANUAL = "manual"
+)
+
+const (
+	MATCH_BRACKET_WINNERS     = "winners"
+	MATCH_BRACKET_LOSERS      = "losers"
+	MATCH_BRACKET_GRAND_FINAL = "grand_final"
+	MATCH_BRACKET_ROUND_ROBIN = "round_robin"
+	MATCH_BRACKET_SWISS       = "swiss"
+)
+
+const (
+	// Some player is not known yet
+	MATCH_STATE_PENDING = "pending"
+	// Both players are known, result is awaited
+	MATCH_STATE_READY    = "ready"
+	MATCH_STATE_FINISHED = "finished"
+)
+
+// Round robin standings points
+const (
+	MATCH_POINTS_WIN  = 3
+	MATCH_POINTS_DRAW = 1
+)
+
+// Swiss standings points are half-points of chess scoring 1-½-0, bye scores a win
+const (
+	SWISS_POINTS_WIN  = 2
+	SWISS_POINTS_DRAW = 1
+)
+
+// Structured format of tournament generated from its players
+type Bracket struct {
+	ID           uint      `json:"id,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	TournamentId uint      `gorm:"unique_index" json:"tournament_id"`
+	Format       string    `json:"format"`
+	Seeding      string    `json:"seeding"`
+	// Number of swiss rounds, the next one is paired as every match of previous one finishes
+	Rounds      int        `json:"rounds,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Prizes by place, paid as bracket completes if any
+	Prizes    []*BracketPrize       `sql:"-" json:"prizes"`
+	Matches   []*Match              `sql:"-" json:"matches"`
+	Standings []*TournamentStanding `sql:"-" json:"standings"`
+}
+
+type BracketPrize struct {
+	ID        uint `json:"-"`
+	BracketId uint `sql:"index" json:"-"`
+	Place     int  `json:"place"`
+	Prize     int  `json:"prize"`
+}
+
+// Match of two players, 0 player means the one is not known yet or there is none (bye)
+type Match struct {
+	ID           uint      `json:"id,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+	TournamentId uint      `sql:"index" json:"tournament_id"`
+	// One of MATCH_BRACKET_*
+	Bracket   string `json:"bracket"`
+	Round     int    `json:"round"`
+	Position  int    `json:"position"`
+	Player1Id uint   `json:"player1_id,omitempty"`
+	Player2Id uint   `json:"player2_id,omitempty"`
+	// Slots are filled as players are known or known to be absent
+	Slot1Filled bool `json:"-"`
+	Slot2Filled bool `json:"-"`
+	Score1      int  `json:"score1"`
+	Score2      int  `json:"score2"`
+	// 0 for draw, for a match of nobody or for one not finished yet
+	WinnerId uint `json:"winner_id,omitempty"`
+	// Winner && loser take slots 1 or 2 of next matches, 0 if they leave bracket
+	WinnerNextMatchId uint `json:"winner_next_match_id,omitempty"`
+	WinnerNextSlot    int  `json:"-"`
+	LoserNextMatchId  uint `json:"loser_next_match_id,omitempty"`
+	LoserNextSlot     int  `json:"-"`
+	// Order of elimination of loser, later stages rank higher
+	Stage int `json:"-"`
+	// One of MATCH_STATE_*
+	State string `sql:"index" json:"state"`
+	// Match finished without play as one of players is absent
+	Bye bool `json:"bye,omitempty"`
+}
+
+func (m *Match) HasPlayer(userId uint) bool {
+	return userId != 0 && (m.Player1Id == userId || m.Player2Id == userId)
+}
+
+// Final place of player in bracket tournament, tied players share place
+type TournamentStanding struct {
+	ID           uint `json:"-"`
+	TournamentId uint `sql:"index" json:"tournament_id"`
+	UserId       uint `json:"user_id"`
+	Place        int  `json:"place"`
+	Seed         int  `json:"seed"`
+	Wins         int  `json:"wins"`
+	Draws        int  `json:"draws"`
+	Losses       int  `json:"losses"`
+	// Round robin points or swiss half-points
+	Points int `json:"points"`
+	// Swiss tie-breaks in half-points: sum of opponents' points
+	// && sum of points of beaten opponents plus half of drawn ones
+	Buchholz        float64 `json:"buchholz,omitempty"`
+	SonnebornBerger float64 `json:"sonneborn_berger,omitempty"`
+	Prize           int     `json:"prize"`
+}
+
+type BracketRequest struct {
+	TournamentId uint `json:"tournament_id" validate:"required"`
+	// join_order by default
+	Seeding string `json:"seeding,omitempty" validate:"oneof=join_order|random|manual"`
+	// Every player of tournament, strongest first, for manual seeding
+	Seeds []uint `json:"seeds,omitempty" validate:"unique"`
+	// Prizes of places 1, 2, ... paid as bracket completes, results are submitted as a winners list otherwise
+	Prizes []int `json:"prizes,omitempty"`
+	// Swiss rounds, log2 of players count rounded up by default
+	Rounds int `json:"rounds,omitempty" validate:"min=0"`
+}
+
+func (r *BracketRequest) validate(prefix string) (errs ValidationErrors) {
+	if r.Seeding == SEEDING_MANUAL && len(r.Seeds) == 0 {
+		errs = errs.add(prefix+"seeds", "is required for manual seeding")
+	}
+	if r.Seeding != SEEDING_MANUAL && len(r.Seeds) > 0 {
+		errs = errs.add(prefix+"seeds", "is allowed for manual seeding only")
+	}
+	for i, prize := range r.Prizes {
+		if prize < 0 {
+			errs = errs.add(fmt.Sprintf("%sprizes[%d]", prefix, i), "must be at least 0")
+		}
+	}
+	return errs
+}
+
+// Body of POST /tournaments/:id/bracket, tournament is taken from path
+type TournamentBracketRequest struct {
+	Seeding string `json:"seeding,omitempty" validate:"oneof=join_order|random|manual"`
+	Seeds   []uint `json:"seeds,omitempty" validate:"unique"`
+	Prizes  []int  `json:"prizes,omitempty"`
+	Rounds  int    `json:"rounds,omitempty" validate:"min=0"`
+}
+
+func (r *TournamentBracketRequest) validate(prefix string) ValidationErrors {
+	return r.BracketRequest(0).validate(prefix)
+}
+
+func (r *TournamentBracketRequest) BracketRequest(tournamentId uint) *BracketRequest {
+	return &BracketRequest{
+		TournamentId: tournamentId,
+		Seeding:      r.Seeding,
+		Seeds:        r.Seeds,
+		Prizes:       r.Prizes,
+		Rounds:       r.Rounds,
+	}
+}
+
+type MatchResultRequest struct {
+	TournamentId uint `json:"tournament_id" validate:"required"`
+	MatchId      uint `json:"match_id" validate:"required"`
+	// 0 for round robin draw
+	WinnerId uint `json:"winner_id,omitempty"`
+	Score1   int  `json:"score1" validate:"min=0"`
+	Score2   int  `json:"score2" validate:"min=0"`
+}
+
+func (r *MatchResultRequest) validate(prefix string) (errs ValidationErrors) {
+	if r.WinnerId == 0 && r.Score1 != r.Score2 {
+		errs = errs.add(prefix+"winner_id", "is required unless match is a draw")
+	}
+	return errs
+}
+
+// Body of PUT /tournaments/:id/matches/:match_id/result, tournament && match are taken from path
+type MatchScoreRequest struct {
+	WinnerId uint `json:"winner_id,omitempty"`
+	Score1   int  `json:"score1" validate:"min=0"`
+	Score2   int  `json:"score2" validate:"min=0"`
+}
+
+func (r *MatchScoreRequest) validate(prefix string) ValidationErrors {
+	return r.MatchResultRequest(0, 0).validate(prefix)
+}
+
+func (r *MatchScoreRequest) MatchResultRequest(tournamentId uint, matchId uint) *MatchResultRequest {
+	return &MatchResultRequest{
+		TournamentId: tournamentId,
+		MatchId:      matchId,
+		WinnerId:     r.WinnerId,
+		Score1:       r.Score1,
+		Score2:       r.Score2,
+	}
+}
+
+// Result of match of round, see MatchResultRequest
+type RoundMatchResult struct {
+	MatchId  uint `json:"match_id" validate:"required"`
+	WinnerId uint `json:"winner_id,omitempty"`
+	Score1   int  `json:"score1" validate:"min=0"`
+	Score2   int  `json:"score2" validate:"min=0"`
+}
+
+func (r *RoundMatchResult) validate(prefix string) (errs ValidationErrors) {
+	if r.WinnerId == 0 && r.Score1 != r.Score2 {
+		errs = errs.add(prefix+"winner_id", "is required unless match is a draw")
+	}
+	return errs
+}
+
+// Results of matches of a round submitted at once
+type RoundResultsRequest struct {
+	TournamentId uint                `json:"tournament_id" validate:"required"`
+	Round        int                 `json:"round" validate:"min=1"`
+	Results      []*RoundMatchResult `json:"results" validate:"required"`
+}
+
+func (r *RoundResultsRequest) validate(prefix string) (errs ValidationErrors) {
+	seen := map[uint]bool{}
+	for i, result := range r.Results {
+		name := fmt.Sprintf("%sresults[%d]", prefix, i)
+		if result == nil {
+			errs = errs.add(name, "is required")
+			continue
+		}
+		if result.MatchId != 0 && seen[result.MatchId] {
+			errs = errs.add(name+".match_id", fmt.Sprintf("contains duplicate value %d", result.MatchId))
+		}
+		seen[result.MatchId] = true
+	}
+	return errs
+}
+
+// Body of POST /tournaments/:id/rounds/:round/results, tournament && round are taken from path
+type TournamentRoundResultsRequest struct {
+	Results []*RoundMatchResult `json:"results" validate:"required"`
+}
+
+func (r *TournamentRoundResultsRequest) validate(prefix string) ValidationErrors {
+	return r.RoundResultsRequest(0, 1).validate(prefix)
+}
+
+func (r *TournamentRoundResultsRequest) RoundResultsRequest(tournamentId uint, round int) *RoundResultsRequest {
+	return &RoundResultsRequest{
+		TournamentId: tournamentId,
+		Round:        round,
+		Results:      r.Results,
+	}
+}
@@ -0,0 +1,38 @@
+package types
+
+// Currencies of user wallets, tournament deposits && prizes
+const (
+	// Default one, all balances made before currencies were introduced are in it
+	CURRENCY_POINTS = "points"
+	CURRENCY_BONUS  = "bonus"
+	CURRENCY_TOKENS = "tokens"
+)
+
+var Currencies = []string{
+	CURRENCY_POINTS,
+	CURRENCY_BONUS,
+	CURRENCY_TOKENS,
+}
+
+// User wallet of given currency, points wallet if Currency is empty
+type WalletRequest struct {
+	UserId   uint   `json:"user_id" validate:"required"`
+	Currency string `json:"currency,omitempty" validate:"oneof=points|bonus|tokens"`
+}
+
+//Returns currency or CURRENCY_POINTS if it's empty
+func CurrencyOrDefault(currency string) string {
+	if currency == "" {
+		return CURRENCY_POINTS
+	}
+	return currency
+}
+
+func IsCurrency(currency string) bool {
+	for _, known := range Currencies {
+		if currency == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package types
+
+// Kinds of failures reported to API clients,
+// every transport maps them to its own statuses (HTTP status, gRPC code)
+const (
+	ERROR_KIND_INVALID_REQUEST = iota
+	ERROR_KIND_NOT_FOUND
+	ERROR_KIND_REJECTED
+	// Operation is not allowed to the user it's made for
+	ERROR_KIND_FORBIDDEN
+)
+
+// Public failure of an ApiStorage operation.
+// Storage errors are logged, clients get just Message;
+// OperationError returned by storage itself is reported to clients as is
+type OperationError struct {
+	Kind int
+	// Machine-readable reason, empty for generic failures
+	Code    string
+	Message string
+}
+
+func (e *OperationError) Error() string {
+	return e.Message
+}
+
+//Exposes Code to GraphQL clients as error extension
+func (e *OperationError) Extensions() map[string]interface{} {
+	if e.Code == "" {
+		return nil
+	}
+	return map[string]interface{}{"code": e.Code}
+}
+
+//Returns err itself if it's an OperationError, fallback otherwise
+func PublicError(err error, fallback *OperationError) *OperationError {
+	if opErr, ok := err.(*OperationError); ok {
+		return opErr
+	}
+	return fallback
+}
+
+var (
+	ErrTournamentNotFound       = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Tournament not found"}
+	ErrTournamentsNotFound      = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Tournaments not found"}
+	ErrBalanceNotFound          = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Balance not found"}
+	ErrBalanceNotTakenAway      = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Balance not taken away"}
+	ErrBalanceNotReplenished    = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Balance not replenished"}
+	ErrTournamentNotAnnounced   = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "could not announce tournament"}
+	ErrTournamentNotJoined      = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "could not join tournament"}
+	ErrTournamentResultNotSaved = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not save tournament result"}
+	ErrStatementNotFound        = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Statement not found"}
+	ErrRequestValidationFailed  = &OperationError{Kind: ERROR_KIND_INVALID_REQUEST, Message: "Request validation failed"}
+	ErrReconciliationFailed     = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not reconcile balances"}
+	ErrPointsNotTransferred     = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not transfer points"}
+	ErrTransfersNotFound        = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Transfers not found"}
+	ErrTransferLimitNotSet      = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not set transfer limit"}
+	ErrTournamentNotWithdrawn   = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not withdraw from tournament"}
+	ErrRegistrationNotClosed    = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not close tournament registration"}
+	ErrTournamentNotCancelled   = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not cancel tournament"}
+	ErrNotificationsNotFound    = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Notifications not found"}
+	ErrVoucherNotCreated        = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not create voucher"}
+	ErrVoucherNotRedeemed       = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not redeem voucher"}
+	ErrRedemptionsNotFound      = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Voucher redemptions not found"}
+	ErrOverlaysNotFound         = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Overlays not found"}
+	ErrBracketNotGenerated      = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not generate bracket"}
+	ErrBracketNotFound          = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Bracket not found"}
+	ErrMatchResultNotSaved      = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not save match result"}
+	ErrRoundResultsNotSaved     = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not save round results"}
+	ErrRatingsNotFound          = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Ratings not found"}
+	ErrRatingsNotRecomputed     = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not recompute ratings"}
+	ErrLeaderboardNotFound      = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Leaderboard not found"}
+	ErrSeasonNotCreated         = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not create season"}
+	ErrSeasonNotFound           = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Season not found"}
+	ErrTicketsNotFound          = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Tickets not found"}
+	ErrTeamNotCreated           = &OperationError{Kind: ERROR_KIND_REJECTED, Message: "Could not create team"}
+	ErrTeamNotFound             = &OperationError{Kind: ERROR_KIND_NOT_FOUND, Message: "Team not found"}
+)
+
+// Reasons player doesn't qualify for tournament entry
+var (
+	ErrEntryRatingTooLow      = &OperationError{Kind: ERROR_KIND_FORBIDDEN, Code: "rating_too_low", Message: "Player rating is below tournament minimum"}
+	ErrEntryRatingTooHigh     = &OperationError{Kind: ERROR_KIND_FORBIDDEN, Code: "rating_too_high", Message: "Player rating is above tournament maximum"}
+	ErrEntryNotInvited        = &OperationError{Kind: ERROR_KIND_FORBIDDEN, Code: "not_invited", Message: "Tournament is invitation-only"}
+	ErrEntryInvalidInvitation = &OperationError{Kind: ERROR_KIND_FORBIDDEN, Code: "invalid_invitation_code", Message: "Invitation code is unknown or already used"}
+	ErrEntryNotQualified      = &OperationError{Kind: ERROR_KIND_FORBIDDEN, Code: "not_qualified", Message: "Player did not qualify in qualifier tournament"}
+	ErrEntryInvalidTicket     = &OperationError{Kind: ERROR_KIND_FORBIDDEN, Code: "invalid_ticket", Message: "Ticket is not the player's valid ticket to tournament"}
+)
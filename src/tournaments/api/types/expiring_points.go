@@ -0,0 +1,69 @@
+package types
+
+import "time"
+
+const NOTIFICATION_KIND_POINTS_EXPIRED = "points_expired"
+
+// Points of a top-up made with expiry, spending consumes lots expiring soonest first,
+// points left in a lot by ExpiresAt are taken away by "expire" operation
+type BalanceLot struct {
+	ID        uint      `json:"id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	UserId    uint      `sql:"index" json:"user_id"`
+	Currency  string    `gorm:"not null;default:'points'" json:"currency"`
+	// Points topped up
+	Amount int `json:"amount"`
+	// Points not spent yet
+	Remaining int       `gorm:"not null;default:0" json:"remaining"`
+	ExpiresAt time.Time `sql:"index" json:"expires_at"`
+	// Points taken away as lot expired
+	Expired   int        `gorm:"not null;default:0" json:"expired"`
+	ExpiredAt *time.Time `json:"expired_at,omitempty"`
+}
+
+// Part of lot held for tournament, returned to the lot if hold is released or refunded
+type BalanceLotUse struct {
+	ID        uint
+	CreatedAt time.Time
+	LotId     uint `sql:"index"`
+	HoldId    uint `sql:"index"`
+	Amount    int
+}
+
+// Message to user about balance changes made by service itself
+type Notification struct {
+	ID        uint      `json:"id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UserId    uint      `sql:"index" json:"user_id"`
+	// One of NOTIFICATION_KIND_*
+	Kind     string `json:"kind"`
+	Currency string `json:"currency,omitempty"`
+	Amount   int    `json:"amount,omitempty"`
+	Message  string `json:"message"`
+}
+
+type NotificationsQuery struct {
+	UserId uint
+	Limit  int
+	Offset int
+}
+
+type NotificationsPage struct {
+	Notifications []*Notification
+	Total         int
+}
+
+type NotificationsResponse struct {
+	Data []*Notification `json:"data"`
+	Meta *PageMeta       `json:"meta"`
+}
+
+//Returns moment points topped up now expire at, nil if they never expire
+func expiresAt(expiresInDays int) *time.Time {
+	if expiresInDays <= 0 {
+		return nil
+	}
+	expiresAt := time.Now().AddDate(0, 0, expiresInDays)
+	return &expiresAt
+}
@@ -0,0 +1,46 @@
+package types
+
+import "time"
+
+const (
+	TOURNAMENT_STATE_OPEN = iota
+	TOURNAMENT_STATE_FINISHED
+	TOURNAMENT_STATE_CANCELLED
+)
+
+const (
+	// Points are reserved on stakeholder balance, not available for spending
+	HOLD_STATE_HELD = "held"
+	// Points are debited from balance by "join" operation
+	HOLD_STATE_CAPTURED = "captured"
+	// Entry is withdrawn or tournament is cancelled before capture
+	HOLD_STATE_RELEASED = "released"
+	// Hold is released by expiry job as registration was not closed in time
+	HOLD_STATE_EXPIRED = "expired"
+	// Captured points are returned by "refund" operation as tournament is cancelled
+	HOLD_STATE_REFUNDED = "refunded"
+)
+
+// Stakeholder's deposit for tournament entry of PlayerId,
+// reserved on joining tournament and captured when registration closes
+type BalanceHold struct {
+	ID           uint      `json:"id,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+	UserId       uint      `sql:"index" json:"user_id"`
+	TournamentId uint      `sql:"index" json:"tournament_id"`
+	PlayerId     uint      `json:"player_id"`
+	Amount       int       `json:"amount"`
+	Currency     string    `gorm:"not null;default:'points'" json:"currency"`
+	State        string    `sql:"index" json:"state"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type WithdrawTournamentRequest struct {
+	TournamentId uint `json:"tournament_id" validate:"required"`
+	PlayerId     uint `json:"player_id" validate:"required"`
+}
+
+type TournamentIdRequest struct {
+	TournamentId uint `json:"tournament_id" validate:"required"`
+}
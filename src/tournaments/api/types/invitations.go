@@ -0,0 +1,14 @@
+package types
+
+import "time"
+
+// Invitation to invitation-only tournament, either of a user or by a single-use code;
+// UserId of code invitation is set as the code is used
+type TournamentInvitation struct {
+	ID           uint       `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	TournamentId uint       `sql:"index" json:"tournament_id"`
+	UserId       uint       `json:"user_id,omitempty"`
+	Code         string     `json:"code,omitempty"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+}
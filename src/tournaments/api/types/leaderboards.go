@@ -0,0 +1,98 @@
+package types
+
+import "time"
+
+// What players are ranked by
+const (
+	// Sum of prizes won
+	LEADERBOARD_METRIC_WINNINGS = "winnings"
+	// Prizes won minus deposits paid
+	LEADERBOARD_METRIC_NET_PROFIT = "net_profit"
+	// Tournaments the biggest prize of which was won
+	LEADERBOARD_METRIC_TOURNAMENTS_WON = "tournaments_won"
+	// Current Rating in a game, requires game to be given
+	LEADERBOARD_METRIC_RATING = "rating"
+)
+
+var LeaderboardMetrics = []string{
+	LEADERBOARD_METRIC_WINNINGS,
+	LEADERBOARD_METRIC_NET_PROFIT,
+	LEADERBOARD_METRIC_TOURNAMENTS_WON,
+	LEADERBOARD_METRIC_RATING,
+}
+
+// Period tournaments are counted over by their date, windows are calendar ones in UTC
+const (
+	LEADERBOARD_WINDOW_ALL = "all"
+	// Since Monday
+	LEADERBOARD_WINDOW_WEEK  = "week"
+	LEADERBOARD_WINDOW_MONTH = "month"
+	// Since the first day of calendar quarter
+	LEADERBOARD_WINDOW_SEASON = "season"
+)
+
+var LeaderboardWindows = []string{
+	LEADERBOARD_WINDOW_ALL,
+	LEADERBOARD_WINDOW_WEEK,
+	LEADERBOARD_WINDOW_MONTH,
+	LEADERBOARD_WINDOW_SEASON,
+}
+
+//Returns start of window containing now, zero time for all-time window
+func LeaderboardWindowStart(window string, now time.Time) time.Time {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch window {
+	case LEADERBOARD_WINDOW_WEEK:
+		return today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	case LEADERBOARD_WINDOW_MONTH:
+		return today.AddDate(0, 0, 1-today.Day())
+	case LEADERBOARD_WINDOW_SEASON:
+		return time.Date(now.Year(), (now.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Time{}
+}
+
+type LeaderboardQuery struct {
+	// One of LeaderboardMetrics
+	Metric string
+	// Any game if 0
+	GameId int
+	// One of LeaderboardWindows
+	Window string
+	// Counts tournaments of the season instead of ones of GameId played within Window if not 0
+	SeasonId uint
+	// Currency of winnings && net profit, ignored by other metrics
+	Currency string
+	// Player whose rank is looked up, none if 0
+	UserId uint
+	Limit  int
+	Offset int
+}
+
+// Players sharing a value share a rank, the next rank skips as many places as players shared it
+type LeaderboardEntry struct {
+	Rank   int     `json:"rank"`
+	UserId uint    `json:"user_id"`
+	Value  float64 `json:"value"`
+}
+
+type LeaderboardPage struct {
+	Entries []*LeaderboardEntry
+	Total   int
+	// Entry of queried user, nil if none was queried or user is not ranked
+	Me *LeaderboardEntry
+	// Leaderboards are cached, so they may be that old
+	ComputedAt time.Time
+}
+
+type LeaderboardMeta struct {
+	Total      int               `json:"total"`
+	ComputedAt time.Time         `json:"computed_at"`
+	Me         *LeaderboardEntry `json:"me,omitempty"`
+}
+
+type LeaderboardResponse struct {
+	Data []*LeaderboardEntry `json:"data"`
+	Meta *LeaderboardMeta    `json:"meta"`
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_PAGE_LIMIT = 20
+	MAX_PAGE_LIMIT     = 100
+)
+
+const (
+	CURSOR_DIRECTION_NEXT = "next"
+	CURSOR_DIRECTION_PREV = "prev"
+)
+
+//Returns limit bounded by 1..MAX_PAGE_LIMIT
+func PageLimit(limit int) int {
+	if limit < 1 {
+		return 1
+	}
+	if limit > MAX_PAGE_LIMIT {
+		return MAX_PAGE_LIMIT
+	}
+	return limit
+}
+
+// Columns tournaments could be sorted by, keyed by "sort" param value
+var TournamentSortFields = map[string]string{
+	"id":         "id",
+	"date":       "date",
+	"deposit":    "deposit",
+	"created_at": "created_at",
+}
+
+// Position in tournaments list sorted by Sort,
+// points to row having sort column value Value and ID
+type TournamentsCursor struct {
+	Sort      string      `json:"s"`
+	Value     interface{} `json:"v"`
+	ID        uint        `json:"id"`
+	Direction string      `json:"d"`
+}
+
+func EncodeCursor(cursor *TournamentsCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(encoded string) (*TournamentsCursor, error) {
+	cursor := &TournamentsCursor{}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("Incorrect cursor")
+	}
+	if err = json.Unmarshal(data, cursor); err != nil || cursor.ID == 0 {
+		return nil, errors.New("Incorrect cursor")
+	}
+	if cursor.Direction != CURSOR_DIRECTION_NEXT && cursor.Direction != CURSOR_DIRECTION_PREV {
+		return nil, errors.New("Incorrect cursor direction")
+	}
+	if _, ok := TournamentSortFields[strings.TrimPrefix(cursor.Sort, "-")]; !ok {
+		return nil, errors.New("Incorrect cursor sort")
+	}
+	return cursor, nil
+}
+
+type TournamentsQuery struct {
+	State        *uint
+	GameId       int
+	DateFrom     time.Time
+	DateTo       time.Time
+	HasFreeSeats *bool
+	PlayerId     uint
+	// column name from TournamentSortFields, "-" prefixed for descending order
+	Sort   string
+	Cursor *TournamentsCursor
+	Limit  int
+	Offset int
+}
+
+type TournamentsPage struct {
+	Tournaments []*Tournament
+	Total       int
+	NextCursor  string
+	PrevCursor  string
+}
+
+type PageMeta struct {
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+type PageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+type TournamentsListResponse struct {
+	Data  []*Tournament `json:"data"`
+	Meta  *PageMeta     `json:"meta"`
+	Links *PageLinks    `json:"links"`
+}
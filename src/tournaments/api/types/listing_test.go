@@ -0,0 +1,72 @@
+package types
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	raw := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+	cases := []struct {
+		name    string
+		encoded string
+		cursor  *TournamentsCursor
+		err     string
+	}{
+		{
+			name:    "encoded cursor",
+			encoded: EncodeCursor(&TournamentsCursor{Sort: "-date", Value: "2018-03-10T18:00:00Z", ID: 7, Direction: CURSOR_DIRECTION_NEXT}),
+			cursor:  &TournamentsCursor{Sort: "-date", Value: "2018-03-10T18:00:00Z", ID: 7, Direction: CURSOR_DIRECTION_NEXT},
+		},
+		{
+			name:    "numeric value is decoded as JSON number",
+			encoded: EncodeCursor(&TournamentsCursor{Sort: "deposit", Value: 100, ID: 3, Direction: CURSOR_DIRECTION_PREV}),
+			cursor:  &TournamentsCursor{Sort: "deposit", Value: float64(100), ID: 3, Direction: CURSOR_DIRECTION_PREV},
+		},
+		{name: "not base64", encoded: "%%%", err: "Incorrect cursor"},
+		{name: "padded base64", encoded: base64.URLEncoding.EncodeToString([]byte(`{"s":"id","v":1,"id":1,"d":"next"}`)), err: "Incorrect cursor"},
+		{name: "not JSON", encoded: raw(`id:1`), err: "Incorrect cursor"},
+		{name: "no ID", encoded: raw(`{"s":"id","v":1,"d":"next"}`), err: "Incorrect cursor"},
+		{name: "unknown direction", encoded: raw(`{"s":"id","v":1,"id":1,"d":"back"}`), err: "Incorrect cursor direction"},
+		{name: "unknown sort", encoded: raw(`{"s":"name","v":"a","id":1,"d":"next"}`), err: "Incorrect cursor sort"},
+		{name: "descending unknown sort", encoded: raw(`{"s":"-name","v":"a","id":1,"d":"next"}`), err: "Incorrect cursor sort"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(c.encoded)
+			if c.err != "" {
+				if err == nil || err.Error() != c.err {
+					t.Fatalf("got error %v, want %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cursor, c.cursor) {
+				t.Errorf("got %+v, want %+v", cursor, c.cursor)
+			}
+		})
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	cases := []struct {
+		limit, clamped int
+	}{
+		{-5, 1},
+		{0, 1},
+		{1, 1},
+		{20, 20},
+		{MAX_PAGE_LIMIT, MAX_PAGE_LIMIT},
+		{MAX_PAGE_LIMIT + 1, MAX_PAGE_LIMIT},
+	}
+	for _, c := range cases {
+		if clamped := PageLimit(c.limit); clamped != c.clamped {
+			t.Errorf("PageLimit(%d) got %d, want %d", c.limit, clamped, c.clamped)
+		}
+	}
+}
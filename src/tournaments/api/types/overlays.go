@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// Guaranteed prize pool of finished tournament and its parts funded by deposits, sponsor && the house
+type TournamentOverlay struct {
+	TournamentId   uint      `json:"tournament_id"`
+	Date           time.Time `json:"date"`
+	Currency       string    `json:"currency"`
+	GuaranteedPool int       `json:"guaranteed_pool"`
+	// Deposits of players && backers
+	Collected int `json:"collected"`
+	PrizePool int `json:"prize_pool"`
+	Overlay   int `json:"overlay"`
+}
+
+type OverlaysQuery struct {
+	// Filter, ignored if empty
+	Currency string
+	// Leaves out tournaments guarantees of which were covered by deposits && prize pool
+	OverlaidOnly bool
+	Limit        int
+	Offset       int
+}
+
+type OverlaysPage struct {
+	Overlays []*TournamentOverlay
+	Total    int
+	// Sum of overlays of every tournament matching query
+	TotalOverlay int
+}
+
+type OverlaysMeta struct {
+	Total        int `json:"total"`
+	TotalOverlay int `json:"total_overlay"`
+}
+
+type OverlaysResponse struct {
+	Data []*TournamentOverlay `json:"data"`
+	Meta *OverlaysMeta        `json:"meta"`
+}
@@ -0,0 +1,68 @@
+package types
+
+import "time"
+
+// Glicko-2 rating of new player
+const (
+	RATING_INITIAL            = 1500.0
+	RATING_INITIAL_DEVIATION  = 350.0
+	RATING_INITIAL_VOLATILITY = 0.06
+)
+
+// Player skill in a game updated by every finished tournament of the game player took part in,
+// deviation shows how uncertain the rating is, volatility how erratic player's performance is
+type Rating struct {
+	ID          uint      `json:"-"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	UserId      uint      `gorm:"unique_index:idx_ratings_user_id_game_id" json:"user_id"`
+	GameId      int       `gorm:"unique_index:idx_ratings_user_id_game_id" json:"game_id"`
+	Rating      float64   `json:"rating"`
+	Deviation   float64   `json:"deviation"`
+	Volatility  float64   `json:"volatility"`
+	Tournaments int       `json:"tournaments"`
+}
+
+// Change of rating by tournament
+type RatingChange struct {
+	ID              uint      `json:"id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UserId          uint      `sql:"index" json:"user_id"`
+	GameId          int       `json:"game_id"`
+	TournamentId    uint      `sql:"index" json:"tournament_id"`
+	RatingBefore    float64   `json:"rating_before"`
+	Rating          float64   `json:"rating"`
+	DeviationBefore float64   `json:"deviation_before"`
+	Deviation       float64   `json:"deviation"`
+	Volatility      float64   `json:"volatility"`
+}
+
+type RatingsQuery struct {
+	UserId uint
+	// Any game if 0
+	GameId int
+}
+
+type RatingHistoryQuery struct {
+	UserId uint
+	// Any game if 0
+	GameId int
+	Limit  int
+	Offset int
+}
+
+type RatingHistoryPage struct {
+	Changes []*RatingChange
+	Total   int
+}
+
+type RatingHistoryResponse struct {
+	Data []*RatingChange `json:"data"`
+	Meta *PageMeta       `json:"meta"`
+}
+
+// Result of rebuilding every rating from finished tournaments
+type RatingsRecomputation struct {
+	RecomputedAt time.Time `json:"recomputed_at"`
+	Tournaments  int       `json:"tournaments"`
+	Ratings      int       `json:"ratings"`
+}
@@ -0,0 +1,82 @@
+package types
+
+import "time"
+
+// Kinds of rows having no parent they refer to
+const (
+	ORPHAN_OPERATION_WITHOUT_BALANCE    = "operation_without_balance"
+	ORPHAN_OPERATION_WITHOUT_TOURNAMENT = "operation_without_tournament"
+	ORPHAN_PLAYER_WITHOUT_TOURNAMENT    = "player_without_tournament"
+	ORPHAN_BACKER_WITHOUT_PLAYER        = "backer_without_player"
+	ORPHAN_WINNER_WITHOUT_PLAYER        = "winner_without_player"
+)
+
+type ReconcileRequest struct {
+	// Write correction operation for every drifted balance if true, just report otherwise
+	Fix bool `json:"fix"`
+}
+
+// User wallet balance differing from the sum of user operations
+// or held points differing from the sum of user's holds
+type BalanceDiscrepancy struct {
+	UserId   uint   `json:"user_id"`
+	Currency string `json:"currency"`
+	Balance  int    `json:"balance"`
+	// Sum of user operations, credited points positive, debited ones negative
+	Expected int `json:"expected"`
+	Held     int `json:"held"`
+	// Sum of user's holds not captured or released yet
+	ExpectedHeld int `json:"expected_held"`
+}
+
+// Tournament which participants' deposits or spread prizes differ from its ledger operations
+type EscrowMismatch struct {
+	TournamentId uint `json:"tournament_id"`
+	State        uint `json:"state"`
+	// Sum of players' and backers' deposits, 0 for cancelled tournament
+	Deposits int `json:"deposits"`
+	// Sum of "join" operations less "refund" ones, tournaments played before kinds were introduced are not checked
+	Debited int `json:"debited"`
+	// Sum of deposits held until registration closes
+	Held int `json:"held"`
+	// Sum of winners' prizes as spread between winners and their backers
+	Prizes int `json:"prizes"`
+	// Sum of "prize" operations
+	Credited int `json:"credited"`
+}
+
+type OrphanRow struct {
+	Kind  string `json:"kind"`
+	Table string `json:"table"`
+	ID    uint   `json:"id"`
+}
+
+// Corrective entry written by reconciliation: "correction" operation of Amount makes the sum of operations
+// equal stored Balance, held points are reset to the sum of holds
+type BalanceCorrection struct {
+	ID        uint      `json:"id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UserId    uint      `json:"user_id"`
+	Currency  string    `gorm:"not null;default:'points'" json:"currency"`
+	Balance   int       `json:"balance"`
+	// Sum of operations before correction
+	OldExpected int `json:"old_expected"`
+	// Credited points positive, debited ones negative, 0 if just held points are corrected
+	Amount  int `json:"amount"`
+	OldHeld int `json:"old_held"`
+	NewHeld int `json:"new_held"`
+}
+
+type ReconciliationReport struct {
+	CheckedAt        time.Time             `json:"checked_at"`
+	BalancesChecked  int                   `json:"balances_checked"`
+	Discrepancies    []*BalanceDiscrepancy `json:"discrepancies"`
+	EscrowMismatches []*EscrowMismatch     `json:"escrow_mismatches"`
+	Orphans          []*OrphanRow          `json:"orphans"`
+	Corrections      []*BalanceCorrection  `json:"corrections"`
+}
+
+//True if nothing left to fix or investigate
+func (r *ReconciliationReport) Consistent() bool {
+	return len(r.Discrepancies) == len(r.Corrections) && len(r.EscrowMismatches) == 0 && len(r.Orphans) == 0
+}
@@ -0,0 +1,127 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const NOTIFICATION_KIND_SEASON_REWARD = "season_reward"
+
+// Points race over finished tournaments of a game played from StartsAt till EndsAt,
+// players score points of places they take in tournaments; rewards are paid to top players as season ends
+type Season struct {
+	ID        uint      `json:"id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Name      string    `json:"name"`
+	GameId    int       `sql:"index" json:"game_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	// Tournaments dated before it count
+	EndsAt time.Time `json:"ends_at"`
+	// Currency of rewards, one of Currencies
+	Currency string `gorm:"not null;default:'points'" json:"currency"`
+	// Set as rewards are paid
+	PaidAt *time.Time `json:"paid_at,omitempty"`
+	// Season points of tournament places, places not listed score nothing
+	PlacePoints []*SeasonPlacePoints `sql:"-" json:"place_points"`
+	// Rewards of season standings places
+	Rewards []*SeasonReward `sql:"-" json:"rewards"`
+}
+
+type SeasonPlacePoints struct {
+	ID       uint `json:"-"`
+	SeasonId uint `sql:"index" json:"-"`
+	Place    int  `json:"place"`
+	Points   int  `json:"points"`
+}
+
+type SeasonReward struct {
+	ID       uint `json:"-"`
+	SeasonId uint `sql:"index" json:"-"`
+	Place    int  `json:"place"`
+	Amount   int  `json:"amount"`
+}
+
+// Reward credited to player by the end-of-season payout
+type SeasonPayout struct {
+	ID          uint      `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+	SeasonId    uint      `sql:"index" json:"season_id"`
+	UserId      uint      `json:"user_id"`
+	Rank        int       `json:"rank"`
+	Amount      int       `json:"amount"`
+	OperationId uint      `json:"operation_id"`
+}
+
+// Players sharing points share a rank && split rewards of places they take evenly
+type SeasonStanding struct {
+	Rank   int  `json:"rank"`
+	UserId uint `json:"user_id"`
+	Points int  `json:"points"`
+	// Tournaments player scored in
+	Tournaments int `json:"tournaments"`
+	// Paid as season ends
+	Reward int `json:"reward,omitempty"`
+}
+
+type SeasonRequest struct {
+	Name     string    `json:"name" validate:"required,max=64"`
+	GameId   int       `json:"game_id" validate:"required"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required"`
+	Currency string    `json:"currency,omitempty" validate:"oneof=points|bonus|tokens"`
+	// Season points of tournament places 1, 2, ...
+	PlacePoints []int `json:"place_points" validate:"required"`
+	// Rewards of season standings places 1, 2, ...
+	Rewards []int `json:"rewards,omitempty"`
+}
+
+func (r *SeasonRequest) validate(prefix string) (errs ValidationErrors) {
+	if !r.EndsAt.After(r.StartsAt) {
+		errs = errs.add(prefix+"ends_at", "must be after starts_at")
+	}
+	for i, points := range r.PlacePoints {
+		if points < 0 {
+			errs = errs.add(fmt.Sprintf("%splace_points[%d]", prefix, i), "must be at least 0")
+		}
+	}
+	for i, amount := range r.Rewards {
+		if amount < 0 {
+			errs = errs.add(fmt.Sprintf("%srewards[%d]", prefix, i), "must be at least 0")
+		}
+	}
+	return errs
+}
+
+type SeasonsQuery struct {
+	// Any game if 0
+	GameId int
+	Limit  int
+	Offset int
+}
+
+type SeasonsPage struct {
+	Seasons []*Season
+	Total   int
+}
+
+type SeasonsResponse struct {
+	Data []*Season `json:"data"`
+	Meta *PageMeta `json:"meta"`
+}
+
+type SeasonStandingsQuery struct {
+	SeasonId uint
+	Limit    int
+	Offset   int
+}
+
+type SeasonStandingsPage struct {
+	Standings []*SeasonStanding
+	Total     int
+}
+
+type SeasonStandingsResponse struct {
+	Data []*SeasonStanding `json:"data"`
+	Meta *PageMeta         `json:"meta"`
+}
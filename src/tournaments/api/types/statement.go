@@ -0,0 +1,99 @@
+package types
+
+import "time"
+
+// Reasons of balance operations
+const (
+	OPERATION_KIND_FUND  = "fund"
+	OPERATION_KIND_TAKE  = "take"
+	OPERATION_KIND_JOIN  = "join"
+	OPERATION_KIND_PRIZE = "prize"
+	// Both legs of a transfer between users
+	OPERATION_KIND_TRANSFER = "transfer"
+	// Transfer fee paid by sender to the house
+	OPERATION_KIND_FEE = "fee"
+	// Captured deposit returned as tournament is cancelled
+	OPERATION_KIND_REFUND = "refund"
+	// Points of expired lot taken away
+	OPERATION_KIND_EXPIRE = "expire"
+	// Voucher amount credited to user redeeming it
+	OPERATION_KIND_VOUCHER = "voucher"
+	// Tournament prize pool debited from sponsor at announcement
+	OPERATION_KIND_SPONSOR = "sponsor"
+	// Unspent part of prize pool returned to sponsor
+	OPERATION_KIND_SPONSOR_RETURN = "sponsor_return"
+	// Shortfall of guaranteed prize pool debited from the house
+	OPERATION_KIND_OVERLAY = "overlay"
+	// End-of-season reward credited to player && debited from the house
+	OPERATION_KIND_SEASON_REWARD = "season_reward"
+	// Value of satellite ticket credited to holder as ticket expires unused or its tournament is cancelled
+	OPERATION_KIND_TICKET_REFUND = "ticket_refund"
+	// Reconciliation entry making the sum of operations equal stored balance
+	OPERATION_KIND_CORRECTION = "correction"
+	// Operation recorded before kinds were introduced, its reason && tournament are unknown
+	OPERATION_KIND_LEGACY = "legacy"
+)
+
+var OperationKinds = []string{
+	OPERATION_KIND_FUND,
+	OPERATION_KIND_TAKE,
+	OPERATION_KIND_JOIN,
+	OPERATION_KIND_PRIZE,
+	OPERATION_KIND_TRANSFER,
+	OPERATION_KIND_FEE,
+	OPERATION_KIND_REFUND,
+	OPERATION_KIND_EXPIRE,
+	OPERATION_KIND_VOUCHER,
+	OPERATION_KIND_SPONSOR,
+	OPERATION_KIND_SPONSOR_RETURN,
+	OPERATION_KIND_OVERLAY,
+	OPERATION_KIND_SEASON_REWARD,
+	OPERATION_KIND_TICKET_REFUND,
+	OPERATION_KIND_CORRECTION,
+	OPERATION_KIND_LEGACY,
+}
+
+const (
+	STATEMENT_FORMAT_JSON  = "json"
+	STATEMENT_FORMAT_CSV   = "csv"
+	STATEMENT_FORMAT_JSONL = "jsonl"
+)
+
+// Row of user balance statement
+type StatementEntry struct {
+	ID           uint      `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Kind         string    `json:"kind"`
+	TournamentId uint      `json:"tournament_id,omitempty"`
+	// Positive for points credited to balance, negative for debited ones
+	Amount int `json:"amount"`
+	// Balance right after the operation
+	RunningBalance int `json:"running_balance"`
+}
+
+type StatementQuery struct {
+	UserId uint
+	// One of Currencies, running balance is computed over the wallet of the currency
+	Currency     string
+	Kinds        []string
+	DateFrom     time.Time
+	DateTo       time.Time
+	TournamentId uint
+	// Oldest entries first if true, newest first otherwise
+	Ascending bool
+	Limit     int
+	Offset    int
+	// Entries following operation of AfterId in query order only, 0 for any;
+	// keeps pages stable while new operations are written
+	AfterId uint
+}
+
+type StatementPage struct {
+	Entries []*StatementEntry
+	Total   int
+}
+
+type StatementResponse struct {
+	Data []*StatementEntry `json:"data"`
+	Meta *PageMeta         `json:"meta"`
+}
@@ -0,0 +1,105 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Shares of team members add up to it
+const TEAM_SHARES_TOTAL = 100
+
+// Players entering team tournaments together, the captain is one of members
+type Team struct {
+	ID        uint          `json:"id,omitempty"`
+	CreatedAt time.Time     `json:"created_at,omitempty"`
+	UpdatedAt time.Time     `json:"updated_at,omitempty"`
+	Name      string        `json:"name"`
+	CaptainId uint          `json:"captain_id"`
+	Members   []*TeamMember `sql:"-" json:"members"`
+}
+
+// Member pays Share percents of team entry deposits && gets Share percents of team prizes,
+// the captain pays && gets indivisible remainders
+type TeamMember struct {
+	ID     uint `json:"-"`
+	TeamId uint `sql:"index" json:"-"`
+	UserId uint `json:"user_id"`
+	Share  int  `json:"share"`
+}
+
+// Team entered in tournament, members && their shares are fixed as team registers
+type TournamentTeam struct {
+	ID           uint      `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	TournamentId uint      `sql:"index" json:"tournament_id"`
+	TeamId       uint      `json:"team_id"`
+	// Owner of entry deposits held on members' balances
+	CaptainId uint `json:"captain_id"`
+	Deposit   int  `json:"deposit"`
+}
+
+type TournamentTeamMember struct {
+	ID           uint `json:"-"`
+	TournamentId uint `sql:"index" json:"-"`
+	TeamId       uint `json:"-"`
+	UserId       uint `json:"user_id"`
+	Share        int  `json:"share"`
+	Deposit      int  `json:"deposit"`
+}
+
+type TeamRequest struct {
+	Name      string               `json:"name" validate:"required,max=64"`
+	CaptainId uint                 `json:"captain_id" validate:"required"`
+	Members   []*TeamMemberRequest `json:"members" validate:"required,max=100"`
+}
+
+type TeamMemberRequest struct {
+	UserId uint `json:"user_id" validate:"required"`
+	Share  int  `json:"share" validate:"min=0,max=100"`
+}
+
+func (r *TeamRequest) validate(prefix string) (errs ValidationErrors) {
+	seen := map[uint]bool{}
+	shares := 0
+	for i, member := range r.Members {
+		if member == nil {
+			errs = errs.add(fmt.Sprintf("%smembers[%d]", prefix, i), "is required")
+			continue
+		}
+		if member.UserId != 0 && seen[member.UserId] {
+			errs = errs.add(fmt.Sprintf("%smembers[%d].user_id", prefix, i), fmt.Sprintf("contains duplicate value %d", member.UserId))
+		}
+		seen[member.UserId] = true
+		shares += member.Share
+	}
+	if r.CaptainId != 0 && len(r.Members) > 0 && !seen[r.CaptainId] {
+		errs = errs.add(prefix+"captain_id", "must be one of members")
+	}
+	if len(r.Members) > 0 && shares != TEAM_SHARES_TOTAL {
+		errs = errs.add(prefix+"members", fmt.Sprintf("shares must add up to %d", TEAM_SHARES_TOTAL))
+	}
+	return errs
+}
+
+type TeamEntryRequest struct {
+	TournamentId uint `json:"tournament_id" validate:"required"`
+	TeamId       uint `json:"team_id" validate:"required"`
+}
+
+type TournamentTeamEntryRequest struct {
+	TeamId uint `json:"team_id" validate:"required"`
+}
+
+func (r *TournamentTeamEntryRequest) TeamEntryRequest(tournamentId uint) *TeamEntryRequest {
+	return &TeamEntryRequest{
+		TournamentId: tournamentId,
+		TeamId:       r.TeamId,
+	}
+}
+
+type TournamentTeamDetails struct {
+	TeamId    uint                    `json:"team_id"`
+	CaptainId uint                    `json:"captain_id"`
+	Deposit   int                     `json:"deposit"`
+	Members   []*TournamentTeamMember `json:"members"`
+}
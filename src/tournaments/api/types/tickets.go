@@ -0,0 +1,61 @@
+package types
+
+import "time"
+
+const NOTIFICATION_KIND_TICKET = "ticket"
+
+const (
+	// Ticket could be used to join its tournament until ExpiresAt
+	TICKET_STATE_ISSUED = "issued"
+	// Ticket paid for an entry, it's issued again as the entry is withdrawn
+	TICKET_STATE_USED = "used"
+	// Ticket value is credited to holder by "ticket_refund" operation
+	TICKET_STATE_REFUNDED = "refunded"
+	// Ticket of satellite not refunding tickets expired unused
+	TICKET_STATE_EXPIRED = "expired"
+)
+
+var TicketStates = []string{
+	TICKET_STATE_ISSUED,
+	TICKET_STATE_USED,
+	TICKET_STATE_REFUNDED,
+	TICKET_STATE_EXPIRED,
+}
+
+// Seat in TournamentId won in satellite tournament, covers the whole entry deposit in place of a deposit debit.
+// Unused ticket is refunded to points as it expires if satellite says so, ticket of cancelled tournament is refunded always
+type Ticket struct {
+	ID        uint      `json:"id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	UserId    uint      `sql:"index" json:"user_id"`
+	// Satellite ticket was won in
+	SatelliteId  uint `json:"satellite_id"`
+	TournamentId uint `sql:"index" json:"tournament_id"`
+	// Deposit of TournamentId as ticket was issued, refunded in Currency
+	Value    int    `json:"value"`
+	Currency string `gorm:"not null;default:'points'" json:"currency"`
+	State    string `sql:"index" json:"state"`
+	// Start of TournamentId, ticket could not be used since then
+	ExpiresAt  time.Time  `sql:"index" json:"expires_at"`
+	UsedAt     *time.Time `json:"used_at,omitempty"`
+	RefundedAt *time.Time `json:"refunded_at,omitempty"`
+}
+
+type TicketsQuery struct {
+	UserId uint
+	// Any state if empty
+	State  string
+	Limit  int
+	Offset int
+}
+
+type TicketsPage struct {
+	Tickets []*Ticket
+	Total   int
+}
+
+type TicketsResponse struct {
+	Data []*Ticket `json:"data"`
+	Meta *PageMeta `json:"meta"`
+}
@@ -0,0 +1,92 @@
+package types
+
+import "time"
+
+const (
+	TRANSFER_DIRECTION_IN  = "in"
+	TRANSFER_DIRECTION_OUT = "out"
+)
+
+// Points moved from one user to another, sender pays Amount + Fee, recipient gets Amount,
+// the house gets Fee
+type Transfer struct {
+	ID         uint      `json:"id,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	FromUserId uint      `sql:"index" json:"from_user_id"`
+	ToUserId   uint      `sql:"index" json:"to_user_id"`
+	Amount     int       `json:"amount"`
+	Fee        int       `json:"fee"`
+	Currency   string    `gorm:"not null;default:'points'" json:"currency"`
+	Comment    string    `json:"comment,omitempty"`
+}
+
+// Per-user daily transfers limit overriding the default one
+type TransferLimit struct {
+	ID        uint      `json:"id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	UserId    uint      `gorm:"unique_index" json:"user_id"`
+	// Sum of amounts user may send per day (UTC) in each currency, 0 for unlimited
+	DailyLimit int `json:"daily_limit"`
+}
+
+type TransferRequest struct {
+	FromUserId uint   `json:"from_user_id" validate:"required"`
+	ToUserId   uint   `json:"to_user_id" validate:"required"`
+	Amount     int    `json:"amount" validate:"min=1"`
+	Currency   string `json:"currency,omitempty" validate:"oneof=points|bonus|tokens"`
+	Comment    string `json:"comment,omitempty" validate:"max=255"`
+}
+
+func (r *TransferRequest) validate(prefix string) (errs ValidationErrors) {
+	if r.FromUserId != 0 && r.FromUserId == r.ToUserId {
+		errs = errs.add(prefix+"to_user_id", "user could not transfer points to himself")
+	}
+	return errs
+}
+
+// Body of POST /users/:id/transfers, sender is taken from path
+type UserTransferRequest struct {
+	ToUserId uint   `json:"to_user_id" validate:"required"`
+	Amount   int    `json:"amount" validate:"min=1"`
+	Currency string `json:"currency,omitempty" validate:"oneof=points|bonus|tokens"`
+	Comment  string `json:"comment,omitempty" validate:"max=255"`
+}
+
+func (r *UserTransferRequest) TransferRequest(fromUserId uint) *TransferRequest {
+	return &TransferRequest{
+		FromUserId: fromUserId,
+		ToUserId:   r.ToUserId,
+		Amount:     r.Amount,
+		Currency:   r.Currency,
+		Comment:    r.Comment,
+	}
+}
+
+type TransferLimitRequest struct {
+	UserId     uint `json:"user_id" validate:"required"`
+	DailyLimit int  `json:"daily_limit" validate:"min=0"`
+}
+
+// Body of PUT /admin/users/:id/transfer-limit, user is taken from path
+type UserTransferLimitRequest struct {
+	DailyLimit int `json:"daily_limit" validate:"min=0"`
+}
+
+type TransfersQuery struct {
+	UserId uint
+	// One of TRANSFER_DIRECTION_*, both directions if empty
+	Direction string
+	Limit     int
+	Offset    int
+}
+
+type TransfersPage struct {
+	Transfers []*Transfer
+	Total     int
+}
+
+type TransfersResponse struct {
+	Data []*Transfer `json:"data"`
+	Meta *PageMeta   `json:"meta"`
+}
@@ -1,16 +1,59 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type ApiStorage interface {
 	FetchTournament(uint) (interface{}, error)
+	FetchTournamentDetails(uint) (interface{}, error)
 	FetchTournaments(int, int) (interface{}, error)
-	FetchBalance(uint) (interface{}, error)
-	TakeAwayBalance(uint, int) (interface{}, error)
-	TopUpBalance(uint, int) (interface{}, error)
+	SearchTournaments(*TournamentsQuery) (interface{}, error)
+	FetchBalance(uint, string) (interface{}, error)
+	FetchBalances([]uint) (interface{}, error)
+	FetchBalanceAsOf(uint, string, time.Time) (interface{}, error)
+	FetchBalancesAsOf(*BalancesAsOfQuery) (interface{}, error)
+	FetchStatement(*StatementQuery) (interface{}, error)
+	FetchTournamentPlayers([]uint) (interface{}, error)
+	FetchTournamentBackers([]uint) (interface{}, error)
+	FetchTournamentWinners([]uint) (interface{}, error)
+	TakeAwayBalance(uint, string, int) (interface{}, error)
+	TopUpBalance(uint, string, int, *time.Time) (interface{}, error)
+	FetchBalanceLots(uint, string) (interface{}, error)
+	FetchNotifications(*NotificationsQuery) (interface{}, error)
+	CreateVoucher(*VoucherRequest) (interface{}, error)
+	CreateVoucherBatch(*VoucherBatchRequest) (interface{}, error)
+	RedeemVoucher(*RedeemVoucherRequest) (interface{}, error)
+	FetchVoucherRedemptions(*VoucherRedemptionsQuery) (interface{}, error)
+	FetchOverlays(*OverlaysQuery) (interface{}, error)
+	GenerateBracket(*BracketRequest) (interface{}, error)
+	FetchBracket(uint) (interface{}, error)
+	SubmitMatchResult(*MatchResultRequest) (interface{}, error)
+	SubmitRoundResults(*RoundResultsRequest) (interface{}, error)
+	FetchRatings(*RatingsQuery) (interface{}, error)
+	FetchRatingHistory(*RatingHistoryQuery) (interface{}, error)
+	RecomputeRatings() (interface{}, error)
+	FetchLeaderboard(*LeaderboardQuery) (interface{}, error)
+	CreateSeason(*SeasonRequest) (interface{}, error)
+	FetchSeason(uint) (interface{}, error)
+	FetchSeasons(*SeasonsQuery) (interface{}, error)
+	FetchSeasonStandings(*SeasonStandingsQuery) (interface{}, error)
+	FetchTickets(*TicketsQuery) (interface{}, error)
+	CreateTeam(*TeamRequest) (interface{}, error)
+	FetchTeam(uint) (interface{}, error)
+	JoinTournamentByTeam(*TeamEntryRequest) error
+	WithdrawTeamFromTournament(*TeamEntryRequest) error
 	CreateNewTournament(*AnnounceTournamentRequest) (interface{}, error)
 	JoinTournamentAndTakePointsFromUserBalances(*JoinTournamentRequest) error
+	WithdrawFromTournament(*WithdrawTournamentRequest) error
+	CloseTournamentRegistration(uint) error
+	CancelTournament(uint) error
 	CheckAndSpreadTournamentPrize(*ResultTournamentRequest) error
+	Reconcile(bool) (interface{}, error)
+	TransferPoints(*TransferRequest) (interface{}, error)
+	FetchTransfers(*TransfersQuery) (interface{}, error)
+	SetTransferLimit(*TransferLimitRequest) (interface{}, error)
 }
 
 type Tournament struct {
@@ -22,6 +65,54 @@ type Tournament struct {
 	Deposit   int        `json:"deposit"` // let's don't use float32 to bonus points!
 	GameId    int        `json:"game_id,omitempty"`
 	State     uint
+	// 0 means unlimited
+	MaxPlayers int `json:"max_players"`
+	// Deposits are held on stakeholders' balances until registration closes
+	RegistrationClosedAt *time.Time `json:"registration_closed_at,omitempty"`
+	// Currency of deposits && prizes, one of Currencies
+	Currency string `gorm:"not null;default:'points'" json:"currency"`
+	// User pre-funding PrizePool at announcement, 0 if tournament is not sponsored
+	SponsorId uint `json:"sponsor_id,omitempty"`
+	// Prizes are paid from it first, free-roll (0 deposit) tournaments have no other source of prizes
+	PrizePool int `gorm:"not null;default:0" json:"prize_pool,omitempty"`
+	// Part of PrizePool returned to sponsor as tournament is cancelled or its prizes don't exhaust the pool
+	PrizePoolReturned int `gorm:"not null;default:0" json:"prize_pool_returned,omitempty"`
+	// Prizes add up to it at least, 0 if nothing is guaranteed
+	GuaranteedPool int `gorm:"not null;default:0" json:"guaranteed_pool,omitempty"`
+	// Part of GuaranteedPool not covered by collected deposits && PrizePool, funded by the house as results are saved
+	Overlay int `gorm:"not null;default:0" json:"overlay,omitempty"`
+	// One of TournamentFormats, results are submitted per match of generated bracket; empty for a flat winners list
+	Format string `json:"format,omitempty"`
+	// Bounds of player's Rating in tournament game, 0 means unbounded; unrated players have RATING_INITIAL
+	MinRating float64 `gorm:"not null;default:0" json:"min_rating,omitempty"`
+	MaxRating float64 `gorm:"not null;default:0" json:"max_rating,omitempty"`
+	// Only players invited by ID or holding an unused invitation code may join
+	InvitationOnly bool `gorm:"not null;default:false" json:"invitation_only,omitempty"`
+	// Only players listed among winners of the qualifier may join, 0 if there is none
+	QualifierId uint `gorm:"not null;default:0" json:"qualifier_id,omitempty"`
+	// Codes of invitations generated at announcement, responded to announcer only
+	InvitationCodes []string `sql:"-" json:"invitation_codes,omitempty"`
+	// Satellite awards Tickets to this tournament to its top places, 0 for tournaments awarding points only
+	TicketTournamentId uint `gorm:"not null;default:0" json:"ticket_tournament_id,omitempty"`
+	Tickets            int  `gorm:"not null;default:0" json:"tickets,omitempty"`
+	// Unused tickets are refunded to points as they expire, otherwise they expire worthless
+	TicketRefundable bool `gorm:"not null;default:false" json:"ticket_refundable,omitempty"`
+	// Entries are teams of so many members, MaxPlayers limits number of teams; 0 for individual entries
+	TeamSize int `gorm:"not null;default:0" json:"team_size,omitempty"`
+	// Played before operations recorded their tournaments, so its escrow could not be checked against ledger
+	LegacyLedger bool `gorm:"not null;default:false" json:"-"`
+}
+
+func (t *Tournament) IsSatellite() bool {
+	return t.TicketTournamentId != 0
+}
+
+func (t *Tournament) IsTeamTournament() bool {
+	return t.TeamSize > 0
+}
+
+func (t *Tournament) IsFreeRoll() bool {
+	return t.Deposit == 0
 }
 
 type UserPointsBalance struct {
@@ -29,33 +120,307 @@ type UserPointsBalance struct {
 	CreatedAt time.Time  `json:"created_at,omitempty"`
 	UpdatedAt time.Time  `json:"updated_at,omitempty"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
-	UserId    uint
-	Balance   int
+	UserId    uint       `gorm:"unique_index:idx_user_points_balances_user_id_currency"`
+	// One of Currencies, every user has a wallet per currency
+	Currency string `gorm:"not null;default:'points';unique_index:idx_user_points_balances_user_id_currency"`
+	Balance  int
+	// Part of Balance reserved by holds of not yet closed tournament registrations
+	Held int `gorm:"not null;default:0"`
+	// Balance - Held, the part user could spend
+	Available int `sql:"-"`
+}
+
+func (b *UserPointsBalance) AfterFind() error {
+	b.Available = b.Balance - b.Held
+	return nil
+}
+
+func (b *UserPointsBalance) AfterSave() error {
+	b.Available = b.Balance - b.Held
+	return nil
+}
+
+type TournamentPlayer struct {
+	ID           uint       `json:"id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at,omitempty"`
+	UpdatedAt    time.Time  `json:"updated_at,omitempty"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	TournamentId uint       `json:"tournament_id"`
+	UserId       uint       `json:"user_id"`
+	UserDeposit  int        `json:"user_deposit"`
+	// Ticket the seat is paid by, UserDeposit is ticket value never debited from player's balance; 0 for paid seats
+	TicketId uint `gorm:"not null;default:0" json:"ticket_id,omitempty"`
+}
+
+type TournamentBacker struct {
+	ID            uint       `json:"id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at,omitempty"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	TournamentId  uint       `json:"tournament_id"`
+	UserId        uint       `json:"user_id"`
+	BackerId      uint       `json:"backer_id"`
+	BackerDeposit int        `json:"backer_deposit"`
+}
+
+type TournamentWinner struct {
+	ID           uint       `json:"id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at,omitempty"`
+	UpdatedAt    time.Time  `json:"updated_at,omitempty"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	TournamentId uint       `json:"tournament_id"`
+	UserId       uint       `json:"user_id"`
+	Prize        int        `json:"prize"`
+	// Ticket won in satellite, 0 if there is none
+	TicketId uint `gorm:"not null;default:0" json:"ticket_id,omitempty"`
+	// Team of member getting its share of team Prize, 0 for individual winners
+	TeamId uint `gorm:"not null;default:0" json:"team_id,omitempty"`
 }
 
 type BalanceOperationRequest struct {
-	PlayerId uint `json:"player_id"`
-	Points   int  `json:"points"`
+	PlayerId uint   `json:"player_id" validate:"required"`
+	Points   int    `json:"points" validate:"min=1"`
+	Currency string `json:"currency,omitempty" validate:"oneof=points|bonus|tokens"`
+	// Funded points expire in given number of days, never if 0; ignored by take
+	ExpiresInDays int `json:"expires_in_days,omitempty" validate:"min=0"`
+}
+
+func (r *BalanceOperationRequest) ExpiresAt() *time.Time {
+	return expiresAt(r.ExpiresInDays)
 }
 
 type AnnounceTournamentRequest struct {
 	Date    time.Time `json:"date,omitempty"`
-	Deposit int       `json:"deposit"` // let's don't use float32 to bonus points!
-	GameId  int       `json:"game_id,omitempty"`
+	Deposit int       `json:"deposit" validate:"min=0"` // let's don't use float32 to bonus points!
+	GameId  int       `json:"game_id,omitempty" validate:"min=0"`
+	// 0 means unlimited
+	MaxPlayers int `json:"max_players,omitempty" validate:"min=0"`
+	// points by default
+	Currency string `json:"currency,omitempty" validate:"oneof=points|bonus|tokens"`
+	// Sponsor's wallet of Currency is debited by PrizePool at once
+	SponsorId uint `json:"sponsor_id,omitempty"`
+	PrizePool int  `json:"prize_pool,omitempty" validate:"min=0"`
+	// Shortfall of deposits && PrizePool is funded by the house as results are saved
+	GuaranteedPool int `json:"guaranteed_pool,omitempty" validate:"min=0"`
+	// Empty for results submitted as a flat winners list
+	Format string `json:"format,omitempty" validate:"oneof=single_elimination|double_elimination|round_robin|swiss"`
+	// Entry restrictions, see Tournament
+	MinRating float64 `json:"min_rating,omitempty"`
+	MaxRating float64 `json:"max_rating,omitempty"`
+	// Makes tournament invitation-only along with InvitationCodes
+	InvitedUserIds []uint `json:"invited_user_ids,omitempty" validate:"unique"`
+	// Number of single-use invitation codes to generate
+	InvitationCodes int  `json:"invitation_codes,omitempty" validate:"min=0,max=1000"`
+	QualifierId     uint `json:"qualifier_id,omitempty"`
+	// Makes tournament a satellite, see Tournament
+	TicketTournamentId uint `json:"ticket_tournament_id,omitempty"`
+	Tickets            int  `json:"tickets,omitempty" validate:"min=0,max=1000"`
+	TicketRefundable   bool `json:"ticket_refundable,omitempty"`
+	// Makes tournament entered by teams, see Tournament
+	TeamSize int `json:"team_size,omitempty" validate:"min=0,max=100"`
+}
+
+// TODO(h.lazar) pay attention to timezone
+func (r *AnnounceTournamentRequest) validate(prefix string) (errs ValidationErrors) {
+	if !r.Date.IsZero() && r.Date.Before(time.Now()) {
+		errs = errs.add(prefix+"date", "must not be in the past")
+	}
+	if r.SponsorId == 0 && r.PrizePool > 0 {
+		errs = errs.add(prefix+"sponsor_id", "is required for prize pool")
+	}
+	if r.SponsorId > 0 && r.PrizePool == 0 {
+		errs = errs.add(prefix+"prize_pool", "is required for sponsor")
+	}
+	if r.Deposit == 0 && r.PrizePool == 0 && r.GuaranteedPool == 0 {
+		errs = errs.add(prefix+"deposit", "must be positive unless prize pool is sponsored or guaranteed")
+	}
+	if r.MinRating < 0 {
+		errs = errs.add(prefix+"min_rating", "must be at least 0")
+	}
+	if r.MaxRating < 0 {
+		errs = errs.add(prefix+"max_rating", "must be at least 0")
+	}
+	if r.MaxRating > 0 && r.MaxRating < r.MinRating {
+		errs = errs.add(prefix+"max_rating", "must not be less than min_rating")
+	}
+	for i, userId := range r.InvitedUserIds {
+		if userId == 0 {
+			errs = errs.add(fmt.Sprintf("%sinvited_user_ids[%d]", prefix, i), "is required")
+		}
+	}
+	if r.TicketTournamentId != 0 && r.Tickets == 0 {
+		errs = errs.add(prefix+"tickets", "is required for satellite")
+	}
+	if r.TicketTournamentId == 0 && (r.Tickets > 0 || r.TicketRefundable) {
+		errs = errs.add(prefix+"ticket_tournament_id", "is required for tickets")
+	}
+	if r.TeamSize > 0 && r.Format != "" {
+		errs = errs.add(prefix+"format", "is not supported by team tournaments")
+	}
+	if r.TeamSize > 0 && r.TicketTournamentId != 0 {
+		errs = errs.add(prefix+"ticket_tournament_id", "is not supported by team tournaments")
+	}
+	return errs
 }
 
 type JoinTournamentRequest struct {
-	TournamentId uint   `json:"tournament_id"`
-	PlayerId     uint   `json:"player_id"`
-	BackerIds    []uint `json:"backer_ids,omitempty"`
+	TournamentId uint   `json:"tournament_id" validate:"required"`
+	PlayerId     uint   `json:"player_id" validate:"required"`
+	BackerIds    []uint `json:"backer_ids,omitempty" validate:"unique"`
+	// Required by invitation-only tournaments from players not invited by ID
+	InvitationCode string `json:"invitation_code,omitempty" validate:"max=32"`
+	// Player's ticket to tournament paying the whole deposit, entries by ticket could not be backed
+	TicketId uint `json:"ticket_id,omitempty"`
+}
+
+func (r *JoinTournamentRequest) validate(prefix string) (errs ValidationErrors) {
+	if r.TicketId != 0 && len(r.BackerIds) > 0 {
+		errs = errs.add(prefix+"backer_ids", "are not allowed along with ticket_id")
+	}
+	for i, backerId := range r.BackerIds {
+		name := fmt.Sprintf("%sbacker_ids[%d]", prefix, i)
+		if backerId == 0 {
+			errs = errs.add(name, "is required")
+		} else if backerId == r.PlayerId {
+			errs = errs.add(name, "player could not back himself")
+		}
+	}
+	return errs
 }
 
 type TournamentWinnerRequest struct {
-	PlayerId uint `json:"player_id"`
-	Prize    int  `json:"prize"`
+	// One of PlayerId && TeamId is required, the latter by team tournaments
+	PlayerId uint `json:"player_id,omitempty"`
+	TeamId   uint `json:"team_id,omitempty"`
+	Prize    int  `json:"prize" validate:"min=0"`
+	// Winner of satellite gets a ticket along with prize, if any
+	Ticket bool `json:"ticket,omitempty"`
+}
+
+func (r *TournamentWinnerRequest) validate(prefix string) (errs ValidationErrors) {
+	if r.PlayerId == 0 && r.TeamId == 0 {
+		errs = errs.add(prefix+"player_id", "is required unless team_id is given")
+	}
+	if r.PlayerId != 0 && r.TeamId != 0 {
+		errs = errs.add(prefix+"team_id", "is not allowed along with player_id")
+	}
+	if r.TeamId != 0 && r.Ticket {
+		errs = errs.add(prefix+"ticket", "is not allowed for teams")
+	}
+	return errs
 }
 
 type ResultTournamentRequest struct {
-	TournamentId uint                       `json:"tournament_id"`
-	Winners      []*TournamentWinnerRequest `json:"winners"`
+	TournamentId uint                       `json:"tournament_id" validate:"required"`
+	Winners      []*TournamentWinnerRequest `json:"winners" validate:"required"`
+}
+
+func (r *ResultTournamentRequest) validate(prefix string) (errs ValidationErrors) {
+	seen, seenTeams := map[uint]bool{}, map[uint]bool{}
+	for i, winner := range r.Winners {
+		name := fmt.Sprintf("%swinners[%d]", prefix, i)
+		if winner == nil {
+			errs = errs.add(name, "is required")
+			continue
+		}
+		if winner.PlayerId != 0 && seen[winner.PlayerId] {
+			errs = errs.add(name+".player_id", fmt.Sprintf("contains duplicate value %d", winner.PlayerId))
+		}
+		seen[winner.PlayerId] = true
+		if winner.TeamId != 0 && seenTeams[winner.TeamId] {
+			errs = errs.add(name+".team_id", fmt.Sprintf("contains duplicate value %d", winner.TeamId))
+		}
+		seenTeams[winner.TeamId] = true
+	}
+	return errs
+}
+
+type PointsRequest struct {
+	Points   int    `json:"points" validate:"min=1"`
+	Currency string `json:"currency,omitempty" validate:"oneof=points|bonus|tokens"`
+	// Topped up points expire in given number of days, never if 0; ignored by withdrawals
+	ExpiresInDays int `json:"expires_in_days,omitempty" validate:"min=0"`
+}
+
+func (r *PointsRequest) ExpiresAt() *time.Time {
+	return expiresAt(r.ExpiresInDays)
+}
+
+type TournamentEntryRequest struct {
+	PlayerId       uint   `json:"player_id" validate:"required"`
+	BackerIds      []uint `json:"backer_ids,omitempty" validate:"unique"`
+	InvitationCode string `json:"invitation_code,omitempty" validate:"max=32"`
+	TicketId       uint   `json:"ticket_id,omitempty"`
+}
+
+func (r *TournamentEntryRequest) validate(prefix string) ValidationErrors {
+	return r.JoinTournamentRequest(0).validate(prefix)
+}
+
+func (r *TournamentEntryRequest) JoinTournamentRequest(tournamentId uint) *JoinTournamentRequest {
+	return &JoinTournamentRequest{
+		TournamentId:   tournamentId,
+		PlayerId:       r.PlayerId,
+		BackerIds:      r.BackerIds,
+		InvitationCode: r.InvitationCode,
+		TicketId:       r.TicketId,
+	}
+}
+
+type TournamentResultsRequest struct {
+	Winners []*TournamentWinnerRequest `json:"winners" validate:"required"`
+}
+
+func (r *TournamentResultsRequest) validate(prefix string) ValidationErrors {
+	return r.ResultTournamentRequest(0).validate(prefix)
+}
+
+func (r *TournamentResultsRequest) ResultTournamentRequest(tournamentId uint) *ResultTournamentRequest {
+	return &ResultTournamentRequest{
+		TournamentId: tournamentId,
+		Winners:      r.Winners,
+	}
+}
+
+type GraphqlRequest struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type TournamentDetails struct {
+	*Tournament
+	// Deposits debited from balances: captured holds && entries made before holds were introduced,
+	// seats paid by tickets are not counted
+	TotalDeposits int `json:"total_deposits"`
+	// Deposits still held on balances until registration closes
+	TotalHeld   int                        `json:"total_held"`
+	TotalPrizes int                        `json:"total_prizes"`
+	Players     []*TournamentPlayerDetails `json:"players"`
+	Teams       []*TournamentTeamDetails   `json:"teams,omitempty"`
+	Winners     []*TournamentWinnerDetails `json:"winners"`
+}
+
+type TournamentPlayerDetails struct {
+	UserId  uint                       `json:"user_id"`
+	Deposit int                        `json:"deposit"`
+	Backers []*TournamentBackerDetails `json:"backers"`
+	// Ticket paying the seat, its value is Deposit && is counted neither in TotalDeposits nor in TotalHeld
+	TicketId uint `json:"ticket_id,omitempty"`
+}
+
+type TournamentBackerDetails struct {
+	BackerId uint `json:"backer_id"`
+	Deposit  int  `json:"deposit"`
+}
+
+type TournamentWinnerDetails struct {
+	UserId  uint                `json:"user_id"`
+	Prize   int                 `json:"prize"`
+	Payouts []*TournamentPayout `json:"payouts"`
+}
+
+type TournamentPayout struct {
+	UserId uint `json:"user_id"`
+	Points int  `json:"points"`
 }
@@ -0,0 +1,330 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const VALIDATION_TAG = `validate`
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type ValidationErrors []*FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, fieldError := range v {
+		messages = append(messages, fieldError.Field+": "+fieldError.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (v ValidationErrors) add(field, message string) ValidationErrors {
+	return append(v, &FieldError{Field: field, Message: message})
+}
+
+// Implemented by request types having rules which could not be expressed by tags
+// (cross-field checks and so on). Called after all tag rules are checked.
+type validatable interface {
+	validate(prefix string) ValidationErrors
+}
+
+// Request types checked by Validate, their tags are compiled by CheckValidationTags at startup
+var ValidatedRequests = []interface{}{
+	&AnnounceTournamentRequest{},
+	&JoinTournamentRequest{},
+	&ResultTournamentRequest{},
+	&PointsRequest{},
+	&BalanceOperationRequest{},
+	&TournamentEntryRequest{},
+	&TournamentResultsRequest{},
+	&GraphqlRequest{},
+	&ReconcileRequest{},
+	&TransferRequest{},
+	&UserTransferRequest{},
+	&TransferLimitRequest{},
+	&UserTransferLimitRequest{},
+	&WalletRequest{},
+	&VoucherRequest{},
+	&VoucherBatchRequest{},
+	&RedeemVoucherRequest{},
+	&UserRedeemVoucherRequest{},
+	&BracketRequest{},
+	&TournamentBracketRequest{},
+	&MatchResultRequest{},
+	&RoundResultsRequest{},
+	&TournamentRoundResultsRequest{},
+	&SeasonRequest{},
+	&WithdrawTournamentRequest{},
+	&TournamentIdRequest{},
+	&TeamRequest{},
+	&TeamEntryRequest{},
+	&TournamentTeamEntryRequest{},
+}
+
+// Rules of struct fields parsed from their tags, by field index
+type structRules struct {
+	fields [][]*rule
+	err    error
+}
+
+type rule struct {
+	name    string
+	limit   int64
+	options []string
+}
+
+// reflect.Type => *structRules
+var compiledRules sync.Map
+
+//Compiles `validate:"..."` tags of requests && structs nested in them,
+//returns error naming the field of first unknown or misplaced rule
+func CheckValidationTags(requests ...interface{}) error {
+	checked := map[reflect.Type]bool{}
+	for _, request := range requests {
+		if err := checkTypeRules(reflect.TypeOf(request), checked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkTypeRules(t reflect.Type, checked map[reflect.Type]bool) error {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) || checked[t] {
+		return nil
+	}
+	checked[t] = true
+	if compiled := rulesOf(t); compiled.err != nil {
+		return compiled.err
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			continue
+		}
+		if err := checkTypeRules(t.Field(i).Type, checked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rulesOf(t reflect.Type) *structRules {
+	if compiled, ok := compiledRules.Load(t); ok {
+		return compiled.(*structRules)
+	}
+	compiled, _ := compiledRules.LoadOrStore(t, compileRules(t))
+	return compiled.(*structRules)
+}
+
+func compileRules(t reflect.Type) *structRules {
+	compiled := &structRules{fields: make([][]*rule, t.NumField())}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup(VALIDATION_TAG)
+		if field.PkgPath != "" || !ok || tag == "-" {
+			continue
+		}
+		for _, spec := range strings.Split(tag, ",") {
+			fieldRule, err := parseRule(field.Type, spec)
+			if err != nil {
+				compiled.err = errors.New(fmt.Sprintf("Incorrect validation tag of %s.%s: %s", t.Name(), field.Name, err.Error()))
+				return compiled
+			}
+			if fieldRule != nil {
+				compiled.fields[i] = append(compiled.fields[i], fieldRule)
+			}
+		}
+	}
+	return compiled
+}
+
+func parseRule(fieldType reflect.Type, spec string) (*rule, error) {
+	parsed := &rule{name: strings.TrimSpace(spec)}
+	param := ""
+	if i := strings.Index(parsed.name, "="); i >= 0 {
+		parsed.name, param = parsed.name[:i], parsed.name[i+1:]
+	}
+	switch parsed.name {
+	case "required":
+	case "min", "max":
+		limit, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("incorrect %s rule parameter %q", parsed.name, param))
+		}
+		if !isMeasurable(fieldType.Kind()) {
+			return nil, errors.New(fmt.Sprintf("%s rule could not be applied to %s", parsed.name, fieldType))
+		}
+		parsed.limit = limit
+	case "oneof":
+		if param == "" {
+			return nil, errors.New("oneof rule requires options")
+		}
+		parsed.options = strings.Split(param, "|")
+	case "unique":
+		if fieldType.Kind() != reflect.Slice || !fieldType.Elem().Comparable() {
+			return nil, errors.New(fmt.Sprintf("unique rule could not be applied to %s", fieldType))
+		}
+	case "":
+		return nil, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown validation rule %q", parsed.name))
+	}
+	return parsed, nil
+}
+
+//Checks request struct against its `validate:"..."` field tags
+//supported rules are "required", "min=N", "max=N", "unique", "oneof=a|b|c";
+//for slices && strings "min" and "max" limit length, "unique" requires distinct elements,
+//"oneof" requires non-empty value to be one of listed ones.
+//Nested structs && slices of structs are checked recursively,
+//field names are reported by their JSON names, like "winners[0].prize".
+//Tags are compiled once per type, incorrect ones are reported as error of the field
+func Validate(request interface{}) ValidationErrors {
+	errs := validateValue(reflect.ValueOf(request), "")
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateValue(value reflect.Value, prefix string) (errs ValidationErrors) {
+	errs = ValidationErrors{}
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return errs
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return errs
+	}
+	valueType := value.Type()
+	compiled := rulesOf(valueType)
+	if compiled.err != nil {
+		return errs.add(strings.TrimSuffix(prefix, "."), compiled.err.Error())
+	}
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := prefix + jsonFieldName(field)
+		fieldValue := value.Field(i)
+		for _, fieldRule := range compiled.fields[i] {
+			if message := checkRule(fieldValue, fieldRule); message != "" {
+				errs = errs.add(name, message)
+			}
+		}
+		switch fieldValue.Kind() {
+		case reflect.Struct, reflect.Ptr:
+			if _, ok := fieldValue.Interface().(time.Time); !ok {
+				errs = append(errs, validateValue(fieldValue, name+".")...)
+			}
+		case reflect.Slice:
+			for j := 0; j < fieldValue.Len(); j++ {
+				errs = append(errs, validateValue(fieldValue.Index(j), fmt.Sprintf("%s[%d].", name, j))...)
+			}
+		}
+	}
+	if value.CanAddr() {
+		if v, ok := value.Addr().Interface().(validatable); ok {
+			errs = append(errs, v.validate(prefix)...)
+		}
+	}
+	return errs
+}
+
+func checkRule(value reflect.Value, fieldRule *rule) string {
+	switch fieldRule.name {
+	case "required":
+		if isZero(value) {
+			return "is required"
+		}
+	case "min", "max":
+		actual, isLength := measure(value)
+		if fieldRule.name == "min" && actual < fieldRule.limit {
+			if isLength {
+				return fmt.Sprintf("must contain at least %d item(s)", fieldRule.limit)
+			}
+			return fmt.Sprintf("must be greater than or equal to %d", fieldRule.limit)
+		}
+		if fieldRule.name == "max" && actual > fieldRule.limit {
+			if isLength {
+				return fmt.Sprintf("must contain at most %d item(s)", fieldRule.limit)
+			}
+			return fmt.Sprintf("must be less than or equal to %d", fieldRule.limit)
+		}
+	case "oneof":
+		if isZero(value) {
+			return ""
+		}
+		for _, option := range fieldRule.options {
+			if fmt.Sprint(value.Interface()) == option {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(fieldRule.options, ", ")
+	case "unique":
+		seen := map[interface{}]bool{}
+		for i := 0; i < value.Len(); i++ {
+			item := value.Index(i).Interface()
+			if seen[item] {
+				return fmt.Sprintf("contains duplicate value %v", item)
+			}
+			seen[item] = true
+		}
+	}
+	return ""
+}
+
+func isMeasurable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Slice, reflect.Map, reflect.String:
+		return true
+	}
+	return false
+}
+
+func measure(value reflect.Value) (int64, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(value.Uint()), false
+	case reflect.Slice, reflect.Map, reflect.String:
+		return int64(value.Len()), true
+	}
+	return 0, false
+}
+
+func isZero(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return value.Len() == 0
+	}
+	if t, ok := value.Interface().(time.Time); ok {
+		return t.IsZero()
+	}
+	return value.IsZero()
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
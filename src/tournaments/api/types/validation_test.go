@@ -0,0 +1,181 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type nestedOptions struct {
+	Tags []string `json:"tags" validate:"unique"`
+}
+
+type zeroValuesRequest struct {
+	Labels  map[string]int `json:"labels" validate:"required,max=2"`
+	Ids     []uint         `json:"ids" validate:"required"`
+	Options nestedOptions  `json:"options" validate:"required"`
+	Date    time.Time      `json:"date" validate:"required"`
+}
+
+type unknownRuleRequest struct {
+	Name string `json:"name" validate:"required,short"`
+}
+
+type misplacedRuleRequest struct {
+	Date time.Time `json:"date" validate:"min=1"`
+}
+
+type badLimitRequest struct {
+	Points int `json:"points" validate:"max=ten"`
+}
+
+type uncomparableUniqueRequest struct {
+	Groups [][]uint `json:"groups" validate:"unique"`
+}
+
+type emptyOneofRequest struct {
+	Kind string `json:"kind" validate:"oneof="`
+}
+
+type nestedBadTagRequest struct {
+	Rules []*unknownRuleRequest `json:"rules"`
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		request interface{}
+		errs    []string
+	}{
+		{
+			name:    "valid join",
+			request: &JoinTournamentRequest{TournamentId: 1, PlayerId: 2, BackerIds: []uint{3, 4}},
+		},
+		{
+			name:    "required fields",
+			request: &JoinTournamentRequest{},
+			errs:    []string{"tournament_id: is required", "player_id: is required"},
+		},
+		{
+			name:    "duplicate backers && self backing",
+			request: &JoinTournamentRequest{TournamentId: 1, PlayerId: 2, BackerIds: []uint{2, 2}},
+			errs: []string{
+				"backer_ids: contains duplicate value 2",
+				"backer_ids[0]: player could not back himself",
+				"backer_ids[1]: player could not back himself",
+			},
+		},
+		{
+			name:    "string length limit",
+			request: &JoinTournamentRequest{TournamentId: 1, PlayerId: 2, InvitationCode: strings.Repeat("K", 33)},
+			errs:    []string{"invitation_code: must contain at most 32 item(s)"},
+		},
+		{
+			name:    "backers along with ticket",
+			request: &JoinTournamentRequest{TournamentId: 1, PlayerId: 2, BackerIds: []uint{3}, TicketId: 4},
+			errs:    []string{"backer_ids: are not allowed along with ticket_id"},
+		},
+		{
+			name:    "number limits",
+			request: &PointsRequest{Points: 0, ExpiresInDays: -1},
+			errs:    []string{"points: must be greater than or equal to 1", "expires_in_days: must be greater than or equal to 0"},
+		},
+		{
+			name:    "unknown option",
+			request: &PointsRequest{Points: 1, Currency: "gold"},
+			errs:    []string{"currency: must be one of points, bonus, tokens"},
+		},
+		{
+			name:    "known option",
+			request: &PointsRequest{Points: 1, Currency: CURRENCY_BONUS},
+		},
+		{
+			name: "nested winners",
+			request: &ResultTournamentRequest{TournamentId: 1, Winners: []*TournamentWinnerRequest{
+				{PlayerId: 1, Prize: -1},
+				{PlayerId: 1, TeamId: 2},
+				nil,
+			}},
+			errs: []string{
+				"winners[0].prize: must be greater than or equal to 0",
+				"winners[1].team_id: is not allowed along with player_id",
+				"winners[1].player_id: contains duplicate value 1",
+				"winners[2]: is required",
+			},
+		},
+		{
+			name:    "empty winners",
+			request: &ResultTournamentRequest{TournamentId: 1, Winners: []*TournamentWinnerRequest{}},
+			errs:    []string{"winners: is required"},
+		},
+		{
+			name:    "zero values of any kind",
+			request: &zeroValuesRequest{},
+			errs: []string{
+				"labels: is required",
+				"ids: is required",
+				"options: is required",
+				"date: is required",
+			},
+		},
+		{
+			name: "filled values of any kind",
+			request: &zeroValuesRequest{
+				Labels:  map[string]int{"a": 1, "b": 2, "c": 3},
+				Ids:     []uint{1},
+				Options: nestedOptions{Tags: []string{"x", "x"}},
+				Date:    time.Date(2018, 3, 10, 18, 0, 0, 0, time.UTC),
+			},
+			errs: []string{"labels: must contain at most 2 item(s)", "options.tags: contains duplicate value x"},
+		},
+		{
+			name:    "incorrect tag",
+			request: &unknownRuleRequest{Name: "x"},
+			errs:    []string{`: Incorrect validation tag of unknownRuleRequest.Name: unknown validation rule "short"`},
+		},
+		{
+			name:    "incorrect nested tag",
+			request: &nestedBadTagRequest{Rules: []*unknownRuleRequest{{}}},
+			errs:    []string{`rules[0]: Incorrect validation tag of unknownRuleRequest.Name: unknown validation rule "short"`},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := []string{}
+			for _, fieldError := range Validate(c.request) {
+				actual = append(actual, fieldError.Field+": "+fieldError.Message)
+			}
+			if len(actual) != len(c.errs) || (len(c.errs) > 0 && !reflect.DeepEqual(actual, c.errs)) {
+				t.Errorf("got %q, want %q", actual, c.errs)
+			}
+		})
+	}
+}
+
+func TestCheckValidationTags(t *testing.T) {
+	if err := CheckValidationTags(ValidatedRequests...); err != nil {
+		t.Fatalf("request tags are incorrect: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		request interface{}
+		err     string
+	}{
+		{"unknown rule", &unknownRuleRequest{}, `Incorrect validation tag of unknownRuleRequest.Name: unknown validation rule "short"`},
+		{"min of time", &misplacedRuleRequest{}, "Incorrect validation tag of misplacedRuleRequest.Date: min rule could not be applied to time.Time"},
+		{"incorrect limit", &badLimitRequest{}, `Incorrect validation tag of badLimitRequest.Points: incorrect max rule parameter "ten"`},
+		{"unique of uncomparable", &uncomparableUniqueRequest{}, "Incorrect validation tag of uncomparableUniqueRequest.Groups: unique rule could not be applied to [][]uint"},
+		{"oneof without options", &emptyOneofRequest{}, "Incorrect validation tag of emptyOneofRequest.Kind: oneof rule requires options"},
+		{"nested struct", &nestedBadTagRequest{}, `Incorrect validation tag of unknownRuleRequest.Name: unknown validation rule "short"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := CheckValidationTags(c.request)
+			if err == nil || err.Error() != c.err {
+				t.Errorf("got %v, want %q", err, c.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+// Characters of generated voucher codes, ones easy to confuse (0, O, 1, I) are left out
+const VOUCHER_CODE_ALPHABET = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Promo code crediting Amount to wallet of Currency of every user redeeming it
+type Voucher struct {
+	ID        uint      `json:"id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Code      string    `gorm:"unique_index" json:"code"`
+	// Set for vouchers generated together by CreateVoucherBatch
+	BatchId  string `sql:"index" json:"batch_id,omitempty"`
+	Amount   int    `json:"amount"`
+	Currency string `gorm:"not null;default:'points'" json:"currency"`
+	// 0 means unlimited
+	MaxRedemptions int `json:"max_redemptions"`
+	// Redemptions per user, 0 means unlimited
+	PerUserLimit int `json:"per_user_limit"`
+	// Number of redemptions made
+	Redemptions int        `gorm:"not null;default:0" json:"redemptions"`
+	ValidFrom   *time.Time `json:"valid_from,omitempty"`
+	ValidUntil  *time.Time `json:"valid_until,omitempty"`
+	// Credited points expire in given number of days unless spent, never if 0
+	ExpiresInDays int `json:"expires_in_days,omitempty"`
+}
+
+// Record of user redeeming voucher
+type VoucherRedemption struct {
+	ID          uint      `json:"id,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	VoucherId   uint      `sql:"index" json:"voucher_id"`
+	UserId      uint      `sql:"index" json:"user_id"`
+	Code        string    `json:"code"`
+	Amount      int       `json:"amount"`
+	Currency    string    `json:"currency"`
+	OperationId uint      `json:"operation_id"`
+	// User wallet right after redemption, returned by RedeemVoucher only
+	Balance *UserPointsBalance `sql:"-" json:"balance,omitempty"`
+}
+
+type VoucherRequest struct {
+	// Generated if omitted
+	Code           string     `json:"code,omitempty" validate:"max=32"`
+	Amount         int        `json:"amount" validate:"min=1"`
+	Currency       string     `json:"currency,omitempty" validate:"oneof=points|bonus|tokens"`
+	MaxRedemptions int        `json:"max_redemptions,omitempty" validate:"min=0"`
+	PerUserLimit   int        `json:"per_user_limit,omitempty" validate:"min=0"`
+	ValidFrom      *time.Time `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time `json:"valid_until,omitempty"`
+	ExpiresInDays  int        `json:"expires_in_days,omitempty" validate:"min=0"`
+}
+
+func (r *VoucherRequest) validate(prefix string) (errs ValidationErrors) {
+	if !isVoucherCode(NormalizeVoucherCode(r.Code)) {
+		errs = errs.add(prefix+"code", "must contain latin letters, digits and dashes only")
+	}
+	if r.ValidFrom != nil && r.ValidUntil != nil && !r.ValidUntil.After(*r.ValidFrom) {
+		errs = errs.add(prefix+"valid_until", "must be after valid_from")
+	}
+	return errs
+}
+
+// Generates Count vouchers like Voucher, codes are made of Prefix and random part
+type VoucherBatchRequest struct {
+	Count   int            `json:"count" validate:"min=1,max=1000"`
+	Prefix  string         `json:"prefix,omitempty" validate:"max=16"`
+	Voucher VoucherRequest `json:"voucher"`
+}
+
+func (r *VoucherBatchRequest) validate(prefix string) (errs ValidationErrors) {
+	if r.Voucher.Code != "" {
+		errs = errs.add(prefix+"voucher.code", "must be omitted, codes are generated")
+	}
+	if !isVoucherCode(NormalizeVoucherCode(r.Prefix)) {
+		errs = errs.add(prefix+"prefix", "must contain latin letters, digits and dashes only")
+	}
+	return errs
+}
+
+type VoucherBatch struct {
+	BatchId  string     `json:"batch_id"`
+	Vouchers []*Voucher `json:"vouchers"`
+}
+
+type RedeemVoucherRequest struct {
+	UserId uint   `json:"user_id" validate:"required"`
+	Code   string `json:"code" validate:"required,max=32"`
+}
+
+// Body of POST /users/:id/voucher-redemptions, user is taken from path
+type UserRedeemVoucherRequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+func (r *UserRedeemVoucherRequest) RedeemVoucherRequest(userId uint) *RedeemVoucherRequest {
+	return &RedeemVoucherRequest{
+		UserId: userId,
+		Code:   r.Code,
+	}
+}
+
+type VoucherRedemptionsQuery struct {
+	// Filters, ignored if 0
+	VoucherId uint
+	UserId    uint
+	Limit     int
+	Offset    int
+}
+
+type VoucherRedemptionsPage struct {
+	Redemptions []*VoucherRedemption
+	Total       int
+}
+
+type VoucherRedemptionsResponse struct {
+	Data []*VoucherRedemption `json:"data"`
+	Meta *PageMeta            `json:"meta"`
+}
+
+//Voucher codes are case insensitive, they are stored upper case
+func NormalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func isVoucherCode(code string) bool {
+	for _, c := range code {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//POST /admin/voucher (/admin/vouchers in v1) with JSON body like
+//{"code":"SPRING50","amount":50,"currency":"bonus","max_redemptions":1000,"per_user_limit":1,"valid_until":"2018-04-01T00:00:00Z"},
+//code is generated if omitted;
+//responds 400 on invalid request or existing code, 201 with full Voucher as "data" otherwise
+func (a *Api) createVoucher(ctx *gin.Context) {
+	var parsedRequestBody types.VoucherRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	voucher, err := a.stor.CreateVoucher(&parsedRequestBody)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrVoucherNotCreated, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": voucher.(*types.Voucher)})
+}
+
+//POST /admin/voucherBatch (/admin/voucher-batches in v1) with JSON body like
+//{"count":100,"prefix":"SPRING-","voucher":{"amount":50,"max_redemptions":1}}
+//generates "count" vouchers like "voucher" having codes starting with "prefix";
+//responds 400 on invalid request, 201 with VoucherBatch as "data" otherwise
+func (a *Api) createVoucherBatch(ctx *gin.Context) {
+	var parsedRequestBody types.VoucherBatchRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	batch, err := a.stor.CreateVoucherBatch(&parsedRequestBody)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrVoucherNotCreated, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": batch.(*types.VoucherBatch)})
+}
+
+//processes POST JSON body like {"user_id":1,"code":"SPRING50"}
+//responds 400 on invalid request or rejected redemption, 200 with full VoucherRedemption as "data" otherwise
+func (a *Api) redeemVoucher(ctx *gin.Context) {
+	var parsedRequestBody types.RedeemVoucherRequest
+	if !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondRedemption(ctx, &parsedRequestBody, http.StatusOK)
+}
+
+//POST /users/:id/voucher-redemptions with JSON body like {"code":"SPRING50"}
+//responds 400 on invalid request or rejected redemption, 201 with full VoucherRedemption as "data" otherwise
+func (a *Api) createVoucherRedemptionV1(ctx *gin.Context) {
+	var parsedRequestBody types.UserRedeemVoucherRequest
+	id, ok := a.pathId(ctx, "id")
+	if !ok || !a.bindRequest(ctx, &parsedRequestBody) {
+		return
+	}
+	a.respondRedemption(ctx, parsedRequestBody.RedeemVoucherRequest(id), http.StatusCreated)
+}
+
+func (a *Api) respondRedemption(ctx *gin.Context, request *types.RedeemVoucherRequest, status int) {
+	redemption, err := a.stor.RedeemVoucher(request)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrVoucherNotRedeemed, err)
+		return
+	}
+	ctx.JSON(status, gin.H{"data": redemption.(*types.VoucherRedemption)})
+}
+
+//GET /admin/voucherRedemptions (/admin/voucher-redemptions in v1)
+//responds with voucher redemptions, newest first, filtered by "voucher_id" and "user_id"
+//and paged by "limit" and "offset" HTTP query params;
+//responds 400 on incorrect params listing every invalid one
+func (a *Api) getVoucherRedemptions(ctx *gin.Context) {
+	params := &queryParams{ctx: ctx}
+	query := &types.VoucherRedemptionsQuery{
+		VoucherId: uint(params.int("voucher_id", 0)),
+		UserId:    uint(params.int("user_id", 0)),
+		Limit:     params.limit(),
+		Offset:    params.int("offset", 0),
+	}
+	if !a.checkQueryParams(ctx, params) {
+		return
+	}
+	found, err := a.stor.FetchVoucherRedemptions(query)
+	if err != nil {
+		a.abortWithOperationError(ctx, types.ErrRedemptionsNotFound, err)
+		return
+	}
+	page := found.(*types.VoucherRedemptionsPage)
+	ctx.JSON(http.StatusOK, &types.VoucherRedemptionsResponse{
+		Data: page.Redemptions,
+		Meta: &types.PageMeta{Total: page.Total},
+	})
+}
@@ -0,0 +1,21 @@
+package main
+
+import "time"
+
+const POINTS_EXPIRY_JOB_INTERVAL = 10 * time.Minute
+
+type lotsExpirer interface {
+	ExpireBalanceLots(time.Time) (int, error)
+}
+
+//Takes away points left in lots expired by now, see storage.ExpireBalanceLots
+func runPointsExpiryJob(stor lotsExpirer) {
+	for {
+		if expired, err := stor.ExpireBalanceLots(time.Now()); err != nil {
+			logger.Printf("Could not expire balance lots: %s", err.Error())
+		} else if expired > 0 {
+			logger.Printf("%d balance lots expired", expired)
+		}
+		time.Sleep(POINTS_EXPIRY_JOB_INTERVAL)
+	}
+}
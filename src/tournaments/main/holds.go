@@ -0,0 +1,29 @@
+package main
+
+import "time"
+
+const HOLDS_JOB_INTERVAL = time.Minute
+
+type holdsSettler interface {
+	CloseDueRegistrations(time.Time) (int, error)
+	ReleaseExpiredHolds(time.Time) (int, error)
+}
+
+//Closes registration of tournaments started by now capturing their held deposits,
+//then releases holds still not captured gracePeriod after their tournaments' start
+func runHoldsJob(stor holdsSettler, gracePeriod time.Duration) {
+	for {
+		now := time.Now()
+		if closed, err := stor.CloseDueRegistrations(now); err != nil {
+			logger.Printf("Could not close tournament registrations: %s", err.Error())
+		} else if closed > 0 {
+			logger.Printf("%d tournament registrations closed", closed)
+		}
+		if released, err := stor.ReleaseExpiredHolds(now.Add(-gracePeriod)); err != nil {
+			logger.Printf("Could not release expired holds: %s", err.Error())
+		} else if released > 0 {
+			logger.Printf("%d tournament entries withdrawn as their holds expired", released)
+		}
+		time.Sleep(HOLDS_JOB_INTERVAL)
+	}
+}
@@ -7,35 +7,67 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"log"
 
 	"github.com/morrah77/game_tournament_api/src/tournaments/api"
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+	"github.com/morrah77/game_tournament_api/src/tournaments/rpc"
 	"github.com/morrah77/game_tournament_api/src/tournaments/storage"
 )
 
 const LOG_PREFIX = `Tournaments `
 
+const COMMAND_RECONCILE = `reconcile`
+
+const COMMAND_RECOMPUTE_RATINGS = `recompute-ratings`
+
 var (
-	logger  *log.Logger
-	dbConf  *storage.DsnColfig
-	apiConf *api.ApiConf
+	logger        *log.Logger
+	dbConf        *storage.DsnColfig
+	transfersConf *storage.TransfersConf
+	apiConf       *api.ApiConf
+	rpcConf       *rpc.RpcConf
+
+	balanceSnapshots bool
+	holdsJob         bool
+	holdGracePeriod  time.Duration
+	pointsExpiryJob  bool
+	seasonPayoutJob  bool
+	ticketExpiryJob  bool
 )
 
 func init() {
 	dbConf = &storage.DsnColfig{}
+	transfersConf = &storage.TransfersConf{}
 	apiConf = &api.ApiConf{}
+	rpcConf = &rpc.RpcConf{}
 	flag.StringVar(&dbConf.DbHost, "db-host", "postgres", "Database host")
 	flag.StringVar(&dbConf.DbPort, "db-port", "5432", "Database port")
 	flag.StringVar(&dbConf.DbUser, "db-user", "postgres", "Database username")
 	flag.StringVar(&dbConf.DbPass, "db-pass", "changeit", "Database password")
 	flag.StringVar(&dbConf.DbName, "db-name", "main", "Database name")
+	flag.IntVar(&transfersConf.DailyLimit, "transfer-daily-limit", 0, "Points every user may transfer to others per day unless set by admin, 0 for unlimited")
+	flag.IntVar(&transfersConf.FeeBasisPoints, "transfer-fee-bps", 0, "Transfer fee in basis points (1/100 of percent) of transferred points")
+	flag.IntVar(&transfersConf.MinFee, "transfer-min-fee", 0, "Minimal transfer fee in points")
+	flag.UintVar(&transfersConf.HouseUserId, "house-user-id", 0, "ID of user collecting transfer fees and funding overlays of guaranteed prize pools and season rewards, 0 to charge no fees")
 	flag.StringVar(&apiConf.ListenAddr, "listen-addr", ":8080", "Address to listen, like :8080")
 	flag.StringVar(&apiConf.RelativePath, "api-path", "/tournament/v0", "Api path, like /tournament/v0")
+	flag.StringVar(&apiConf.V1RelativePath, "api-v1-path", "/tournament/v1", "Resource-oriented Api path, like /tournament/v1")
+	flag.StringVar(&rpcConf.ListenAddr, "rpc-listen-addr", ":8081", "Address for gRPC server to listen, like :8081, empty to disable gRPC")
+	flag.BoolVar(&balanceSnapshots, "balance-snapshots", true, "Take daily snapshots of users' balances to speed up balance queries as of past dates")
+	flag.BoolVar(&holdsJob, "holds-job", true, "Close registration of started tournaments capturing held deposits and release expired holds")
+	flag.BoolVar(&pointsExpiryJob, "points-expiry-job", true, "Take away points of expired top-ups notifying their owners")
+	flag.BoolVar(&seasonPayoutJob, "season-payout-job", true, "Pay rewards of ended seasons to their top players")
+	flag.BoolVar(&ticketExpiryJob, "ticket-expiry-job", true, "Refund to points or expire satellite tickets not used before their tournaments start")
+	flag.DurationVar(&holdGracePeriod, "hold-grace-period", time.Hour, "Time after tournament start its deposits stay held if registration could not be closed")
+	flag.StringVar(&apiConf.AdminToken, "admin-token", "", "Token required by admin routes in X-Admin-Token header, empty to disable admin routes")
 
 	logger = log.New(os.Stdout, LOG_PREFIX, log.Flags())
 }
@@ -46,6 +78,8 @@ func main() {
 		err            error
 		stor           interface{}
 		tournamentsApi *api.Api
+		tournamentsRpc *rpc.Rpc
+		exitCode       int
 	)
 
 	defer func() {
@@ -58,6 +92,9 @@ func main() {
 				logger.Print(err.Error())
 			}
 		}
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
 	}()
 
 	//stopChan = make(chan os.Signal, 1)
@@ -65,10 +102,66 @@ func main() {
 
 	flag.Parse()
 
-	if stor, err = storage.NewStorage(dbConf, logger); err != nil {
+	if flag.Arg(0) == COMMAND_RECONCILE || flag.Arg(0) == COMMAND_RECOMPUTE_RATINGS {
+		// Keep stdout for the report only
+		logger.SetOutput(os.Stderr)
+	}
+
+	if stor, err = storage.NewStorage(dbConf, transfersConf, logger); err != nil {
 		panic(err.Error())
 	}
 
+	if flag.Arg(0) == COMMAND_RECONCILE {
+		consistent, err := reconcile(stor, flag.Args()[1:])
+		if err != nil {
+			panic(err.Error())
+		}
+		if !consistent {
+			exitCode = 1
+		}
+		return
+	}
+
+	if flag.Arg(0) == COMMAND_RECOMPUTE_RATINGS {
+		if err = recomputeRatings(stor); err != nil {
+			panic(err.Error())
+		}
+		return
+	}
+
+	if balanceSnapshots {
+		go runBalanceSnapshots(stor.(balanceSnapshotter))
+	}
+
+	if holdsJob {
+		go runHoldsJob(stor.(holdsSettler), holdGracePeriod)
+	}
+
+	if pointsExpiryJob {
+		go runPointsExpiryJob(stor.(lotsExpirer))
+	}
+
+	if seasonPayoutJob {
+		go runSeasonPayoutJob(stor.(seasonsPayer))
+	}
+
+	if ticketExpiryJob {
+		go runTicketExpiryJob(stor.(ticketsExpirer))
+	}
+
+	if rpcConf.ListenAddr != "" {
+		tournamentsRpc, err = rpc.NewRpc(rpcConf, stor, logger)
+		if err != nil {
+			panic(err.Error())
+		}
+		defer tournamentsRpc.Stop()
+		go func() {
+			if err := tournamentsRpc.Run(); err != nil {
+				panic(err.Error())
+			}
+		}()
+	}
+
 	tournamentsApi, err = api.NewApi(apiConf, stor, logger)
 	if err != nil {
 		panic(err.Error())
@@ -81,3 +174,36 @@ func main() {
 	//fmt.Printf("OS signal received: %#v\n", s)
 	return
 }
+
+//Runs `tournaments [flags] reconcile [--fix]` command printing types.ReconciliationReport as JSON to stdout,
+//returns false if any inconsistency is left unfixed
+func reconcile(stor interface{}, args []string) (bool, error) {
+	var fix bool
+	flags := flag.NewFlagSet(COMMAND_RECONCILE, flag.ExitOnError)
+	flags.BoolVar(&fix, "fix", false, "Write correction operations making ledger sums of drifted balances equal them, reset held points to sums of holds")
+	flags.Parse(args)
+
+	found, err := stor.(types.ApiStorage).Reconcile(fix)
+	if err != nil {
+		return false, err
+	}
+	report := found.(*types.ReconciliationReport)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(report); err != nil {
+		return false, err
+	}
+	return report.Consistent(), nil
+}
+
+//Runs `tournaments [flags] recompute-ratings` command rebuilding every rating from finished tournaments,
+//prints types.RatingsRecomputation as JSON to stdout
+func recomputeRatings(stor interface{}) error {
+	found, err := stor.(types.ApiStorage).RecomputeRatings()
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(found.(*types.RatingsRecomputation))
+}
@@ -0,0 +1,21 @@
+package main
+
+import "time"
+
+const SEASON_PAYOUT_JOB_INTERVAL = 10 * time.Minute
+
+type seasonsPayer interface {
+	PaySeasonRewards(time.Time) (int, error)
+}
+
+//Pays rewards of seasons ended by now, see storage.PaySeasonRewards
+func runSeasonPayoutJob(stor seasonsPayer) {
+	for {
+		if paid, err := stor.PaySeasonRewards(time.Now()); err != nil {
+			logger.Printf("Could not pay season rewards: %s", err.Error())
+		} else if paid > 0 {
+			logger.Printf("Rewards of %d seasons paid", paid)
+		}
+		time.Sleep(SEASON_PAYOUT_JOB_INTERVAL)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import "time"
+
+// Delay after midnight (UTC) to let operations made before it be committed
+const BALANCE_SNAPSHOT_DELAY = 5 * time.Minute
+const BALANCE_SNAPSHOT_RETRY_INTERVAL = 10 * time.Minute
+
+type balanceSnapshotter interface {
+	SnapshotBalances(time.Time) (int, error)
+}
+
+//Takes snapshot of every user's balance at the start of every day (UTC),
+//snapshot of the current day is taken at once if missing
+func runBalanceSnapshots(stor balanceSnapshotter) {
+	for {
+		day := time.Now().Add(-BALANCE_SNAPSHOT_DELAY).UTC().Truncate(24 * time.Hour)
+		count, err := stor.SnapshotBalances(day)
+		if err != nil {
+			logger.Printf("Could not take balance snapshots at %s: %s", day.Format(time.RFC3339), err.Error())
+			time.Sleep(BALANCE_SNAPSHOT_RETRY_INTERVAL)
+			continue
+		}
+		logger.Printf("%d balance snapshots taken at %s", count, day.Format(time.RFC3339))
+		time.Sleep(time.Until(day.Add(24*time.Hour + BALANCE_SNAPSHOT_DELAY)))
+	}
+}
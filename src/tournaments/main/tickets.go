@@ -0,0 +1,21 @@
+package main
+
+import "time"
+
+const TICKET_EXPIRY_JOB_INTERVAL = 10 * time.Minute
+
+type ticketsExpirer interface {
+	ExpireTickets(time.Time) (int, error)
+}
+
+//Refunds or expires satellite tickets not used by now, see storage.ExpireTickets
+func runTicketExpiryJob(stor ticketsExpirer) {
+	for {
+		if expired, err := stor.ExpireTickets(time.Now()); err != nil {
+			logger.Printf("Could not expire tickets: %s", err.Error())
+		} else if expired > 0 {
+			logger.Printf("%d tickets expired", expired)
+		}
+		time.Sleep(TICKET_EXPIRY_JOB_INTERVAL)
+	}
+}
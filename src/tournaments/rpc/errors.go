@@ -0,0 +1,37 @@
+package rpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+var grpcCodes = map[int]codes.Code{
+	types.ERROR_KIND_INVALID_REQUEST: codes.InvalidArgument,
+	types.ERROR_KIND_NOT_FOUND:       codes.NotFound,
+	types.ERROR_KIND_REJECTED:        codes.FailedPrecondition,
+	types.ERROR_KIND_FORBIDDEN:       codes.PermissionDenied,
+}
+
+//Logs storage error err && returns gRPC status matching opErr kind
+//with opErr message, same as api.abortWithOperationError does for HTTP;
+//code of OperationError returned by storage prefixes its message
+func (r *Rpc) operationError(opErr *types.OperationError, err error) error {
+	r.logger.Println(err.Error())
+	opErr = types.PublicError(err, opErr)
+	if opErr.Code != "" {
+		return status.Error(grpcCodes[opErr.Kind], opErr.Code+": "+opErr.Message)
+	}
+	return status.Error(grpcCodes[opErr.Kind], opErr.Message)
+}
+
+//Validates request by types.Validate,
+//returns InvalidArgument status listing every invalid field on failure
+func (r *Rpc) validate(request interface{}) error {
+	if errs := types.Validate(request); errs != nil {
+		r.logger.Println(errs.Error())
+		return status.Error(grpcCodes[types.ErrRequestValidationFailed.Kind], types.ErrRequestValidationFailed.Message+": "+errs.Error())
+	}
+	return nil
+}
@@ -0,0 +1,418 @@
+// Copyright 2018 h.lazar. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// gRPC interface of tournaments service, covers core api/types.ApiStorage operations only:
+// tournaments listing, announcement, entry && results, balances && wallets.
+// Other operations (withdrawals, transfers, statements, vouchers, brackets, ratings, leaderboards,
+// seasons, tickets, teams && team entries) are served by REST && GraphQL APIs.
+// Go code in ./pb is generated by protoc 23.4, protoc-gen-go v1.31.0 and protoc-gen-go-grpc v1.3.0:
+//   protoc -I . --go_out=pb --go_opt=paths=source_relative \
+//     --go-grpc_out=pb --go-grpc_opt=paths=source_relative tournaments.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: tournaments.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Tournaments_FetchTournament_FullMethodName     = "/tournaments.v0.Tournaments/FetchTournament"
+	Tournaments_FetchTournaments_FullMethodName    = "/tournaments.v0.Tournaments/FetchTournaments"
+	Tournaments_FetchBalance_FullMethodName        = "/tournaments.v0.Tournaments/FetchBalance"
+	Tournaments_FetchWallets_FullMethodName        = "/tournaments.v0.Tournaments/FetchWallets"
+	Tournaments_TakeAwayBalance_FullMethodName     = "/tournaments.v0.Tournaments/TakeAwayBalance"
+	Tournaments_TopUpBalance_FullMethodName        = "/tournaments.v0.Tournaments/TopUpBalance"
+	Tournaments_CreateNewTournament_FullMethodName = "/tournaments.v0.Tournaments/CreateNewTournament"
+	Tournaments_JoinTournament_FullMethodName      = "/tournaments.v0.Tournaments/JoinTournament"
+	Tournaments_ResultTournament_FullMethodName    = "/tournaments.v0.Tournaments/ResultTournament"
+)
+
+// TournamentsClient is the client API for Tournaments service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TournamentsClient interface {
+	FetchTournament(ctx context.Context, in *TournamentIdRequest, opts ...grpc.CallOption) (*Tournament, error)
+	FetchTournaments(ctx context.Context, in *FetchTournamentsRequest, opts ...grpc.CallOption) (*TournamentList, error)
+	FetchBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*UserPointsBalance, error)
+	FetchWallets(ctx context.Context, in *UserIdRequest, opts ...grpc.CallOption) (*UserPointsBalanceList, error)
+	TakeAwayBalance(ctx context.Context, in *BalanceOperationRequest, opts ...grpc.CallOption) (*UserPointsBalance, error)
+	TopUpBalance(ctx context.Context, in *BalanceOperationRequest, opts ...grpc.CallOption) (*UserPointsBalance, error)
+	CreateNewTournament(ctx context.Context, in *AnnounceTournamentRequest, opts ...grpc.CallOption) (*Tournament, error)
+	JoinTournament(ctx context.Context, in *JoinTournamentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ResultTournament(ctx context.Context, in *ResultTournamentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type tournamentsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTournamentsClient(cc grpc.ClientConnInterface) TournamentsClient {
+	return &tournamentsClient{cc}
+}
+
+func (c *tournamentsClient) FetchTournament(ctx context.Context, in *TournamentIdRequest, opts ...grpc.CallOption) (*Tournament, error) {
+	out := new(Tournament)
+	err := c.cc.Invoke(ctx, Tournaments_FetchTournament_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentsClient) FetchTournaments(ctx context.Context, in *FetchTournamentsRequest, opts ...grpc.CallOption) (*TournamentList, error) {
+	out := new(TournamentList)
+	err := c.cc.Invoke(ctx, Tournaments_FetchTournaments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentsClient) FetchBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*UserPointsBalance, error) {
+	out := new(UserPointsBalance)
+	err := c.cc.Invoke(ctx, Tournaments_FetchBalance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentsClient) FetchWallets(ctx context.Context, in *UserIdRequest, opts ...grpc.CallOption) (*UserPointsBalanceList, error) {
+	out := new(UserPointsBalanceList)
+	err := c.cc.Invoke(ctx, Tournaments_FetchWallets_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentsClient) TakeAwayBalance(ctx context.Context, in *BalanceOperationRequest, opts ...grpc.CallOption) (*UserPointsBalance, error) {
+	out := new(UserPointsBalance)
+	err := c.cc.Invoke(ctx, Tournaments_TakeAwayBalance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentsClient) TopUpBalance(ctx context.Context, in *BalanceOperationRequest, opts ...grpc.CallOption) (*UserPointsBalance, error) {
+	out := new(UserPointsBalance)
+	err := c.cc.Invoke(ctx, Tournaments_TopUpBalance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentsClient) CreateNewTournament(ctx context.Context, in *AnnounceTournamentRequest, opts ...grpc.CallOption) (*Tournament, error) {
+	out := new(Tournament)
+	err := c.cc.Invoke(ctx, Tournaments_CreateNewTournament_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentsClient) JoinTournament(ctx context.Context, in *JoinTournamentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tournaments_JoinTournament_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentsClient) ResultTournament(ctx context.Context, in *ResultTournamentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tournaments_ResultTournament_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TournamentsServer is the server API for Tournaments service.
+// All implementations must embed UnimplementedTournamentsServer
+// for forward compatibility
+type TournamentsServer interface {
+	FetchTournament(context.Context, *TournamentIdRequest) (*Tournament, error)
+	FetchTournaments(context.Context, *FetchTournamentsRequest) (*TournamentList, error)
+	FetchBalance(context.Context, *BalanceRequest) (*UserPointsBalance, error)
+	FetchWallets(context.Context, *UserIdRequest) (*UserPointsBalanceList, error)
+	TakeAwayBalance(context.Context, *BalanceOperationRequest) (*UserPointsBalance, error)
+	TopUpBalance(context.Context, *BalanceOperationRequest) (*UserPointsBalance, error)
+	CreateNewTournament(context.Context, *AnnounceTournamentRequest) (*Tournament, error)
+	JoinTournament(context.Context, *JoinTournamentRequest) (*emptypb.Empty, error)
+	ResultTournament(context.Context, *ResultTournamentRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedTournamentsServer()
+}
+
+// UnimplementedTournamentsServer must be embedded to have forward compatible implementations.
+type UnimplementedTournamentsServer struct {
+}
+
+func (UnimplementedTournamentsServer) FetchTournament(context.Context, *TournamentIdRequest) (*Tournament, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchTournament not implemented")
+}
+func (UnimplementedTournamentsServer) FetchTournaments(context.Context, *FetchTournamentsRequest) (*TournamentList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchTournaments not implemented")
+}
+func (UnimplementedTournamentsServer) FetchBalance(context.Context, *BalanceRequest) (*UserPointsBalance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchBalance not implemented")
+}
+func (UnimplementedTournamentsServer) FetchWallets(context.Context, *UserIdRequest) (*UserPointsBalanceList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchWallets not implemented")
+}
+func (UnimplementedTournamentsServer) TakeAwayBalance(context.Context, *BalanceOperationRequest) (*UserPointsBalance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TakeAwayBalance not implemented")
+}
+func (UnimplementedTournamentsServer) TopUpBalance(context.Context, *BalanceOperationRequest) (*UserPointsBalance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TopUpBalance not implemented")
+}
+func (UnimplementedTournamentsServer) CreateNewTournament(context.Context, *AnnounceTournamentRequest) (*Tournament, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNewTournament not implemented")
+}
+func (UnimplementedTournamentsServer) JoinTournament(context.Context, *JoinTournamentRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinTournament not implemented")
+}
+func (UnimplementedTournamentsServer) ResultTournament(context.Context, *ResultTournamentRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResultTournament not implemented")
+}
+func (UnimplementedTournamentsServer) mustEmbedUnimplementedTournamentsServer() {}
+
+// UnsafeTournamentsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TournamentsServer will
+// result in compilation errors.
+type UnsafeTournamentsServer interface {
+	mustEmbedUnimplementedTournamentsServer()
+}
+
+func RegisterTournamentsServer(s grpc.ServiceRegistrar, srv TournamentsServer) {
+	s.RegisterService(&Tournaments_ServiceDesc, srv)
+}
+
+func _Tournaments_FetchTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TournamentIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentsServer).FetchTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tournaments_FetchTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentsServer).FetchTournament(ctx, req.(*TournamentIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tournaments_FetchTournaments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchTournamentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentsServer).FetchTournaments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tournaments_FetchTournaments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentsServer).FetchTournaments(ctx, req.(*FetchTournamentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tournaments_FetchBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentsServer).FetchBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tournaments_FetchBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentsServer).FetchBalance(ctx, req.(*BalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tournaments_FetchWallets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentsServer).FetchWallets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tournaments_FetchWallets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentsServer).FetchWallets(ctx, req.(*UserIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tournaments_TakeAwayBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentsServer).TakeAwayBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tournaments_TakeAwayBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentsServer).TakeAwayBalance(ctx, req.(*BalanceOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tournaments_TopUpBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentsServer).TopUpBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tournaments_TopUpBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentsServer).TopUpBalance(ctx, req.(*BalanceOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tournaments_CreateNewTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnounceTournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentsServer).CreateNewTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tournaments_CreateNewTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentsServer).CreateNewTournament(ctx, req.(*AnnounceTournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tournaments_JoinTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinTournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentsServer).JoinTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tournaments_JoinTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentsServer).JoinTournament(ctx, req.(*JoinTournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tournaments_ResultTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResultTournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentsServer).ResultTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tournaments_ResultTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentsServer).ResultTournament(ctx, req.(*ResultTournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Tournaments_ServiceDesc is the grpc.ServiceDesc for Tournaments service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Tournaments_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tournaments.v0.Tournaments",
+	HandlerType: (*TournamentsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FetchTournament",
+			Handler:    _Tournaments_FetchTournament_Handler,
+		},
+		{
+			MethodName: "FetchTournaments",
+			Handler:    _Tournaments_FetchTournaments_Handler,
+		},
+		{
+			MethodName: "FetchBalance",
+			Handler:    _Tournaments_FetchBalance_Handler,
+		},
+		{
+			MethodName: "FetchWallets",
+			Handler:    _Tournaments_FetchWallets_Handler,
+		},
+		{
+			MethodName: "TakeAwayBalance",
+			Handler:    _Tournaments_TakeAwayBalance_Handler,
+		},
+		{
+			MethodName: "TopUpBalance",
+			Handler:    _Tournaments_TopUpBalance_Handler,
+		},
+		{
+			MethodName: "CreateNewTournament",
+			Handler:    _Tournaments_CreateNewTournament_Handler,
+		},
+		{
+			MethodName: "JoinTournament",
+			Handler:    _Tournaments_JoinTournament_Handler,
+		},
+		{
+			MethodName: "ResultTournament",
+			Handler:    _Tournaments_ResultTournament_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "tournaments.proto",
+}
@@ -0,0 +1,49 @@
+package rpc
+
+import (
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+	"github.com/morrah77/game_tournament_api/src/tournaments/rpc/pb"
+)
+
+type RpcConf struct {
+	ListenAddr string
+}
+
+type Rpc struct {
+	conf   *RpcConf
+	server *grpc.Server
+	stor   types.ApiStorage
+	logger *log.Logger
+}
+
+func NewRpc(conf *RpcConf, s interface{}, logger *log.Logger) (r *Rpc, err error) {
+	if err = types.CheckValidationTags(types.ValidatedRequests...); err != nil {
+		return nil, err
+	}
+	r = &Rpc{
+		conf:   conf,
+		server: grpc.NewServer(),
+		stor:   s.(types.ApiStorage),
+		logger: logger,
+	}
+	pb.RegisterTournamentsServer(r.server, &tournamentsServer{rpc: r})
+	return r, nil
+}
+
+func (r *Rpc) Run() error {
+	listener, err := net.Listen("tcp", r.conf.ListenAddr)
+	if err != nil {
+		return err
+	}
+	r.logger.Printf("gRPC server listening on %s\n", r.conf.ListenAddr)
+	return r.server.Serve(listener)
+}
+
+func (r *Rpc) Stop() {
+	r.server.GracefulStop()
+}
@@ -0,0 +1,220 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+	"github.com/morrah77/game_tournament_api/src/tournaments/rpc/pb"
+)
+
+// Implements pb.TournamentsServer over Rpc storage
+type tournamentsServer struct {
+	pb.UnimplementedTournamentsServer
+	rpc *Rpc
+}
+
+func (s *tournamentsServer) FetchTournament(ctx context.Context, request *pb.TournamentIdRequest) (*pb.Tournament, error) {
+	tournament, err := s.rpc.stor.FetchTournament(uint(request.Id))
+	if err != nil {
+		return nil, s.rpc.operationError(types.ErrTournamentNotFound, err)
+	}
+	return tournamentToPb(tournament.(*types.Tournament)), nil
+}
+
+func (s *tournamentsServer) FetchTournaments(ctx context.Context, request *pb.FetchTournamentsRequest) (*pb.TournamentList, error) {
+	limit := int(request.Limit)
+	if limit == 0 {
+		limit = types.DEFAULT_PAGE_LIMIT
+	}
+	tournaments, err := s.rpc.stor.FetchTournaments(types.PageLimit(limit), int(request.Offset))
+	if err != nil {
+		return nil, s.rpc.operationError(types.ErrTournamentsNotFound, err)
+	}
+	list := &pb.TournamentList{}
+	for _, tournament := range tournaments.([]*types.Tournament) {
+		list.Tournaments = append(list.Tournaments, tournamentToPb(tournament))
+	}
+	return list, nil
+}
+
+func (s *tournamentsServer) FetchBalance(ctx context.Context, request *pb.BalanceRequest) (*pb.UserPointsBalance, error) {
+	wallet := &types.WalletRequest{UserId: uint(request.Id), Currency: request.Currency}
+	if err := s.rpc.validate(wallet); err != nil {
+		return nil, err
+	}
+	balance, err := s.rpc.stor.FetchBalance(wallet.UserId, types.CurrencyOrDefault(wallet.Currency))
+	if err != nil {
+		return nil, s.rpc.operationError(types.ErrBalanceNotFound, err)
+	}
+	return balanceToPb(balance.(*types.UserPointsBalance)), nil
+}
+
+func (s *tournamentsServer) FetchWallets(ctx context.Context, request *pb.UserIdRequest) (*pb.UserPointsBalanceList, error) {
+	wallets, err := s.rpc.stor.FetchBalances([]uint{uint(request.Id)})
+	if err != nil {
+		return nil, s.rpc.operationError(types.ErrBalanceNotFound, err)
+	}
+	list := &pb.UserPointsBalanceList{}
+	for _, balance := range wallets.([]*types.UserPointsBalance) {
+		list.Balances = append(list.Balances, balanceToPb(balance))
+	}
+	return list, nil
+}
+
+func (s *tournamentsServer) TakeAwayBalance(ctx context.Context, request *pb.BalanceOperationRequest) (*pb.UserPointsBalance, error) {
+	operation := balanceOperationFromPb(request)
+	if err := s.rpc.validate(operation); err != nil {
+		return nil, err
+	}
+	balance, err := s.rpc.stor.TakeAwayBalance(operation.PlayerId, types.CurrencyOrDefault(operation.Currency), operation.Points)
+	if err != nil {
+		return nil, s.rpc.operationError(types.ErrBalanceNotTakenAway, err)
+	}
+	return balanceToPb(balance.(*types.UserPointsBalance)), nil
+}
+
+func (s *tournamentsServer) TopUpBalance(ctx context.Context, request *pb.BalanceOperationRequest) (*pb.UserPointsBalance, error) {
+	operation := balanceOperationFromPb(request)
+	if err := s.rpc.validate(operation); err != nil {
+		return nil, err
+	}
+	balance, err := s.rpc.stor.TopUpBalance(operation.PlayerId, types.CurrencyOrDefault(operation.Currency), operation.Points, operation.ExpiresAt())
+	if err != nil {
+		return nil, s.rpc.operationError(types.ErrBalanceNotReplenished, err)
+	}
+	return balanceToPb(balance.(*types.UserPointsBalance)), nil
+}
+
+func (s *tournamentsServer) CreateNewTournament(ctx context.Context, request *pb.AnnounceTournamentRequest) (*pb.Tournament, error) {
+	announcement := &types.AnnounceTournamentRequest{
+		Deposit:         int(request.Deposit),
+		GameId:          int(request.GameId),
+		MaxPlayers:      int(request.MaxPlayers),
+		Currency:        request.Currency,
+		SponsorId:       uint(request.SponsorId),
+		PrizePool:       int(request.PrizePool),
+		GuaranteedPool:  int(request.GuaranteedPool),
+		Format:          request.Format,
+		MinRating:       request.MinRating,
+		MaxRating:       request.MaxRating,
+		InvitationCodes: int(request.InvitationCodes),
+		QualifierId:     uint(request.QualifierId),
+
+		TicketTournamentId: uint(request.TicketTournamentId),
+		Tickets:            int(request.Tickets),
+		TicketRefundable:   request.TicketRefundable,
+		TeamSize:           int(request.TeamSize),
+	}
+	for _, userId := range request.InvitedUserIds {
+		announcement.InvitedUserIds = append(announcement.InvitedUserIds, uint(userId))
+	}
+	if request.Date != nil {
+		announcement.Date = request.Date.AsTime()
+	}
+	if err := s.rpc.validate(announcement); err != nil {
+		return nil, err
+	}
+	if announcement.Date.IsZero() {
+		announcement.Date = time.Now()
+	}
+	tournament, err := s.rpc.stor.CreateNewTournament(announcement)
+	if err != nil {
+		return nil, s.rpc.operationError(types.ErrTournamentNotAnnounced, err)
+	}
+	return tournamentToPb(tournament.(*types.Tournament)), nil
+}
+
+func (s *tournamentsServer) JoinTournament(ctx context.Context, request *pb.JoinTournamentRequest) (*emptypb.Empty, error) {
+	join := &types.JoinTournamentRequest{
+		TournamentId:   uint(request.TournamentId),
+		PlayerId:       uint(request.PlayerId),
+		InvitationCode: request.InvitationCode,
+		TicketId:       uint(request.TicketId),
+	}
+	for _, backerId := range request.BackerIds {
+		join.BackerIds = append(join.BackerIds, uint(backerId))
+	}
+	if err := s.rpc.validate(join); err != nil {
+		return nil, err
+	}
+	if err := s.rpc.stor.JoinTournamentAndTakePointsFromUserBalances(join); err != nil {
+		return nil, s.rpc.operationError(types.ErrTournamentNotJoined, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *tournamentsServer) ResultTournament(ctx context.Context, request *pb.ResultTournamentRequest) (*emptypb.Empty, error) {
+	result := &types.ResultTournamentRequest{
+		TournamentId: uint(request.TournamentId),
+	}
+	for _, winner := range request.Winners {
+		result.Winners = append(result.Winners, &types.TournamentWinnerRequest{
+			PlayerId: uint(winner.PlayerId),
+			Prize:    int(winner.Prize),
+			TeamId:   uint(winner.TeamId),
+			Ticket:   winner.Ticket,
+		})
+	}
+	if err := s.rpc.validate(result); err != nil {
+		return nil, err
+	}
+	if err := s.rpc.stor.CheckAndSpreadTournamentPrize(result); err != nil {
+		return nil, s.rpc.operationError(types.ErrTournamentResultNotSaved, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func balanceOperationFromPb(request *pb.BalanceOperationRequest) *types.BalanceOperationRequest {
+	return &types.BalanceOperationRequest{
+		PlayerId:      uint(request.PlayerId),
+		Points:        int(request.Points),
+		Currency:      request.Currency,
+		ExpiresInDays: int(request.ExpiresInDays),
+	}
+}
+
+func tournamentToPb(tournament *types.Tournament) *pb.Tournament {
+	return &pb.Tournament{
+		Id:                 uint64(tournament.ID),
+		CreatedAt:          timestamppb.New(tournament.CreatedAt),
+		UpdatedAt:          timestamppb.New(tournament.UpdatedAt),
+		Date:               timestamppb.New(tournament.Date),
+		Deposit:            int64(tournament.Deposit),
+		GameId:             int64(tournament.GameId),
+		State:              uint32(tournament.State),
+		MaxPlayers:         int64(tournament.MaxPlayers),
+		Currency:           tournament.Currency,
+		SponsorId:          uint64(tournament.SponsorId),
+		PrizePool:          int64(tournament.PrizePool),
+		PrizePoolReturned:  int64(tournament.PrizePoolReturned),
+		GuaranteedPool:     int64(tournament.GuaranteedPool),
+		Overlay:            int64(tournament.Overlay),
+		Format:             tournament.Format,
+		MinRating:          tournament.MinRating,
+		MaxRating:          tournament.MaxRating,
+		InvitationOnly:     tournament.InvitationOnly,
+		QualifierId:        uint64(tournament.QualifierId),
+		InvitationCodes:    tournament.InvitationCodes,
+		TicketTournamentId: uint64(tournament.TicketTournamentId),
+		Tickets:            int64(tournament.Tickets),
+		TicketRefundable:   tournament.TicketRefundable,
+		TeamSize:           int64(tournament.TeamSize),
+	}
+}
+
+func balanceToPb(balance *types.UserPointsBalance) *pb.UserPointsBalance {
+	return &pb.UserPointsBalance{
+		Id:        uint64(balance.ID),
+		CreatedAt: timestamppb.New(balance.CreatedAt),
+		UpdatedAt: timestamppb.New(balance.UpdatedAt),
+		UserId:    uint64(balance.UserId),
+		Balance:   int64(balance.Balance),
+		Held:      int64(balance.Held),
+		Available: int64(balance.Balance - balance.Held),
+		Currency:  balance.Currency,
+	}
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Wallet balances as of moment given by the first two args: the latest snapshot taken not later than the moment
+// plus operations made since the snapshot and before the moment; %s is a condition on balances "b"
+const BALANCES_AS_OF_QUERY = `SELECT b.user_id, b.currency, COALESCE(sn.balance, 0) + COALESCE(ops.sum, 0) AS balance
+	FROM user_points_balances b
+	LEFT JOIN LATERAL (SELECT s.balance, s.taken_at FROM balance_snapshots s
+		WHERE s.user_id = b.user_id AND s.currency = b.currency AND s.taken_at <= ?
+		ORDER BY s.taken_at DESC LIMIT 1) sn ON true
+	LEFT JOIN LATERAL (SELECT SUM(CASE WHEN o.operation_type = %d THEN o.sum ELSE -o.sum END) AS sum
+		FROM user_points_operations o
+		WHERE o.user_id = b.user_id AND o.currency = b.currency AND o.deleted_at IS NULL AND o.created_at < ?
+		AND (sn.taken_at IS NULL OR o.created_at >= sn.taken_at)) ops ON true
+	WHERE b.deleted_at IS NULL AND %s`
+
+const SNAPSHOT_BALANCES_QUERY = `INSERT INTO balance_snapshots (created_at, user_id, currency, taken_at, balance)
+	SELECT now(), user_id, currency, ?, balance FROM (%s) AS balances
+	ON CONFLICT (user_id, currency, taken_at) DO NOTHING`
+
+//Fetches user wallet balance made of operations before asOf
+func (s *Storage) FetchBalanceAsOf(id uint, currency string, asOf time.Time) (interface{}, error) {
+	balances := []*types.BalanceAsOf{}
+	if err := s.db.Raw(balancesAsOfQuery("b.user_id = ? AND b.currency = ?"), asOf, asOf, id, currency).Scan(&balances).Error; err != nil {
+		return nil, errors.New("An error occured during Balance fetching")
+	}
+	if len(balances) == 0 {
+		return nil, errors.New("Balance not found")
+	}
+	balances[0].AsOf = asOf
+	return balances[0], nil
+}
+
+//Fetches page of wallet balances made of operations before query.AsOf
+//for users who had wallet by then, ordered by user id and currency
+func (s *Storage) FetchBalancesAsOf(query *types.BalancesAsOfQuery) (interface{}, error) {
+	var (
+		balances []*types.BalanceAsOf
+		total    int
+		err      error
+	)
+	db := s.db.Model(&types.UserPointsBalance{}).Where("created_at < ?", query.AsOf)
+	condition := "b.created_at < ?"
+	args := []interface{}{query.AsOf, query.AsOf, query.AsOf}
+	if query.Currency != "" {
+		db = db.Where("currency = ?", query.Currency)
+		condition += " AND b.currency = ?"
+		args = append(args, query.Currency)
+	}
+	if err = db.Count(&total).Error; err != nil {
+		return nil, errors.New("An error occured during balances counting")
+	}
+	balances = []*types.BalanceAsOf{}
+	if err = s.db.Raw(balancesAsOfQuery(condition)+" ORDER BY b.user_id, b.currency LIMIT ? OFFSET ?",
+		append(args, query.Limit, query.Offset)...).Scan(&balances).Error; err != nil {
+		return nil, errors.New("An error occured during balances fetching")
+	}
+	for _, balance := range balances {
+		balance.AsOf = query.AsOf
+	}
+	return &types.BalancesAsOfPage{
+		Balances: balances,
+		Total:    total,
+	}, nil
+}
+
+//Stores balance at takenAt of every wallet existing by then, starting from previous snapshots.
+//Snapshots already taken at takenAt are kept, so it's safe to run it repeatedly;
+//returns number of snapshots taken
+func (s *Storage) SnapshotBalances(takenAt time.Time) (int, error) {
+	result := s.db.Exec(fmt.Sprintf(SNAPSHOT_BALANCES_QUERY, balancesAsOfQuery("b.created_at < ?")),
+		takenAt, takenAt, takenAt, takenAt)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
+func balancesAsOfQuery(condition string) string {
+	return fmt.Sprintf(BALANCES_AS_OF_QUERY, USER_POINTS_OPERATION_DEBT, condition)
+}
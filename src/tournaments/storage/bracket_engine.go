@@ -0,0 +1,432 @@
+package storage
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Match of planned bracket linked to matches its winner && loser proceed to
+type plannedMatch struct {
+	match      *types.Match
+	winnerNext *plannedMatch
+	loserNext  *plannedMatch
+}
+
+// Player (0 for bye) taking slot of planned match before any match is played
+type plannedSeat struct {
+	match    *plannedMatch
+	slot     int
+	playerId uint
+}
+
+type bracketPlan struct {
+	// Ordered so that every match links to matches following it only
+	matches []*plannedMatch
+	seats   []*plannedSeat
+}
+
+//Plans matches of bracket of given format for players ordered by seed, strongest first,
+//swiss bracket is planned round by round starting from the first one
+func planBracket(format string, playerIds []uint) (*bracketPlan, error) {
+	if len(playerIds) < 2 {
+		return nil, errors.New("Bracket requires 2 players at least!")
+	}
+	switch format {
+	case types.TOURNAMENT_FORMAT_SINGLE_ELIMINATION:
+		return planElimination(playerIds, false), nil
+	case types.TOURNAMENT_FORMAT_DOUBLE_ELIMINATION:
+		return planElimination(playerIds, true), nil
+	case types.TOURNAMENT_FORMAT_ROUND_ROBIN:
+		return planRoundRobin(playerIds), nil
+	case types.TOURNAMENT_FORMAT_SWISS:
+		return planSwissRound(newSwissEntries(playerIds), 1), nil
+	}
+	return nil, errors.New("Tournament has no bracket format!")
+}
+
+//Plans winners bracket of power of 2 size, top seeds get byes if players are fewer.
+//For double elimination losers of winners bracket round 1 meet each other in losers bracket round 1,
+//losers of round k+1 drop to losers bracket round 2k to meet survivors of it;
+//winners of both brackets meet in grand final
+func planElimination(playerIds []uint, double bool) *bracketPlan {
+	plan := &bracketPlan{}
+	rounds := 1
+	for 1<<uint(rounds) < len(playerIds) {
+		rounds++
+	}
+	size := 1 << uint(rounds)
+
+	winners := make([][]*plannedMatch, rounds+1)
+	for round := 1; round <= rounds; round++ {
+		stage := round
+		if double {
+			// Losers of winners bracket are not eliminated but drop to losers bracket
+			stage = 0
+		}
+		winners[round] = plan.addRound(types.MATCH_BRACKET_WINNERS, round, size>>uint(round), stage)
+	}
+	for round := 1; round < rounds; round++ {
+		for position, planned := range winners[round] {
+			planned.proceedWinner(winners[round+1][position/2], position%2+1)
+		}
+	}
+	order := seedOrder(size)
+	for position, planned := range winners[1] {
+		for slot := 1; slot <= 2; slot++ {
+			seed := order[2*position+slot-1]
+			playerId := uint(0)
+			if seed <= len(playerIds) {
+				playerId = playerIds[seed-1]
+			}
+			plan.seats = append(plan.seats, &plannedSeat{match: planned, slot: slot, playerId: playerId})
+		}
+	}
+	if !double {
+		return plan
+	}
+
+	losersRounds := 2 * (rounds - 1)
+	losers := make([][]*plannedMatch, losersRounds+1)
+	for round := 1; round <= losersRounds; round++ {
+		// Odd rounds halve survivors, even ones meet them with players dropped from winners bracket
+		count := size >> uint(round/2+1+round%2)
+		losers[round] = plan.addRound(types.MATCH_BRACKET_LOSERS, round, count, round)
+	}
+	grandFinal := plan.addRound(types.MATCH_BRACKET_GRAND_FINAL, 1, 1, losersRounds+1)[0]
+
+	winners[rounds][0].proceedWinner(grandFinal, 1)
+	if rounds == 1 {
+		winners[1][0].proceedLoser(grandFinal, 2)
+		return plan
+	}
+	for position, planned := range winners[1] {
+		planned.proceedLoser(losers[1][position/2], position%2+1)
+	}
+	for round := 2; round <= rounds; round++ {
+		for position, planned := range winners[round] {
+			planned.proceedLoser(losers[2*(round-1)][position], 2)
+		}
+	}
+	for round := 1; round <= losersRounds; round++ {
+		for position, planned := range losers[round] {
+			switch {
+			case round == losersRounds:
+				planned.proceedWinner(grandFinal, 2)
+			case round%2 == 1:
+				planned.proceedWinner(losers[round+1][position], 1)
+			default:
+				planned.proceedWinner(losers[round+1][position/2], position%2+1)
+			}
+		}
+	}
+	return plan
+}
+
+//Plans every player meeting each other once by circle method, a player rests a round if players are odd
+func planRoundRobin(playerIds []uint) *bracketPlan {
+	plan := &bracketPlan{}
+	circle := append([]uint{}, playerIds...)
+	if len(circle)%2 == 1 {
+		circle = append(circle, 0)
+	}
+	count := len(circle)
+	for round := 1; round < count; round++ {
+		position := 0
+		for i := 0; i < count/2; i++ {
+			player1, player2 := circle[i], circle[count-1-i]
+			if player1 == 0 || player2 == 0 {
+				continue
+			}
+			planned := plan.addRound(types.MATCH_BRACKET_ROUND_ROBIN, round, 1, 0)[0]
+			planned.match.Position = position
+			plan.seats = append(plan.seats,
+				&plannedSeat{match: planned, slot: 1, playerId: player1},
+				&plannedSeat{match: planned, slot: 2, playerId: player2})
+			position++
+		}
+		// First player stays, the rest rotate clockwise
+		circle = append([]uint{circle[0], circle[count-1]}, circle[1:count-1]...)
+	}
+	return plan
+}
+
+func (p *bracketPlan) addRound(bracket string, round int, count int, stage int) []*plannedMatch {
+	planned := make([]*plannedMatch, 0, count)
+	for position := 0; position < count; position++ {
+		match := &plannedMatch{match: &types.Match{
+			Bracket:  bracket,
+			Round:    round,
+			Position: position,
+			Stage:    stage,
+			State:    types.MATCH_STATE_PENDING,
+		}}
+		planned = append(planned, match)
+		p.matches = append(p.matches, match)
+	}
+	return planned
+}
+
+func (m *plannedMatch) proceedWinner(next *plannedMatch, slot int) {
+	m.winnerNext = next
+	m.match.WinnerNextSlot = slot
+}
+
+func (m *plannedMatch) proceedLoser(next *plannedMatch, slot int) {
+	m.loserNext = next
+	m.match.LoserNextSlot = slot
+}
+
+//Returns seeds in order of bracket slots, so that seeds 1 && 2 could meet in final only,
+//1..4 in semifinals only and so on; size must be power of 2
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, 2*len(order))
+		for _, seed := range order {
+			next = append(next, seed, 2*len(order)+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// Matches of bracket by ID, advances players as matches finish
+type bracketEngine struct {
+	matches map[uint]*types.Match
+	// Matches changed since engine was made, to be saved
+	changed map[uint]*types.Match
+}
+
+func newBracketEngine(matches []*types.Match) *bracketEngine {
+	engine := &bracketEngine{
+		matches: make(map[uint]*types.Match, len(matches)),
+		changed: map[uint]*types.Match{},
+	}
+	engine.add(matches)
+	return engine
+}
+
+//Adds matches created since engine was made
+func (e *bracketEngine) add(matches []*types.Match) {
+	for _, match := range matches {
+		e.matches[match.ID] = match
+	}
+}
+
+//Puts player into match slot, 0 player means there is none; match having both slots filled
+//becomes ready or is finished at once if some player is absent
+func (e *bracketEngine) seat(match *types.Match, slot int, playerId uint) {
+	if slot == 1 {
+		match.Player1Id, match.Slot1Filled = playerId, true
+	} else {
+		match.Player2Id, match.Slot2Filled = playerId, true
+	}
+	e.changed[match.ID] = match
+	if !match.Slot1Filled || !match.Slot2Filled {
+		return
+	}
+	switch {
+	case match.Player1Id != 0 && match.Player2Id != 0:
+		match.State = types.MATCH_STATE_READY
+	case match.Player1Id != 0:
+		match.Bye = true
+		e.finish(match, match.Player1Id, 0)
+	default:
+		match.Bye = true
+		e.finish(match, match.Player2Id, 0)
+	}
+}
+
+//Marks match finished moving winner && loser to their next matches
+func (e *bracketEngine) finish(match *types.Match, winnerId uint, loserId uint) {
+	match.State = types.MATCH_STATE_FINISHED
+	match.WinnerId = winnerId
+	e.changed[match.ID] = match
+	if next, ok := e.matches[match.WinnerNextMatchId]; ok {
+		e.seat(next, match.WinnerNextSlot, winnerId)
+	}
+	if next, ok := e.matches[match.LoserNextMatchId]; ok {
+		e.seat(next, match.LoserNextSlot, loserId)
+	}
+}
+
+//Records result of ready match, winner 0 means a draw allowed in round robin only
+func (e *bracketEngine) report(match *types.Match, winnerId uint, score1 int, score2 int) error {
+	if match.State != types.MATCH_STATE_READY {
+		return errors.New("Match is not ready to be played!")
+	}
+	if winnerId == 0 && match.Bracket != types.MATCH_BRACKET_ROUND_ROBIN {
+		return errors.New("Elimination match could not end in a draw!")
+	}
+	if winnerId != 0 && !match.HasPlayer(winnerId) {
+		return errors.New("Winner does not play the match!")
+	}
+	loserId := uint(0)
+	if winnerId != 0 {
+		loserId = match.Player1Id
+		if winnerId == match.Player1Id {
+			loserId = match.Player2Id
+		}
+	}
+	match.Score1, match.Score2 = score1, score2
+	e.finish(match, winnerId, loserId)
+	return nil
+}
+
+func (e *bracketEngine) lastRound() int {
+	round := 0
+	for _, match := range e.matches {
+		if match.Round > round {
+			round = match.Round
+		}
+	}
+	return round
+}
+
+//Tells if every match is finished, swiss bracket could get further rounds still
+func (e *bracketEngine) completed() bool {
+	for _, match := range e.matches {
+		if match.State != types.MATCH_STATE_FINISHED {
+			return false
+		}
+	}
+	return true
+}
+
+//Fills places, records && points of standings of completed bracket && prizes of places,
+//tied players share place and split prizes of places they take; standings are left sorted by place
+func (e *bracketEngine) rank(format string, standings []*types.TournamentStanding, prizes []*types.BracketPrize) {
+	byUser := make(map[uint]*types.TournamentStanding, len(standings))
+	for _, standing := range standings {
+		standing.Wins, standing.Draws, standing.Losses, standing.Points, standing.Prize = 0, 0, 0, 0, 0
+		standing.Buchholz, standing.SonnebornBerger = 0, 0
+		byUser[standing.UserId] = standing
+	}
+	// Round robin ties are broken by score difference
+	scoreDiff := map[uint]int{}
+	// Elimination stage players left bracket at, champion's one is the highest
+	eliminated := map[uint]int{}
+	champion := 0
+	for _, match := range e.matches {
+		if match.Stage >= champion {
+			champion = match.Stage + 1
+		}
+		if match.State != types.MATCH_STATE_FINISHED {
+			continue
+		}
+		if match.Bye {
+			// Swiss bye scores a win, elimination one just advances player
+			if format == types.TOURNAMENT_FORMAT_SWISS && match.WinnerId != 0 {
+				byUser[match.WinnerId].Wins++
+			}
+			continue
+		}
+		scoreDiff[match.Player1Id] += match.Score1 - match.Score2
+		scoreDiff[match.Player2Id] += match.Score2 - match.Score1
+		if match.WinnerId == 0 {
+			byUser[match.Player1Id].Draws++
+			byUser[match.Player2Id].Draws++
+			continue
+		}
+		loserId := match.Player1Id
+		if match.WinnerId == match.Player1Id {
+			loserId = match.Player2Id
+		}
+		byUser[match.WinnerId].Wins++
+		byUser[loserId].Losses++
+		if match.LoserNextMatchId == 0 {
+			eliminated[loserId] = match.Stage
+		}
+	}
+
+	win, draw := types.MATCH_POINTS_WIN, types.MATCH_POINTS_DRAW
+	if format == types.TOURNAMENT_FORMAT_SWISS {
+		win, draw = types.SWISS_POINTS_WIN, types.SWISS_POINTS_DRAW
+	}
+	for _, standing := range standings {
+		standing.Points = win*standing.Wins + draw*standing.Draws
+	}
+	if format == types.TOURNAMENT_FORMAT_SWISS {
+		e.swissTieBreaks(byUser)
+	}
+	rankKey := func(standing *types.TournamentStanding) []float64 {
+		switch format {
+		case types.TOURNAMENT_FORMAT_ROUND_ROBIN:
+			return []float64{float64(standing.Points), float64(scoreDiff[standing.UserId])}
+		case types.TOURNAMENT_FORMAT_SWISS:
+			return []float64{float64(standing.Points), standing.Buchholz, standing.SonnebornBerger}
+		}
+		if stage, ok := eliminated[standing.UserId]; ok {
+			return []float64{float64(stage)}
+		}
+		return []float64{float64(champion)}
+	}
+	// Positive if i ranks higher than j, 0 if they are tied
+	compare := func(i int, j int) int {
+		iKey, jKey := rankKey(standings[i]), rankKey(standings[j])
+		for k := range iKey {
+			if iKey[k] > jKey[k] {
+				return 1
+			}
+			if iKey[k] < jKey[k] {
+				return -1
+			}
+		}
+		return 0
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		if c := compare(i, j); c != 0 {
+			return c > 0
+		}
+		return standings[i].Seed < standings[j].Seed
+	})
+
+	prizeOfPlace := map[int]int{}
+	for _, prize := range prizes {
+		prizeOfPlace[prize.Place] = prize.Prize
+	}
+	for first := 0; first < len(standings); {
+		last := first
+		for last+1 < len(standings) && compare(first, last+1) == 0 {
+			last++
+		}
+		tied := last - first + 1
+		shared := 0
+		for place := first + 1; place <= last+1; place++ {
+			shared += prizeOfPlace[place]
+		}
+		for i, standing := range standings[first : last+1] {
+			standing.Place = first + 1
+			standing.Prize = shared / tied
+			// Indivisible remainder goes to the best seeds so that prizes add up
+			if i < shared%tied {
+				standing.Prize++
+			}
+		}
+		first = last + 1
+	}
+}
+
+//Sums Buchholz && Sonneborn-Berger tie-breaks of swiss standings having points filled,
+//byes add nothing to them
+func (e *bracketEngine) swissTieBreaks(byUser map[uint]*types.TournamentStanding) {
+	for _, match := range e.matches {
+		if match.Bye || match.State != types.MATCH_STATE_FINISHED {
+			continue
+		}
+		player1, player2 := byUser[match.Player1Id], byUser[match.Player2Id]
+		player1.Buchholz += float64(player2.Points)
+		player2.Buchholz += float64(player1.Points)
+		switch match.WinnerId {
+		case 0:
+			player1.SonnebornBerger += float64(player2.Points) / 2
+			player2.SonnebornBerger += float64(player1.Points) / 2
+		case match.Player1Id:
+			player1.SonnebornBerger += float64(player2.Points)
+		default:
+			player2.SonnebornBerger += float64(player1.Points)
+		}
+	}
+}
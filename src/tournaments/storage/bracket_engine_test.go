@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Result of ready match: winner (0 for draw) && scores
+type matchResult func(match *types.Match) (uint, int, int)
+
+// The player of lower ID always wins 1:0
+func lowerIdWins(match *types.Match) (uint, int, int) {
+	if match.Player1Id < match.Player2Id {
+		return match.Player1Id, 1, 0
+	}
+	return match.Player2Id, 0, 1
+}
+
+//Numbers planned matches the way createMatches does, seats players && plays every match by result
+func playPlan(t *testing.T, plan *bracketPlan, engine *bracketEngine, result matchResult) *bracketEngine {
+	t.Helper()
+	matches := make([]*types.Match, 0, len(plan.matches))
+	nextId := uint(len(engine.matches))
+	for i := len(plan.matches) - 1; i >= 0; i-- {
+		planned := plan.matches[i]
+		nextId++
+		planned.match.ID = nextId
+		if planned.winnerNext != nil {
+			planned.match.WinnerNextMatchId = planned.winnerNext.match.ID
+		}
+		if planned.loserNext != nil {
+			planned.match.LoserNextMatchId = planned.loserNext.match.ID
+		}
+		matches = append(matches, planned.match)
+	}
+	engine.add(matches)
+	for _, seat := range plan.seats {
+		engine.seat(seat.match.match, seat.slot, seat.playerId)
+	}
+	for {
+		ready := []*types.Match{}
+		for _, match := range engine.matches {
+			if match.State == types.MATCH_STATE_READY {
+				ready = append(ready, match)
+			}
+		}
+		if len(ready) == 0 {
+			return engine
+		}
+		sort.Slice(ready, func(i, j int) bool { return ready[i].ID < ready[j].ID })
+		winnerId, score1, score2 := result(ready[0])
+		if err := engine.report(ready[0], winnerId, score1, score2); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSeedOrder(t *testing.T) {
+	cases := []struct {
+		size  int
+		order []int
+	}{
+		{1, []int{1}},
+		{2, []int{1, 2}},
+		{4, []int{1, 4, 2, 3}},
+		{8, []int{1, 8, 4, 5, 2, 7, 3, 6}},
+	}
+	for _, c := range cases {
+		if order := seedOrder(c.size); !reflect.DeepEqual(order, c.order) {
+			t.Errorf("seedOrder(%d) got %v, want %v", c.size, order, c.order)
+		}
+	}
+}
+
+func TestPlanElimination(t *testing.T) {
+	cases := []struct {
+		name    string
+		players int
+		double  bool
+		// Matches by bracket
+		matches map[string]int
+		byes    int
+	}{
+		{"single of 2", 2, false, map[string]int{types.MATCH_BRACKET_WINNERS: 1}, 0},
+		{"single of 5", 5, false, map[string]int{types.MATCH_BRACKET_WINNERS: 7}, 3},
+		{"single of 8", 8, false, map[string]int{types.MATCH_BRACKET_WINNERS: 7}, 0},
+		{"double of 2", 2, true, map[string]int{types.MATCH_BRACKET_WINNERS: 1, types.MATCH_BRACKET_GRAND_FINAL: 1}, 0},
+		{"double of 3", 3, true, map[string]int{types.MATCH_BRACKET_WINNERS: 3, types.MATCH_BRACKET_LOSERS: 2, types.MATCH_BRACKET_GRAND_FINAL: 1}, 1},
+		{"double of 8", 8, true, map[string]int{types.MATCH_BRACKET_WINNERS: 7, types.MATCH_BRACKET_LOSERS: 6, types.MATCH_BRACKET_GRAND_FINAL: 1}, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			playerIds := make([]uint, 0, c.players)
+			for i := 1; i <= c.players; i++ {
+				playerIds = append(playerIds, uint(i))
+			}
+			plan := planElimination(playerIds, c.double)
+
+			matches := map[string]int{}
+			order := map[*plannedMatch]int{}
+			for i, planned := range plan.matches {
+				matches[planned.match.Bracket]++
+				order[planned] = i
+			}
+			if !reflect.DeepEqual(matches, c.matches) {
+				t.Errorf("got matches %v, want %v", matches, c.matches)
+			}
+			for i, planned := range plan.matches {
+				for _, next := range []*plannedMatch{planned.winnerNext, planned.loserNext} {
+					if next != nil && order[next] <= i {
+						t.Errorf("match %d links to preceding match %d", i, order[next])
+					}
+				}
+			}
+			byes := 0
+			seated := map[uint]bool{}
+			for _, seat := range plan.seats {
+				if seat.playerId == 0 {
+					byes++
+				}
+				seated[seat.playerId] = true
+			}
+			if byes != c.byes {
+				t.Errorf("got %d byes, want %d", byes, c.byes)
+			}
+			for _, playerId := range playerIds {
+				if !seated[playerId] {
+					t.Errorf("player %d is not seated", playerId)
+				}
+			}
+
+			engine := playPlan(t, plan, newBracketEngine(nil), lowerIdWins)
+			if !engine.completed() {
+				t.Fatal("bracket is not completed")
+			}
+			for _, match := range engine.matches {
+				if match.WinnerNextMatchId == 0 && match.WinnerId != 1 {
+					t.Errorf("top seed does not win last match, %d does", match.WinnerId)
+				}
+			}
+		})
+	}
+}
+
+func TestBracketEngineRank(t *testing.T) {
+	cases := []struct {
+		name    string
+		format  string
+		players []uint
+		result  matchResult
+		prizes  []*types.BracketPrize
+		// User IDs, places && prizes of standings sorted by place
+		users       []uint
+		places      []int
+		standPrizes []int
+	}{
+		{
+			name:        "single elimination semifinal losers share third place",
+			format:      types.TOURNAMENT_FORMAT_SINGLE_ELIMINATION,
+			players:     []uint{11, 12, 13, 14},
+			result:      lowerIdWins,
+			prizes:      []*types.BracketPrize{{Place: 1, Prize: 100}, {Place: 2, Prize: 50}, {Place: 3, Prize: 21}},
+			users:       []uint{11, 12, 13, 14},
+			places:      []int{1, 2, 3, 3},
+			standPrizes: []int{100, 50, 11, 10},
+		},
+		{
+			name:        "double elimination ranks losers by bracket stage",
+			format:      types.TOURNAMENT_FORMAT_DOUBLE_ELIMINATION,
+			players:     []uint{11, 12, 13, 14},
+			result:      lowerIdWins,
+			prizes:      []*types.BracketPrize{{Place: 1, Prize: 100}, {Place: 2, Prize: 50}},
+			users:       []uint{11, 12, 13, 14},
+			places:      []int{1, 2, 3, 4},
+			standPrizes: []int{100, 50, 0, 0},
+		},
+		{
+			name:    "round robin ties are broken by score difference",
+			format:  types.TOURNAMENT_FORMAT_ROUND_ROBIN,
+			players: []uint{1, 2, 3},
+			result: func(match *types.Match) (uint, int, int) {
+				// 1 beats 2 by 3:0, 2 beats 3 by 1:0, 3 beats 1 by 1:0
+				beats := map[uint]uint{1: 2, 2: 3, 3: 1}
+				margin := map[uint]int{1: 3, 2: 1, 3: 1}
+				winnerId := match.Player1Id
+				if beats[match.Player2Id] == match.Player1Id {
+					winnerId = match.Player2Id
+				}
+				if winnerId == match.Player1Id {
+					return winnerId, margin[winnerId], 0
+				}
+				return winnerId, 0, margin[winnerId]
+			},
+			prizes:      []*types.BracketPrize{{Place: 1, Prize: 100}},
+			users:       []uint{1, 3, 2},
+			places:      []int{1, 2, 3},
+			standPrizes: []int{100, 0, 0},
+		},
+		{
+			name:    "round robin draws tie everybody",
+			format:  types.TOURNAMENT_FORMAT_ROUND_ROBIN,
+			players: []uint{1, 2, 3},
+			result: func(match *types.Match) (uint, int, int) {
+				return 0, 2, 2
+			},
+			prizes:      []*types.BracketPrize{{Place: 1, Prize: 100}, {Place: 2, Prize: 50}, {Place: 3, Prize: 20}},
+			users:       []uint{1, 2, 3},
+			places:      []int{1, 1, 1},
+			standPrizes: []int{57, 57, 56},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			plan, err := planBracket(c.format, c.players)
+			if err != nil {
+				t.Fatal(err)
+			}
+			engine := playPlan(t, plan, newBracketEngine(nil), c.result)
+			standings := make([]*types.TournamentStanding, 0, len(c.players))
+			for i, playerId := range c.players {
+				standings = append(standings, &types.TournamentStanding{UserId: playerId, Seed: i + 1})
+			}
+			engine.rank(c.format, standings, c.prizes)
+
+			users, places, prizes := []uint{}, []int{}, []int{}
+			for _, standing := range standings {
+				users = append(users, standing.UserId)
+				places = append(places, standing.Place)
+				prizes = append(prizes, standing.Prize)
+			}
+			if !reflect.DeepEqual(users, c.users) || !reflect.DeepEqual(places, c.places) || !reflect.DeepEqual(prizes, c.standPrizes) {
+				t.Errorf("got users %v places %v prizes %v, want users %v places %v prizes %v",
+					users, places, prizes, c.users, c.places, c.standPrizes)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package storage
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Generates bracket of tournament format from its players closing registration if it's still open,
+//players without opponents get byes; prizes of places are paid as the last match result is submitted.
+//Swiss bracket gets its first round only, the next one is paired as the previous one finishes
+func (s *Storage) GenerateBracket(request *types.BracketRequest) (result interface{}, err error) {
+	var (
+		players []*types.TournamentPlayer
+		seeds   []uint
+		plan    *bracketPlan
+		matches []*types.Match
+	)
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	tournament := &types.Tournament{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(tournament, request.TournamentId).Error; err != nil {
+		return nil, err
+	}
+	if tournament.State != types.TOURNAMENT_STATE_OPEN {
+		err = errors.New(`Tournament already finished or cancelled!`)
+		return nil, err
+	}
+	if tournament.Format == "" {
+		err = errors.New(`Tournament has no bracket format!`)
+		return nil, err
+	}
+	if !tx.Where(&types.Bracket{TournamentId: tournament.ID}).First(&types.Bracket{}).RecordNotFound() {
+		err = errors.New(`Tournament bracket already generated!`)
+		return nil, err
+	}
+	prizes := 0
+	for _, prize := range request.Prizes {
+		prizes += prize
+	}
+	if len(request.Prizes) > 0 && prizes < tournament.GuaranteedPool {
+		err = errors.New(`Prizes must add up to guaranteed prize pool at least!`)
+		return nil, err
+	}
+	if request.Rounds > 0 && tournament.Format != types.TOURNAMENT_FORMAT_SWISS {
+		err = errors.New(`Rounds could be set for swiss tournaments only!`)
+		return nil, err
+	}
+	if tournament.RegistrationClosedAt == nil {
+		if err = s.closeRegistration(tx, tournament); err != nil {
+			return nil, err
+		}
+	}
+
+	players = []*types.TournamentPlayer{}
+	if err = tx.Where(&types.TournamentPlayer{TournamentId: tournament.ID}).Order("id").Find(&players).Error; err != nil {
+		return nil, err
+	}
+	if seeds, err = seedPlayers(request, players); err != nil {
+		return nil, err
+	}
+	if plan, err = planBracket(tournament.Format, seeds); err != nil {
+		return nil, err
+	}
+
+	bracket := &types.Bracket{
+		TournamentId: tournament.ID,
+		Format:       tournament.Format,
+		Seeding:      request.Seeding,
+	}
+	if bracket.Seeding == "" {
+		bracket.Seeding = types.SEEDING_JOIN_ORDER
+	}
+	if tournament.Format == types.TOURNAMENT_FORMAT_SWISS {
+		bracket.Rounds = request.Rounds
+		if bracket.Rounds == 0 {
+			bracket.Rounds = swissRounds(len(seeds))
+		}
+		if bracket.Rounds >= len(seeds) {
+			err = errors.New(`Swiss tournament must have fewer rounds than players!`)
+			return nil, err
+		}
+	}
+	if err = tx.Create(bracket).Error; err != nil {
+		return nil, err
+	}
+	for i, prize := range request.Prizes {
+		if err = tx.Create(&types.BracketPrize{BracketId: bracket.ID, Place: i + 1, Prize: prize}).Error; err != nil {
+			return nil, err
+		}
+	}
+	for i, userId := range seeds {
+		if err = tx.Create(&types.TournamentStanding{TournamentId: tournament.ID, UserId: userId, Seed: i + 1}).Error; err != nil {
+			return nil, err
+		}
+	}
+	if matches, err = s.createMatches(tx, tournament.ID, plan); err != nil {
+		return nil, err
+	}
+	engine := newBracketEngine(matches)
+	for _, seat := range plan.seats {
+		engine.seat(seat.match.match, seat.slot, seat.playerId)
+	}
+	if err = s.saveMatches(tx, engine); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return s.FetchBracket(tournament.ID)
+}
+
+//Fetches tournament bracket with prizes, matches && standings, players not ranked yet come last
+func (s *Storage) FetchBracket(tournamentId uint) (interface{}, error) {
+	bracket := &types.Bracket{}
+	if err := s.db.Where(&types.Bracket{TournamentId: tournamentId}).First(bracket).Error; err != nil {
+		return nil, err
+	}
+	bracket.Prizes = []*types.BracketPrize{}
+	if err := s.db.Where(&types.BracketPrize{BracketId: bracket.ID}).Order("place").Find(&bracket.Prizes).Error; err != nil {
+		return nil, errors.New("An error occured during bracket prizes fetching")
+	}
+	bracket.Matches = []*types.Match{}
+	if err := s.db.Where(&types.Match{TournamentId: tournamentId}).Order("id").Find(&bracket.Matches).Error; err != nil {
+		return nil, errors.New("An error occured during bracket matches fetching")
+	}
+	bracket.Standings = []*types.TournamentStanding{}
+	if err := s.db.Where(&types.TournamentStanding{TournamentId: tournamentId}).Order("place = 0, place, seed").Find(&bracket.Standings).Error; err != nil {
+		return nil, errors.New("An error occured during bracket standings fetching")
+	}
+	return bracket, nil
+}
+
+//Records result of ready match advancing its players; as the last match finishes ranks players
+//and pays prizes of places if bracket has any, finishing tournament
+func (s *Storage) SubmitMatchResult(request *types.MatchResultRequest) (interface{}, error) {
+	var match *types.Match
+	err := s.reportMatches(request.TournamentId, func(engine *bracketEngine) error {
+		var ok bool
+		if match, ok = engine.matches[request.MatchId]; !ok {
+			return errors.New(`Match not found in tournament bracket!`)
+		}
+		return engine.report(match, request.WinnerId, request.Score1, request.Score2)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+//Records results of ready matches of the round at once, see SubmitMatchResult;
+//returns bracket having the next swiss round paired if the round is finished
+func (s *Storage) SubmitRoundResults(request *types.RoundResultsRequest) (interface{}, error) {
+	err := s.reportMatches(request.TournamentId, func(engine *bracketEngine) error {
+		for _, result := range request.Results {
+			match, ok := engine.matches[result.MatchId]
+			if !ok || match.Round != request.Round {
+				return errors.New(`Match not found in the round of tournament bracket!`)
+			}
+			if err := engine.report(match, result.WinnerId, result.Score1, result.Score2); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.FetchBracket(request.TournamentId)
+}
+
+//Reports results to engine of tournament bracket && saves matches advanced,
+//pairs the next swiss round or completes bracket as every match finishes
+func (s *Storage) reportMatches(tournamentId uint, report func(engine *bracketEngine) error) (err error) {
+	var matches []*types.Match
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	tournament := &types.Tournament{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(tournament, tournamentId).Error; err != nil {
+		return err
+	}
+	if tournament.State != types.TOURNAMENT_STATE_OPEN {
+		err = errors.New(`Tournament already finished or cancelled!`)
+		return err
+	}
+	bracket := &types.Bracket{}
+	if tx.Where(&types.Bracket{TournamentId: tournament.ID}).First(bracket).RecordNotFound() {
+		err = errors.New(`Tournament bracket is not generated!`)
+		return err
+	}
+	if bracket.CompletedAt != nil {
+		err = errors.New(`Tournament bracket already completed!`)
+		return err
+	}
+	matches = []*types.Match{}
+	if err = tx.Where(&types.Match{TournamentId: tournament.ID}).Find(&matches).Error; err != nil {
+		return err
+	}
+	engine := newBracketEngine(matches)
+	if err = report(engine); err != nil {
+		return err
+	}
+	if engine.completed() && bracket.Format == types.TOURNAMENT_FORMAT_SWISS && engine.lastRound() < bracket.Rounds {
+		if err = s.pairSwissRound(tx, tournament, engine); err != nil {
+			return err
+		}
+	}
+	if err = s.saveMatches(tx, engine); err != nil {
+		return err
+	}
+	if engine.completed() {
+		if err = s.completeBracket(tx, tournament, bracket, engine); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+//Pairs the next swiss round by points players scored in finished ones
+func (s *Storage) pairSwissRound(tx *gorm.DB, tournament *types.Tournament, engine *bracketEngine) error {
+	standings := []*types.TournamentStanding{}
+	if err := tx.Where(&types.TournamentStanding{TournamentId: tournament.ID}).Order("seed").Find(&standings).Error; err != nil {
+		return err
+	}
+	plan := planSwissRound(swissEntries(standings, engine.matches), engine.lastRound()+1)
+	matches, err := s.createMatches(tx, tournament.ID, plan)
+	if err != nil {
+		return err
+	}
+	engine.add(matches)
+	for _, seat := range plan.seats {
+		engine.seat(seat.match.match, seat.slot, seat.playerId)
+	}
+	return nil
+}
+
+//Ranks players of completed bracket && pays prizes of places if bracket has any,
+//top places of satellite win its tickets; tournament must be locked
+func (s *Storage) completeBracket(tx *gorm.DB, tournament *types.Tournament, bracket *types.Bracket, engine *bracketEngine) error {
+	standings := []*types.TournamentStanding{}
+	if err := tx.Where(&types.TournamentStanding{TournamentId: tournament.ID}).Order("seed").Find(&standings).Error; err != nil {
+		return err
+	}
+	prizes := []*types.BracketPrize{}
+	if err := tx.Where(&types.BracketPrize{BracketId: bracket.ID}).Find(&prizes).Error; err != nil {
+		return err
+	}
+	engine.rank(bracket.Format, standings, prizes)
+	winners := []*types.TournamentWinnerRequest{}
+	for _, standing := range standings {
+		if err := tx.Save(standing).Error; err != nil {
+			return err
+		}
+		ticket := tournament.IsSatellite() && standing.Place > 0 && standing.Place <= tournament.Tickets
+		if standing.Prize > 0 || ticket {
+			winners = append(winners, &types.TournamentWinnerRequest{PlayerId: standing.UserId, Prize: standing.Prize, Ticket: ticket})
+		}
+	}
+	if err := tx.Model(bracket).UpdateColumn("completed_at", time.Now()).Error; err != nil {
+		return err
+	}
+	if len(prizes) == 0 && !tournament.IsSatellite() {
+		return nil
+	}
+	return s.spreadTournamentPrize(tx, tournament, winners)
+}
+
+//Lets tournament having bracket take results as a winners list only if bracket is completed without prizes of places
+func (s *Storage) checkBracketResults(tx *gorm.DB, tournament *types.Tournament) error {
+	bracket := &types.Bracket{}
+	if tx.Where(&types.Bracket{TournamentId: tournament.ID}).First(bracket).RecordNotFound() || bracket.CompletedAt == nil {
+		return errors.New(`Tournament bracket is not completed!`)
+	}
+	return nil
+}
+
+//Creates planned matches linking them to matches their winners && losers proceed to,
+//matches are created from the last one as every match links to following ones only
+func (s *Storage) createMatches(tx *gorm.DB, tournamentId uint, plan *bracketPlan) ([]*types.Match, error) {
+	matches := make([]*types.Match, 0, len(plan.matches))
+	for i := len(plan.matches) - 1; i >= 0; i-- {
+		planned := plan.matches[i]
+		planned.match.TournamentId = tournamentId
+		if planned.winnerNext != nil {
+			planned.match.WinnerNextMatchId = planned.winnerNext.match.ID
+		}
+		if planned.loserNext != nil {
+			planned.match.LoserNextMatchId = planned.loserNext.match.ID
+		}
+		if err := tx.Create(planned.match).Error; err != nil {
+			return nil, err
+		}
+		matches = append(matches, planned.match)
+	}
+	return matches, nil
+}
+
+func (s *Storage) saveMatches(tx *gorm.DB, engine *bracketEngine) error {
+	ids := make([]int, 0, len(engine.changed))
+	for id := range engine.changed {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		if err := tx.Save(engine.changed[uint(id)]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Orders tournament players by seed, strongest first
+func seedPlayers(request *types.BracketRequest, players []*types.TournamentPlayer) ([]uint, error) {
+	playerIds := make([]uint, 0, len(players))
+	joined := make(map[uint]bool, len(players))
+	for _, player := range players {
+		playerIds = append(playerIds, player.UserId)
+		joined[player.UserId] = true
+	}
+	switch request.Seeding {
+	case types.SEEDING_RANDOM:
+		random := rand.New(rand.NewSource(time.Now().UnixNano()))
+		random.Shuffle(len(playerIds), func(i, j int) { playerIds[i], playerIds[j] = playerIds[j], playerIds[i] })
+	case types.SEEDING_MANUAL:
+		if len(request.Seeds) != len(playerIds) {
+			return nil, errors.New(`Seeds must list every tournament player!`)
+		}
+		for _, userId := range request.Seeds {
+			if !joined[userId] {
+				return nil, errors.New(`Seeds must list tournament players only!`)
+			}
+		}
+		return request.Seeds, nil
+	}
+	return playerIds, nil
+}
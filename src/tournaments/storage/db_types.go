@@ -30,27 +30,27 @@ type User struct {
 //	State   uint
 //}
 
-type TournamentPlayer struct {
-	Model
-	TournamentId uint
-	UserId       uint
-	UserDeposit  int
-}
+//type TournamentPlayer struct {
+//	Model
+//	TournamentId uint
+//	UserId       uint
+//	UserDeposit  int
+//}
 
-type TournamentBacker struct {
-	Model
-	TournamentId  uint
-	UserId        uint
-	BackerId      uint
-	BackerDeposit int
-}
+//type TournamentBacker struct {
+//	Model
+//	TournamentId  uint
+//	UserId        uint
+//	BackerId      uint
+//	BackerDeposit int
+//}
 
-type TournamentWinner struct {
-	Model
-	TournamentId uint
-	UserId       uint
-	Prize        int
-}
+//type TournamentWinner struct {
+//	Model
+//	TournamentId uint
+//	UserId       uint
+//	Prize        int
+//}
 
 type UserAuth struct {
 	Model
@@ -59,9 +59,15 @@ type UserAuth struct {
 
 type UserPointsOperations struct {
 	Model
-	UserId        uint
+	UserId        uint `sql:"index"`
 	OperationType uint
-	Sum           int
+	// One of types.OPERATION_KIND_*, empty for operations recorded before kinds were introduced
+	Kind         string
+	TournamentId uint
+	TransferId   uint
+	// One of types.Currencies
+	Currency string `gorm:"not null;default:'points'"`
+	Sum      int
 }
 
 //type UserPointsBalance struct {
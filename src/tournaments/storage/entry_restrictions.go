@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Invites users of request to tournament being announced && generates its invitation codes
+func (s *Storage) createInvitations(tx *gorm.DB, tournament *types.Tournament, request *types.AnnounceTournamentRequest) error {
+	for _, userId := range request.InvitedUserIds {
+		if err := tx.Create(&types.TournamentInvitation{TournamentId: tournament.ID, UserId: userId}).Error; err != nil {
+			return err
+		}
+	}
+	tournament.InvitationCodes = make([]string, 0, request.InvitationCodes)
+	for i := 0; i < request.InvitationCodes; i++ {
+		code, err := randomCode(VOUCHER_CODE_LENGTH)
+		if err != nil {
+			return err
+		}
+		if err = tx.Create(&types.TournamentInvitation{TournamentId: tournament.ID, Code: code}).Error; err != nil {
+			return err
+		}
+		tournament.InvitationCodes = append(tournament.InvitationCodes, code)
+	}
+	return nil
+}
+
+//Returns one of types.ErrEntry* if player doesn't qualify for locked tournament,
+//uses invitation code of request unless player is invited by ID
+func (s *Storage) checkEntryRestrictions(tx *gorm.DB, tournament *types.Tournament, request *types.JoinTournamentRequest) error {
+	if tournament.MinRating > 0 || tournament.MaxRating > 0 {
+		rating := &types.Rating{}
+		if tx.Where(&types.Rating{UserId: request.PlayerId, GameId: tournament.GameId}).First(rating).RecordNotFound() {
+			rating.Rating = types.RATING_INITIAL
+		}
+		if tournament.MinRating > 0 && rating.Rating < tournament.MinRating {
+			return types.ErrEntryRatingTooLow
+		}
+		if tournament.MaxRating > 0 && rating.Rating > tournament.MaxRating {
+			return types.ErrEntryRatingTooHigh
+		}
+	}
+
+	if tournament.QualifierId != 0 &&
+		tx.Where(&types.TournamentWinner{TournamentId: tournament.QualifierId, UserId: request.PlayerId}).First(&types.TournamentWinner{}).RecordNotFound() {
+		return types.ErrEntryNotQualified
+	}
+
+	if !tournament.InvitationOnly ||
+		!tx.Where("tournament_id = ? AND user_id = ? AND code = ''", tournament.ID, request.PlayerId).First(&types.TournamentInvitation{}).RecordNotFound() {
+		return nil
+	}
+	code := types.NormalizeVoucherCode(request.InvitationCode)
+	if code == "" {
+		return types.ErrEntryNotInvited
+	}
+	invitation := &types.TournamentInvitation{}
+	if tx.Set("gorm:query_option", "FOR UPDATE").Where(&types.TournamentInvitation{TournamentId: tournament.ID, Code: code}).First(invitation).RecordNotFound() ||
+		invitation.UserId != 0 {
+		return types.ErrEntryInvalidInvitation
+	}
+	return tx.Model(invitation).UpdateColumns(map[string]interface{}{"user_id": request.PlayerId, "used_at": time.Now()}).Error
+}
+
+//Makes invitation code used by player withdrawing from tournament usable again
+func (s *Storage) releaseInvitation(tx *gorm.DB, tournamentId uint, playerId uint) error {
+	return tx.Model(&types.TournamentInvitation{}).
+		Where("tournament_id = ? AND user_id = ? AND code <> ''", tournamentId, playerId).
+		UpdateColumns(map[string]interface{}{"user_id": 0, "used_at": nil}).Error
+}
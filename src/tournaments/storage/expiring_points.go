@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Fetches lots of user wallet having points not spent or expired yet, expiring soonest first
+func (s *Storage) FetchBalanceLots(userId uint, currency string) (interface{}, error) {
+	lots := []*types.BalanceLot{}
+	if err := s.db.Where("user_id = ? AND currency = ? AND remaining > 0 AND expired_at IS NULL", userId, currency).
+		Order("expires_at, id").Find(&lots).Error; err != nil {
+		return nil, errors.New("An error occured during balance lots fetching")
+	}
+	return lots, nil
+}
+
+//Fetches page of user notifications, newest first
+func (s *Storage) FetchNotifications(query *types.NotificationsQuery) (interface{}, error) {
+	var (
+		notifications []*types.Notification
+		total         int
+		err           error
+	)
+	db := s.db.Model(&types.Notification{}).Where(&types.Notification{UserId: query.UserId})
+	if err = db.Count(&total).Error; err != nil {
+		return nil, errors.New("An error occured during notifications counting")
+	}
+	notifications = []*types.Notification{}
+	if err = db.Order("id DESC").Limit(query.Limit).Offset(query.Offset).Find(&notifications).Error; err != nil {
+		return nil, errors.New("An error occured during notifications fetching")
+	}
+	return &types.NotificationsPage{
+		Notifications: notifications,
+		Total:         total,
+	}, nil
+}
+
+//Takes away points left in every lot expired before now by "expire" operations
+//notifying wallet owners; returns number of lots expired
+func (s *Storage) ExpireBalanceLots(now time.Time) (int, error) {
+	var wallets []*struct {
+		UserId   uint
+		Currency string
+	}
+	if err := s.db.Raw(`SELECT DISTINCT user_id, currency FROM balance_lots
+		WHERE expires_at <= ? AND remaining > 0 AND expired_at IS NULL`, now).Scan(&wallets).Error; err != nil {
+		return 0, err
+	}
+	expired := 0
+	for _, wallet := range wallets {
+		count, err := s.expireWalletLots(wallet.UserId, wallet.Currency, now)
+		if err != nil {
+			s.logger.Printf("Could not expire %s lots of user %d: %s", wallet.Currency, wallet.UserId, err.Error())
+			continue
+		}
+		expired += count
+	}
+	return expired, nil
+}
+
+func (s *Storage) expireWalletLots(userId uint, currency string, now time.Time) (count int, err error) {
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	balance := &types.UserPointsBalance{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").Where(&types.UserPointsBalance{UserId: userId, Currency: currency}).First(balance).Error; err != nil {
+		return 0, err
+	}
+	lots := []*types.BalanceLot{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("user_id = ? AND currency = ? AND expires_at <= ? AND remaining > 0 AND expired_at IS NULL", userId, currency, now).
+		Order("id").Find(&lots).Error; err != nil {
+		return 0, err
+	}
+	sum := 0
+	for _, lot := range lots {
+		sum += lot.Remaining
+		if err = tx.Model(lot).UpdateColumns(map[string]interface{}{
+			"remaining":  0,
+			"expired":    gorm.Expr(`expired + ?`, lot.Remaining),
+			"expired_at": now,
+		}).Error; err != nil {
+			return 0, err
+		}
+	}
+	if sum > 0 {
+		if err = tx.Create(
+			&UserPointsOperations{
+				UserId:        userId,
+				OperationType: USER_POINTS_OPERATION_CREDIT,
+				Kind:          types.OPERATION_KIND_EXPIRE,
+				Currency:      currency,
+				Sum:           sum,
+			}).Error; err != nil {
+			return 0, err
+		}
+		if err = tx.Model(balance).UpdateColumn(`balance`, gorm.Expr(`balance - ?`, sum)).Error; err != nil {
+			return 0, err
+		}
+		if err = tx.Create(
+			&types.Notification{
+				UserId:   userId,
+				Kind:     types.NOTIFICATION_KIND_POINTS_EXPIRED,
+				Currency: currency,
+				Amount:   sum,
+				Message:  fmt.Sprintf("%d %s expired", sum, currency),
+			}).Error; err != nil {
+			return 0, err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return 0, err
+	}
+	return len(lots), nil
+}
+
+//Records points topped up to balance as lot expiring at expiresAt
+func (s *Storage) addLot(tx *gorm.DB, balance *types.UserPointsBalance, points int, expiresAt time.Time) error {
+	return tx.Create(
+		&types.BalanceLot{
+			UserId:    balance.UserId,
+			Currency:  balance.Currency,
+			Amount:    points,
+			Remaining: points,
+			ExpiresAt: expiresAt,
+		}).Error
+}
+
+//Spends points of wallet lots expiring soonest first, wallet balance must be locked.
+//Points spent above lots are taken from never expiring part of balance.
+//If holdId is not 0 records lots used by hold, so they are restored if hold is released, see restoreLots
+func (s *Storage) consumeLots(tx *gorm.DB, userId uint, currency string, points int, holdId uint) error {
+	lots := []*types.BalanceLot{}
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("user_id = ? AND currency = ? AND remaining > 0 AND expired_at IS NULL", userId, currency).
+		Order("expires_at, id").Find(&lots).Error; err != nil {
+		return err
+	}
+	for _, lot := range lots {
+		if points <= 0 {
+			break
+		}
+		used := lot.Remaining
+		if used > points {
+			used = points
+		}
+		if err := tx.Model(lot).UpdateColumn(`remaining`, gorm.Expr(`remaining - ?`, used)).Error; err != nil {
+			return err
+		}
+		if holdId > 0 {
+			if err := tx.Create(&types.BalanceLotUse{LotId: lot.ID, HoldId: holdId, Amount: used}).Error; err != nil {
+				return err
+			}
+		}
+		points -= used
+	}
+	return nil
+}
+
+//Returns points of lots used by hold back to them, points of lots expired since then
+//are expired again by next ExpireBalanceLots
+func (s *Storage) restoreLots(tx *gorm.DB, holdId uint) error {
+	uses := []*types.BalanceLotUse{}
+	if err := tx.Where(&types.BalanceLotUse{HoldId: holdId}).Order("lot_id").Find(&uses).Error; err != nil {
+		return err
+	}
+	for _, use := range uses {
+		if err := tx.Model(&types.BalanceLot{ID: use.LotId}).UpdateColumns(map[string]interface{}{
+			"remaining":  gorm.Expr(`remaining + ?`, use.Amount),
+			"expired_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where(&types.BalanceLotUse{HoldId: holdId}).Delete(&types.BalanceLotUse{}).Error
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	sqlite3 "github.com/mattn/go-sqlite3"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+func init() {
+	// SQLite has no NOW() of Postgres
+	sql.Register("sqlite3_storage_test", &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("now", func() string { return time.Now().UTC().Format("2006-01-02 15:04:05") }, false)
+		},
+	})
+	// && no row locks, transaction locks the whole database instead
+	gorm.DefaultCallback.Query().Before("gorm:query").Register("storage_test:drop_row_locks", func(scope *gorm.Scope) {
+		scope.Set("gorm:query_option", "")
+	})
+}
+
+//Makes storage of fresh in-memory SQLite database, transactional paths run there as they do on Postgres
+//since the only connection serializes transactions
+func newTestStorage(t *testing.T, transfersConf *TransfersConf) *Storage {
+	t.Helper()
+	sqlDb, err := sql.Open("sqlite3_storage_test", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDb.SetMaxOpenConns(1)
+	db, err := gorm.Open("sqlite3", sqlDb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.LogMode(false)
+	t.Cleanup(func() { db.Close() })
+	if transfersConf == nil {
+		transfersConf = &TransfersConf{}
+	}
+	s := &Storage{db: db, transfersConf: transfersConf, logger: log.New(ioutil.Discard, "", 0), leaderboards: newLeaderboardCache()}
+	s.autoMigrate()
+	return s
+}
+
+func mustTopUp(t *testing.T, s *Storage, userId uint, points int) {
+	t.Helper()
+	if _, err := s.TopUpBalance(userId, types.CURRENCY_POINTS, points, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//Announces tournament starting in an hour unless request tells its date
+func mustAnnounce(t *testing.T, s *Storage, request *types.AnnounceTournamentRequest) *types.Tournament {
+	t.Helper()
+	if request.Date.IsZero() {
+		request.Date = time.Now().Add(time.Hour)
+	}
+	tournament, err := s.CreateNewTournament(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tournament.(*types.Tournament)
+}
+
+func mustJoin(t *testing.T, s *Storage, request *types.JoinTournamentRequest) {
+	t.Helper()
+	if err := s.JoinTournamentAndTakePointsFromUserBalances(request); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func balanceOf(t *testing.T, s *Storage, userId uint) *types.UserPointsBalance {
+	t.Helper()
+	balance, err := s.FetchBalance(userId, types.CURRENCY_POINTS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return balance.(*types.UserPointsBalance)
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"math"
+	"sort"
+)
+
+// Glicko-2 system constants, see http://www.glicko.net/glicko/glicko2.pdf
+const (
+	// Constrains change of volatility over time
+	GLICKO_TAU = 0.5
+	// Converts ratings to Glicko-2 scale && back
+	GLICKO_SCALE = 173.7178
+	// Convergence tolerance of volatility iteration
+	GLICKO_EPSILON = 0.000001
+)
+
+// Player rating on Glicko-2 scale
+type glickoRating struct {
+	mu    float64
+	phi   float64
+	sigma float64
+}
+
+func newGlickoRating(rating float64, deviation float64, volatility float64) *glickoRating {
+	return &glickoRating{
+		mu:    (rating - 1500) / GLICKO_SCALE,
+		phi:   deviation / GLICKO_SCALE,
+		sigma: volatility,
+	}
+}
+
+func (r *glickoRating) rating() float64 {
+	return r.mu*GLICKO_SCALE + 1500
+}
+
+func (r *glickoRating) deviation() float64 {
+	return r.phi * GLICKO_SCALE
+}
+
+// Game of rating period, score1 is 1 if player1 won, 0.5 for draw, 0 if player2 won
+type ratedGame struct {
+	player1 uint
+	player2 uint
+	score1  float64
+	// Part of a whole game the outcome counts for, 1 for games actually played
+	weight float64
+}
+
+// Game as seen by one of its players
+type glickoOutcome struct {
+	opponent *glickoRating
+	score    float64
+	weight   float64
+}
+
+//Rates players of games played in one rating period against ratings they had before the period,
+//returns new ratings of players of games only
+func rateGames(ratings map[uint]*glickoRating, games []*ratedGame) map[uint]*glickoRating {
+	outcomes := map[uint][]*glickoOutcome{}
+	for _, game := range games {
+		outcomes[game.player1] = append(outcomes[game.player1], &glickoOutcome{opponent: ratings[game.player2], score: game.score1, weight: game.weight})
+		outcomes[game.player2] = append(outcomes[game.player2], &glickoOutcome{opponent: ratings[game.player1], score: 1 - game.score1, weight: game.weight})
+	}
+	rated := make(map[uint]*glickoRating, len(outcomes))
+	for userId, userOutcomes := range outcomes {
+		rated[userId] = ratings[userId].update(userOutcomes)
+	}
+	return rated
+}
+
+//Returns rating after period of given outcomes, every outcome contributes to variance && improvement by its weight
+func (r *glickoRating) update(outcomes []*glickoOutcome) *glickoRating {
+	if len(outcomes) == 0 {
+		return &glickoRating{mu: r.mu, phi: math.Sqrt(r.phi*r.phi + r.sigma*r.sigma), sigma: r.sigma}
+	}
+	variance, improvement := 0.0, 0.0
+	for _, outcome := range outcomes {
+		g := glickoG(outcome.opponent.phi)
+		expected := 1 / (1 + math.Exp(-g*(r.mu-outcome.opponent.mu)))
+		variance += outcome.weight * g * g * expected * (1 - expected)
+		improvement += outcome.weight * g * (outcome.score - expected)
+	}
+	variance = 1 / variance
+	delta := variance * improvement
+
+	sigma := r.volatility(delta, variance)
+	phiStar := math.Sqrt(r.phi*r.phi + sigma*sigma)
+	phi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/variance)
+	return &glickoRating{mu: r.mu + phi*phi*improvement, phi: phi, sigma: sigma}
+}
+
+//Finds new volatility by Illinois algorithm
+func (r *glickoRating) volatility(delta float64, variance float64) float64 {
+	a := math.Log(r.sigma * r.sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		denominator := r.phi*r.phi + variance + ex
+		return ex*(delta*delta-r.phi*r.phi-variance-ex)/(2*denominator*denominator) - (x-a)/(GLICKO_TAU*GLICKO_TAU)
+	}
+	lower := a
+	var upper float64
+	if delta*delta > r.phi*r.phi+variance {
+		upper = math.Log(delta*delta - r.phi*r.phi - variance)
+	} else {
+		k := 1.0
+		for f(a-k*GLICKO_TAU) < 0 {
+			k++
+		}
+		upper = a - k*GLICKO_TAU
+	}
+	fLower, fUpper := f(lower), f(upper)
+	for math.Abs(upper-lower) > GLICKO_EPSILON {
+		next := lower + (lower-upper)*fLower/(fUpper-fLower)
+		fNext := f(next)
+		if fNext*fUpper <= 0 {
+			lower, fLower = upper, fUpper
+		} else {
+			fLower /= 2
+		}
+		upper, fUpper = next, fNext
+	}
+	return math.Exp(lower / 2)
+}
+
+func glickoG(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+//Makes games of every pair of players by their placement, higher scored player beats lower scored one,
+//equally scored ones draw. Every game weighs 1/(n-1), so a placement among n players counts as one game
+//whatever the field size is && ratings swing like in bracket tournaments
+func placementGames(scores map[uint]int) []*ratedGame {
+	userIds := make([]int, 0, len(scores))
+	for userId := range scores {
+		userIds = append(userIds, int(userId))
+	}
+	sort.Ints(userIds)
+	games := []*ratedGame{}
+	if len(userIds) < 2 {
+		return games
+	}
+	weight := 1 / float64(len(userIds)-1)
+	for i, userId1 := range userIds {
+		for _, userId2 := range userIds[i+1:] {
+			score1 := 0.5
+			if scores[uint(userId1)] > scores[uint(userId2)] {
+				score1 = 1
+			} else if scores[uint(userId1)] < scores[uint(userId2)] {
+				score1 = 0
+			}
+			games = append(games, &ratedGame{player1: uint(userId1), player2: uint(userId2), score1: score1, weight: weight})
+		}
+	}
+	return games
+}
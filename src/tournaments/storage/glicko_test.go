@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGlickoRatingUpdate(t *testing.T) {
+	player := func() *glickoRating {
+		return newGlickoRating(1500, 200, 0.06)
+	}
+	cases := []struct {
+		name       string
+		outcomes   []*glickoOutcome
+		rating     float64
+		deviation  float64
+		volatility float64
+	}{
+		{
+			// Example of http://www.glicko.net/glicko/glicko2.pdf
+			name: "Glickman's example",
+			outcomes: []*glickoOutcome{
+				{opponent: newGlickoRating(1400, 30, 0.06), score: 1, weight: 1},
+				{opponent: newGlickoRating(1550, 100, 0.06), score: 0, weight: 1},
+				{opponent: newGlickoRating(1700, 300, 0.06), score: 0, weight: 1},
+			},
+			rating:     1464.06,
+			deviation:  151.52,
+			volatility: 0.05999,
+		},
+		{
+			name:       "no games grow deviation only",
+			rating:     1500,
+			deviation:  200.27,
+			volatility: 0.06,
+		},
+		{
+			name:       "win against equal",
+			outcomes:   []*glickoOutcome{{opponent: player(), score: 1, weight: 1}},
+			rating:     1578.80,
+			deviation:  180.08,
+			volatility: 0.06,
+		},
+		{
+			name:       "draw against equal",
+			outcomes:   []*glickoOutcome{{opponent: player(), score: 0.5, weight: 1}},
+			rating:     1500,
+			deviation:  180.08,
+			volatility: 0.06,
+		},
+		{
+			name:       "win weighing a third of game",
+			outcomes:   []*glickoOutcome{{opponent: player(), score: 1, weight: 1.0 / 3}},
+			rating:     1530.11,
+			deviation:  192.81,
+			volatility: 0.06,
+		},
+		{
+			name: "three wins weighing a third are one win",
+			outcomes: []*glickoOutcome{
+				{opponent: player(), score: 1, weight: 1.0 / 3},
+				{opponent: player(), score: 1, weight: 1.0 / 3},
+				{opponent: player(), score: 1, weight: 1.0 / 3},
+			},
+			rating:     1578.80,
+			deviation:  180.08,
+			volatility: 0.06,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rated := player().update(c.outcomes)
+			if math.Abs(rated.rating()-c.rating) > 0.05 || math.Abs(rated.deviation()-c.deviation) > 0.05 ||
+				math.Abs(rated.sigma-c.volatility) > 0.00005 {
+				t.Errorf("got %.2f/%.2f/%.5f, want %.2f/%.2f/%.5f",
+					rated.rating(), rated.deviation(), rated.sigma, c.rating, c.deviation, c.volatility)
+			}
+		})
+	}
+}
+
+func TestPlacementGamesWeight(t *testing.T) {
+	cases := []struct {
+		name   string
+		scores map[uint]int
+		games  int
+		weight float64
+	}{
+		{"single player", map[uint]int{1: 100}, 0, 0},
+		{"two players", map[uint]int{1: 100, 2: 0}, 1, 1},
+		{"four players", map[uint]int{1: 100, 2: 50, 3: 0, 4: 0}, 6, 1.0 / 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			games := placementGames(c.scores)
+			if len(games) != c.games {
+				t.Fatalf("got %d games, want %d", len(games), c.games)
+			}
+			// Every player plays n-1 games, so they weigh one game in total
+			for _, game := range games {
+				if game.weight != c.weight {
+					t.Errorf("got weight %v, want %v", game.weight, c.weight)
+				}
+			}
+		})
+	}
+}
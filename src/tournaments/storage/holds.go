@@ -0,0 +1,327 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Closes tournament registration capturing every held deposit by "join" operations.
+//Deposits could not be held for tournament since then
+func (s *Storage) CloseTournamentRegistration(tournamentId uint) (err error) {
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	tournament := &types.Tournament{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(tournament, tournamentId).Error; err != nil {
+		return err
+	}
+	if tournament.State != types.TOURNAMENT_STATE_OPEN {
+		err = errors.New(`Tournament already finished or cancelled!`)
+		return err
+	}
+	if tournament.RegistrationClosedAt != nil {
+		err = errors.New(`Tournament registration already closed!`)
+		return err
+	}
+	if err = s.closeRegistration(tx, tournament); err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+//Withdraws player's entry from tournament releasing deposits held on player's and backers' balances
+//or issuing the ticket paid for the entry again, possible until registration closes
+func (s *Storage) WithdrawFromTournament(request *types.WithdrawTournamentRequest) (err error) {
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	tournament := &types.Tournament{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(tournament, request.TournamentId).Error; err != nil {
+		return err
+	}
+	if tournament.State != types.TOURNAMENT_STATE_OPEN || tournament.RegistrationClosedAt != nil {
+		err = errors.New(`Tournament registration already closed!`)
+		return err
+	}
+	if tx.Where(&types.TournamentPlayer{UserId: request.PlayerId, TournamentId: tournament.ID}).First(&types.TournamentPlayer{}).RecordNotFound() {
+		err = errors.New(`User does not participate tournament!`)
+		return err
+	}
+	var byTicket bool
+	if byTicket, err = s.restoreTicket(tx, tournament.ID, request.PlayerId); err != nil {
+		return err
+	}
+	// Entries made before holds were introduced are debited at once, free-roll ones && ones by ticket hold nothing
+	if !byTicket && !tournament.IsFreeRoll() && tx.Where(&types.BalanceHold{TournamentId: tournament.ID, PlayerId: request.PlayerId, State: types.HOLD_STATE_HELD}).First(&types.BalanceHold{}).RecordNotFound() {
+		err = errors.New(`Entry deposits already debited!`)
+		return err
+	}
+	if err = s.releaseEntry(tx, tournament.ID, request.PlayerId, types.HOLD_STATE_RELEASED); err != nil {
+		return err
+	}
+	if err = s.releaseInvitation(tx, tournament.ID, request.PlayerId); err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+//Cancels not finished tournament releasing held deposits && refunding captured ones and tickets to it,
+//returns prize pool to sponsor if tournament is sponsored
+func (s *Storage) CancelTournament(tournamentId uint) (err error) {
+	var holds []*types.BalanceHold
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	tournament := &types.Tournament{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(tournament, tournamentId).Error; err != nil {
+		return err
+	}
+	if tournament.State != types.TOURNAMENT_STATE_OPEN {
+		err = errors.New(`Tournament already finished or cancelled!`)
+		return err
+	}
+	holds = []*types.BalanceHold{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").Where("tournament_id = ? AND state IN (?)", tournament.ID, []string{types.HOLD_STATE_HELD, types.HOLD_STATE_CAPTURED}).
+		Order("user_id").Find(&holds).Error; err != nil {
+		return err
+	}
+	for _, hold := range holds {
+		if hold.State == types.HOLD_STATE_HELD {
+			err = s.releaseHold(tx, hold, types.HOLD_STATE_RELEASED)
+		} else {
+			err = s.refundHold(tx, hold)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err = s.refundTournamentTickets(tx, tournament); err != nil {
+		return err
+	}
+	if tournament.PrizePool > 0 {
+		if err = s.returnPrizePool(tx, tournament, tournament.PrizePool); err != nil {
+			return err
+		}
+	}
+	if err = tx.Model(tournament).UpdateColumn("state", types.TOURNAMENT_STATE_CANCELLED).Error; err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+//Closes registration of every open tournament started before now,
+//returns number of registrations closed
+func (s *Storage) CloseDueRegistrations(now time.Time) (int, error) {
+	var tournamentIds []uint
+	if err := s.db.Model(&types.Tournament{}).
+		Where("state = ? AND registration_closed_at IS NULL AND date <= ?", types.TOURNAMENT_STATE_OPEN, now).
+		Pluck("id", &tournamentIds).Error; err != nil {
+		return 0, err
+	}
+	closed := 0
+	for _, tournamentId := range tournamentIds {
+		if err := s.CloseTournamentRegistration(tournamentId); err != nil {
+			s.logger.Printf("Could not close registration of tournament %d: %s", tournamentId, err.Error())
+			continue
+		}
+		closed++
+	}
+	return closed, nil
+}
+
+//Withdraws every entry having deposits still held after their expiry passed before given moment,
+//which happens if registration could not be closed in time (tournament is deleted and so on).
+//Returns number of entries withdrawn
+func (s *Storage) ReleaseExpiredHolds(before time.Time) (int, error) {
+	var entries []*struct {
+		TournamentId uint
+		PlayerId     uint
+	}
+	if err := s.db.Raw(`SELECT DISTINCT tournament_id, player_id FROM balance_holds WHERE state = ? AND expires_at < ?`,
+		types.HOLD_STATE_HELD, before).Scan(&entries).Error; err != nil {
+		return 0, err
+	}
+	released := 0
+	for _, entry := range entries {
+		withdrawn, err := s.releaseExpiredEntry(entry.TournamentId, entry.PlayerId)
+		if err != nil {
+			s.logger.Printf("Could not release expired holds of player %d in tournament %d: %s", entry.PlayerId, entry.TournamentId, err.Error())
+			continue
+		}
+		if withdrawn {
+			released++
+		}
+	}
+	return released, nil
+}
+
+//Withdraws entry under tournament lock unless its registration got closed meanwhile capturing the deposits,
+//deleted tournaments are locked too as their holds would never be captured
+func (s *Storage) releaseExpiredEntry(tournamentId uint, playerId uint) (withdrawn bool, err error) {
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	tournament := &types.Tournament{}
+	locked := tx.Unscoped().Set("gorm:query_option", "FOR UPDATE").First(tournament, tournamentId)
+	if locked.Error != nil && !locked.RecordNotFound() {
+		err = locked.Error
+		return false, err
+	}
+	if tournament.RegistrationClosedAt != nil {
+		err = tx.Commit().Error
+		return false, err
+	}
+	if err = s.releaseEntry(tx, tournamentId, playerId, types.HOLD_STATE_EXPIRED); err != nil {
+		return false, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//Reserves stake on stakeholder's balance for entry of playerId until tournament registration closes,
+//spending points expiring soonest first
+func (s *Storage) placeHold(tx *gorm.DB, tournament *types.Tournament, playerId uint, balance *types.UserPointsBalance, stake int) error {
+	hold := &types.BalanceHold{
+		UserId:       balance.UserId,
+		TournamentId: tournament.ID,
+		PlayerId:     playerId,
+		Amount:       stake,
+		Currency:     tournament.Currency,
+		State:        types.HOLD_STATE_HELD,
+		ExpiresAt:    tournament.Date,
+	}
+	if err := tx.Create(hold).Error; err != nil {
+		return err
+	}
+	if err := s.consumeLots(tx, balance.UserId, balance.Currency, stake, hold.ID); err != nil {
+		return err
+	}
+	return tx.Model(balance).UpdateColumn(`held`, gorm.Expr(`held + ?`, stake)).Error
+}
+
+//Captures every held deposit of tournament && marks its registration closed, tournament must be locked
+func (s *Storage) closeRegistration(tx *gorm.DB, tournament *types.Tournament) error {
+	holds := []*types.BalanceHold{}
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").Where(&types.BalanceHold{TournamentId: tournament.ID, State: types.HOLD_STATE_HELD}).
+		Order("user_id").Find(&holds).Error; err != nil {
+		return err
+	}
+	for _, hold := range holds {
+		if err := s.captureHold(tx, hold); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	tournament.RegistrationClosedAt = &now
+	return tx.Model(tournament).UpdateColumn("registration_closed_at", now).Error
+}
+
+//Releases held deposits of player's entry && removes the entry, team entries are owned by team captains
+func (s *Storage) releaseEntry(tx *gorm.DB, tournamentId uint, playerId uint, state string) error {
+	holds := []*types.BalanceHold{}
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").Where(&types.BalanceHold{TournamentId: tournamentId, PlayerId: playerId, State: types.HOLD_STATE_HELD}).
+		Order("user_id").Find(&holds).Error; err != nil {
+		return err
+	}
+	for _, hold := range holds {
+		if err := s.releaseHold(tx, hold, state); err != nil {
+			return err
+		}
+	}
+	if err := s.removeTeamEntry(tx, tournamentId, playerId); err != nil {
+		return err
+	}
+	if err := tx.Where(&types.TournamentBacker{TournamentId: tournamentId, UserId: playerId}).Delete(&types.TournamentBacker{}).Error; err != nil {
+		return err
+	}
+	return tx.Where(&types.TournamentPlayer{TournamentId: tournamentId, UserId: playerId}).Delete(&types.TournamentPlayer{}).Error
+}
+
+//Debits held deposit from stakeholder's balance by "join" operation
+func (s *Storage) captureHold(tx *gorm.DB, hold *types.BalanceHold) error {
+	if err := s.moveHold(tx, hold, types.HOLD_STATE_HELD, types.HOLD_STATE_CAPTURED); err != nil {
+		return err
+	}
+	if err := tx.Create(
+		&UserPointsOperations{
+			UserId:        hold.UserId,
+			OperationType: USER_POINTS_OPERATION_CREDIT,
+			Kind:          types.OPERATION_KIND_JOIN,
+			TournamentId:  hold.TournamentId,
+			Currency:      hold.Currency,
+			Sum:           hold.Amount,
+		}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&types.UserPointsBalance{}).Where(&types.UserPointsBalance{UserId: hold.UserId, Currency: hold.Currency}).
+		UpdateColumns(map[string]interface{}{
+			"balance": gorm.Expr(`balance - ?`, hold.Amount),
+			"held":    gorm.Expr(`held - ?`, hold.Amount),
+		}).Error
+}
+
+//Makes held deposit available on stakeholder's balance again returning it to lots it was spent from,
+//state is one of released or expired
+func (s *Storage) releaseHold(tx *gorm.DB, hold *types.BalanceHold, state string) error {
+	if err := s.moveHold(tx, hold, types.HOLD_STATE_HELD, state); err != nil {
+		return err
+	}
+	if err := tx.Model(&types.UserPointsBalance{}).Where(&types.UserPointsBalance{UserId: hold.UserId, Currency: hold.Currency}).
+		UpdateColumn(`held`, gorm.Expr(`held - ?`, hold.Amount)).Error; err != nil {
+		return err
+	}
+	return s.restoreLots(tx, hold.ID)
+}
+
+//Returns captured deposit to stakeholder's balance by "refund" operation and to lots it was spent from
+func (s *Storage) refundHold(tx *gorm.DB, hold *types.BalanceHold) error {
+	if err := s.moveHold(tx, hold, types.HOLD_STATE_CAPTURED, types.HOLD_STATE_REFUNDED); err != nil {
+		return err
+	}
+	if err := tx.Create(
+		&UserPointsOperations{
+			UserId:        hold.UserId,
+			OperationType: USER_POINTS_OPERATION_DEBT,
+			Kind:          types.OPERATION_KIND_REFUND,
+			TournamentId:  hold.TournamentId,
+			Currency:      hold.Currency,
+			Sum:           hold.Amount,
+		}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&types.UserPointsBalance{}).Where(&types.UserPointsBalance{UserId: hold.UserId, Currency: hold.Currency}).
+		UpdateColumn(`balance`, gorm.Expr(`balance + ?`, hold.Amount)).Error; err != nil {
+		return err
+	}
+	return s.restoreLots(tx, hold.ID)
+}
+
+//Changes hold state if it's still the given one, so concurrent transaction which settled the hold first wins
+//&& the other one fails before touching the balance
+func (s *Storage) moveHold(tx *gorm.DB, hold *types.BalanceHold, from string, to string) error {
+	result := tx.Model(&types.BalanceHold{}).Where("id = ? AND state = ?", hold.ID, from).UpdateColumn("state", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(`Deposit hold already settled!`)
+	}
+	hold.State = to
+	return nil
+}
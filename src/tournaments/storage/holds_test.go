@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//States of tournament holds by stakeholder
+func holdStates(t *testing.T, s *Storage, tournamentId uint) map[uint]string {
+	t.Helper()
+	holds := []*types.BalanceHold{}
+	if err := s.db.Where(&types.BalanceHold{TournamentId: tournamentId}).Find(&holds).Error; err != nil {
+		t.Fatal(err)
+	}
+	states := map[uint]string{}
+	for _, hold := range holds {
+		states[hold.UserId] = hold.State
+	}
+	return states
+}
+
+func expectBalance(t *testing.T, s *Storage, userId uint, balance int, held int) {
+	t.Helper()
+	if actual := balanceOf(t, s, userId); actual.Balance != balance || actual.Held != held {
+		t.Errorf("user %d got balance %d held %d, want %d held %d", userId, actual.Balance, actual.Held, balance, held)
+	}
+}
+
+//Announces tournament of deposit 30 entered by player 1 backed by 2, both having 100 points
+func backedEntry(t *testing.T) (*Storage, *types.Tournament) {
+	t.Helper()
+	s := newTestStorage(t, nil)
+	mustTopUp(t, s, 1, 100)
+	mustTopUp(t, s, 2, 100)
+	tournament := mustAnnounce(t, s, &types.AnnounceTournamentRequest{Deposit: 30})
+	mustJoin(t, s, &types.JoinTournamentRequest{TournamentId: tournament.ID, PlayerId: 1, BackerIds: []uint{2}})
+	expectBalance(t, s, 1, 100, 15)
+	expectBalance(t, s, 2, 100, 15)
+	return s, tournament
+}
+
+func TestHoldsCapturedAsRegistrationCloses(t *testing.T) {
+	s, tournament := backedEntry(t)
+	if err := s.CloseTournamentRegistration(tournament.ID); err != nil {
+		t.Fatal(err)
+	}
+	expectBalance(t, s, 1, 85, 0)
+	expectBalance(t, s, 2, 85, 0)
+	if states := holdStates(t, s, tournament.ID); states[1] != types.HOLD_STATE_CAPTURED || states[2] != types.HOLD_STATE_CAPTURED {
+		t.Errorf("got hold states %v, want both captured", states)
+	}
+	if err := s.CloseTournamentRegistration(tournament.ID); err == nil {
+		t.Error("registration closed twice")
+	}
+	if err := s.WithdrawFromTournament(&types.WithdrawTournamentRequest{TournamentId: tournament.ID, PlayerId: 1}); err == nil {
+		t.Error("entry withdrawn after registration closed")
+	}
+
+	// Captured deposits are refunded as tournament is cancelled
+	if err := s.CancelTournament(tournament.ID); err != nil {
+		t.Fatal(err)
+	}
+	expectBalance(t, s, 1, 100, 0)
+	expectBalance(t, s, 2, 100, 0)
+	if states := holdStates(t, s, tournament.ID); states[1] != types.HOLD_STATE_REFUNDED || states[2] != types.HOLD_STATE_REFUNDED {
+		t.Errorf("got hold states %v, want both refunded", states)
+	}
+}
+
+func TestWithdrawReleasesHolds(t *testing.T) {
+	s, tournament := backedEntry(t)
+	request := &types.WithdrawTournamentRequest{TournamentId: tournament.ID, PlayerId: 1}
+	if err := s.WithdrawFromTournament(request); err != nil {
+		t.Fatal(err)
+	}
+	expectBalance(t, s, 1, 100, 0)
+	expectBalance(t, s, 2, 100, 0)
+	if states := holdStates(t, s, tournament.ID); states[1] != types.HOLD_STATE_RELEASED || states[2] != types.HOLD_STATE_RELEASED {
+		t.Errorf("got hold states %v, want both released", states)
+	}
+	if err := s.WithdrawFromTournament(request); err == nil {
+		t.Error("entry withdrawn twice")
+	}
+	// Nothing is left to capture
+	if err := s.CloseTournamentRegistration(tournament.ID); err != nil {
+		t.Fatal(err)
+	}
+	expectBalance(t, s, 1, 100, 0)
+}
+
+func TestReleaseExpiredHolds(t *testing.T) {
+	s, tournament := backedEntry(t)
+	// Registration was not closed in time
+	if err := s.db.Model(&types.BalanceHold{}).UpdateColumn("expires_at", time.Now().Add(-time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	released, err := s.ReleaseExpiredHolds(time.Now())
+	if err != nil || released != 1 {
+		t.Fatalf("got %d entries released, error %v, want 1", released, err)
+	}
+	expectBalance(t, s, 1, 100, 0)
+	expectBalance(t, s, 2, 100, 0)
+	if states := holdStates(t, s, tournament.ID); states[1] != types.HOLD_STATE_EXPIRED || states[2] != types.HOLD_STATE_EXPIRED {
+		t.Errorf("got hold states %v, want both expired", states)
+	}
+	if released, _ := s.ReleaseExpiredHolds(time.Now()); released != 0 {
+		t.Errorf("got %d entries released again", released)
+	}
+}
+
+func TestExpiredEntryOfClosedRegistrationIsKept(t *testing.T) {
+	s, tournament := backedEntry(t)
+	// Registration closes while the expiry job is about to withdraw the entry
+	if err := s.CloseTournamentRegistration(tournament.ID); err != nil {
+		t.Fatal(err)
+	}
+	withdrawn, err := s.releaseExpiredEntry(tournament.ID, 1)
+	if err != nil || withdrawn {
+		t.Fatalf("got withdrawn %v, error %v, want entry kept", withdrawn, err)
+	}
+	players, _ := s.FetchTournamentPlayers([]uint{tournament.ID})
+	if len(players.([]*types.TournamentPlayer)) != 1 {
+		t.Errorf("got players %v, want the entry kept", players)
+	}
+	expectBalance(t, s, 1, 85, 0)
+}
+
+func TestHoldIsSettledOnce(t *testing.T) {
+	s, tournament := backedEntry(t)
+	hold := &types.BalanceHold{}
+	if err := s.db.Where(&types.BalanceHold{TournamentId: tournament.ID, UserId: 1}).First(hold).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Concurrent transaction captures the hold loaded by another one before it releases the hold
+	stale := *hold
+	if err := s.captureHold(s.db, hold); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.releaseHold(s.db, &stale, types.HOLD_STATE_RELEASED); err == nil {
+		t.Error("captured hold released")
+	}
+	if err := s.captureHold(s.db, &stale); err == nil {
+		t.Error("hold captured twice")
+	}
+	expectBalance(t, s, 1, 85, 0)
+}
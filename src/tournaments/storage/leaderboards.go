@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Computed leaderboard is served for so long before it's computed again
+const LEADERBOARD_CACHE_TTL = time.Minute
+
+type leaderboardKey struct {
+	metric   string
+	gameId   int
+	window   string
+	seasonId uint
+	currency string
+}
+
+// Prizes won && deposits paid by every stakeholder of tournaments: winner && its backers share prize equally
+// (indivisible remainder is never paid), players, backers && team members pay their deposits.
+// Satellite ticket is won at its value unless it expired worthless && pays seat it's used for at the same value,
+// it's valued in currency of its tournament, NULL currency is the one of tournament
+const STAKEHOLDER_RESULTS_QUERY = `SELECT w.tournament_id, w.user_id, w.prize / (1 + COALESCE(b.count, 0)) AS won, 0 AS paid,
+	NULL AS currency
+	FROM tournament_winners w
+	LEFT JOIN (` + BACKERS_COUNT_QUERY + `) b ON b.tournament_id = w.tournament_id AND b.user_id = w.user_id
+	WHERE w.deleted_at IS NULL
+	UNION ALL
+	SELECT w.tournament_id, bk.backer_id, w.prize / (1 + b.count), 0, NULL
+	FROM tournament_winners w
+	JOIN tournament_backers bk ON bk.tournament_id = w.tournament_id AND bk.user_id = w.user_id AND bk.deleted_at IS NULL
+	JOIN (` + BACKERS_COUNT_QUERY + `) b ON b.tournament_id = w.tournament_id AND b.user_id = w.user_id
+	WHERE w.deleted_at IS NULL
+	UNION ALL
+	SELECT tournament_id, user_id, 0, user_deposit, NULL FROM tournament_players WHERE deleted_at IS NULL
+	UNION ALL
+	SELECT tournament_id, backer_id, 0, backer_deposit, NULL FROM tournament_backers WHERE deleted_at IS NULL
+	UNION ALL
+	SELECT tournament_id, user_id, 0, deposit, NULL FROM tournament_team_members
+	UNION ALL
+	SELECT satellite_id, user_id, value, 0, currency FROM tickets WHERE state <> '` + types.TICKET_STATE_EXPIRED + `'`
+
+const BACKERS_COUNT_QUERY = `SELECT tournament_id, user_id, COUNT(*) AS count FROM tournament_backers
+	WHERE deleted_at IS NULL GROUP BY tournament_id, user_id`
+
+// Every ranked player of leaderboard, best first
+type cachedLeaderboard struct {
+	// Held while leaderboard is computed, so concurrent requests of it wait for the only computation
+	sync.Mutex
+	entries    []*types.LeaderboardEntry
+	computedAt time.Time
+	// Requests waiting for or reading the leaderboard, guarded by leaderboardCache lock
+	users int
+}
+
+type leaderboardCache struct {
+	// Guards boards map only, leaderboards are computed under their own locks
+	sync.Mutex
+	boards map[leaderboardKey]*cachedLeaderboard
+}
+
+func newLeaderboardCache() *leaderboardCache {
+	return &leaderboardCache{boards: map[leaderboardKey]*cachedLeaderboard{}}
+}
+
+//Returns leaderboard of key registering its user, evicts expired leaderboards nobody uses,
+//so boards of arbitrary games && seasons don't pile up
+func (c *leaderboardCache) acquire(key leaderboardKey, now time.Time) *cachedLeaderboard {
+	c.Lock()
+	defer c.Unlock()
+
+	for cachedKey, cached := range c.boards {
+		if cached.users == 0 && now.Sub(cached.computedAt) >= LEADERBOARD_CACHE_TTL {
+			delete(c.boards, cachedKey)
+		}
+	}
+	board, ok := c.boards[key]
+	if !ok {
+		board = &cachedLeaderboard{}
+		c.boards[key] = board
+	}
+	board.users++
+	return board
+}
+
+func (c *leaderboardCache) release(board *cachedLeaderboard) {
+	c.Lock()
+	defer c.Unlock()
+	board.users--
+}
+
+// Value of a player scanned from leaderboard queries
+type leaderboardRow struct {
+	UserId uint
+	Value  float64
+}
+
+//Fetches page of leaderboard along with queried user's entry,
+//leaderboard is computed once per LEADERBOARD_CACHE_TTL for every metric, game, window, season && currency
+func (s *Storage) FetchLeaderboard(query *types.LeaderboardQuery) (interface{}, error) {
+	key := leaderboardKey{metric: query.Metric, gameId: query.GameId, window: query.Window, seasonId: query.SeasonId}
+	if query.Metric == types.LEADERBOARD_METRIC_WINNINGS || query.Metric == types.LEADERBOARD_METRIC_NET_PROFIT {
+		key.currency = types.CurrencyOrDefault(query.Currency)
+	}
+	board, err := s.leaderboard(key)
+	if err != nil {
+		s.logger.Println(err.Error())
+		return nil, errors.New("An error occured during leaderboard computing")
+	}
+
+	page := &types.LeaderboardPage{
+		Entries:    []*types.LeaderboardEntry{},
+		Total:      len(board.entries),
+		ComputedAt: board.computedAt,
+	}
+	if query.Offset < len(board.entries) {
+		end := query.Offset + query.Limit
+		if end > len(board.entries) {
+			end = len(board.entries)
+		}
+		page.Entries = board.entries[query.Offset:end]
+	}
+	if query.UserId != 0 {
+		for _, entry := range board.entries {
+			if entry.UserId == query.UserId {
+				page.Me = entry
+				break
+			}
+		}
+	}
+	return page, nil
+}
+
+//Returns cached leaderboard computing it if it's absent or expired,
+//concurrent requests of the same leaderboard wait for the only computation, requests of other ones don't
+func (s *Storage) leaderboard(key leaderboardKey) (*cachedLeaderboard, error) {
+	board := s.leaderboards.acquire(key, time.Now())
+	defer s.leaderboards.release(board)
+	board.Lock()
+	defer board.Unlock()
+
+	if time.Since(board.computedAt) < LEADERBOARD_CACHE_TTL {
+		return &cachedLeaderboard{entries: board.entries, computedAt: board.computedAt}, nil
+	}
+	computedAt := time.Now()
+	gameId, since, until := key.gameId, types.LeaderboardWindowStart(key.window, computedAt), time.Time{}
+	if key.seasonId != 0 {
+		season := &types.Season{}
+		if err := s.db.First(season, key.seasonId).Error; err != nil {
+			return nil, err
+		}
+		gameId, since, until = season.GameId, season.StartsAt, season.EndsAt
+	}
+	rows, err := s.leaderboardRows(key, gameId, since, until)
+	if err != nil {
+		return nil, err
+	}
+	board.entries, board.computedAt = rankLeaderboard(rows), computedAt
+	return &cachedLeaderboard{entries: board.entries, computedAt: board.computedAt}, nil
+}
+
+//Queries value of every player having one for leaderboard of tournaments of game (any if 0)
+//dated since given time till the other one, unbounded by zero times;
+//winnings && net profit come from tournament winners && entries, so they include backed stakes
+//&& tournaments played before the ledger recorded tournaments of operations
+func (s *Storage) leaderboardRows(key leaderboardKey, gameId int, since time.Time, until time.Time) ([]*leaderboardRow, error) {
+	rows := []*leaderboardRow{}
+	if key.metric == types.LEADERBOARD_METRIC_RATING {
+		err := s.db.Model(&types.Rating{}).Select("user_id, rating AS value").
+			Where(&types.Rating{GameId: gameId}).Scan(&rows).Error
+		return rows, err
+	}
+
+	var db *gorm.DB
+	switch key.metric {
+	case types.LEADERBOARD_METRIC_TOURNAMENTS_WON:
+		// Every member of winning team wins
+		db = s.db.Table("(" + WINNER_ENTRY_PRIZES_QUERY + ") o").
+			Select("o.user_id, COUNT(*) AS value").
+			Where("o.entry_prize > 0").
+			Where(`o.entry_prize = (SELECT MAX(w.entry_prize) FROM (` + WINNER_ENTRY_PRIZES_QUERY + `) w
+				WHERE w.tournament_id = o.tournament_id)`)
+	case types.LEADERBOARD_METRIC_NET_PROFIT:
+		db = s.db.Table("("+STAKEHOLDER_RESULTS_QUERY+") o").
+			Select("o.user_id, SUM(o.won - o.paid) AS value").
+			Where("COALESCE(o.currency, t.currency) = ?", key.currency)
+	default:
+		db = s.db.Table("("+STAKEHOLDER_RESULTS_QUERY+") o").
+			Select("o.user_id, SUM(o.won) AS value").
+			Where("o.won > 0 AND COALESCE(o.currency, t.currency) = ?", key.currency)
+	}
+	db = db.Joins("JOIN tournaments t ON t.id = o.tournament_id").
+		Where("t.deleted_at IS NULL AND t.state = ?", types.TOURNAMENT_STATE_FINISHED)
+	if gameId != 0 {
+		db = db.Where("t.game_id = ?", gameId)
+	}
+	if !since.IsZero() {
+		db = db.Where("t.date >= ?", since)
+	}
+	if !until.IsZero() {
+		db = db.Where("t.date < ?", until)
+	}
+	err := db.Group("o.user_id").Scan(&rows).Error
+	return rows, err
+}
+
+//Orders rows by value, best first, ties by user ID, ranking tied players equally
+func rankLeaderboard(rows []*leaderboardRow) []*types.LeaderboardEntry {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Value != rows[j].Value {
+			return rows[i].Value > rows[j].Value
+		}
+		return rows[i].UserId < rows[j].UserId
+	})
+	entries := make([]*types.LeaderboardEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = &types.LeaderboardEntry{Rank: i + 1, UserId: row.UserId, Value: row.Value}
+		if i > 0 && row.Value == rows[i-1].Value {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+	return entries
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+func TestRankLeaderboard(t *testing.T) {
+	cases := []struct {
+		name    string
+		rows    []*leaderboardRow
+		entries []*types.LeaderboardEntry
+	}{
+		{
+			name:    "empty",
+			rows:    []*leaderboardRow{},
+			entries: []*types.LeaderboardEntry{},
+		},
+		{
+			name: "best first",
+			rows: []*leaderboardRow{{UserId: 1, Value: 10}, {UserId: 2, Value: 30}, {UserId: 3, Value: -5}},
+			entries: []*types.LeaderboardEntry{
+				{Rank: 1, UserId: 2, Value: 30},
+				{Rank: 2, UserId: 1, Value: 10},
+				{Rank: 3, UserId: 3, Value: -5},
+			},
+		},
+		{
+			name: "ties share rank, ordered by user ID",
+			rows: []*leaderboardRow{{UserId: 4, Value: 10}, {UserId: 2, Value: 20}, {UserId: 3, Value: 10}, {UserId: 1, Value: 5}},
+			entries: []*types.LeaderboardEntry{
+				{Rank: 1, UserId: 2, Value: 20},
+				{Rank: 2, UserId: 3, Value: 10},
+				{Rank: 2, UserId: 4, Value: 10},
+				{Rank: 4, UserId: 1, Value: 5},
+			},
+		},
+		{
+			name: "everybody tied",
+			rows: []*leaderboardRow{{UserId: 2, Value: 0.5}, {UserId: 1, Value: 0.5}},
+			entries: []*types.LeaderboardEntry{
+				{Rank: 1, UserId: 1, Value: 0.5},
+				{Rank: 1, UserId: 2, Value: 0.5},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if entries := rankLeaderboard(c.rows); !reflect.DeepEqual(entries, c.entries) {
+				t.Errorf("got %v, want %v", entries, c.entries)
+			}
+		})
+	}
+}
+
+func TestLeaderboardCacheEviction(t *testing.T) {
+	now := time.Date(2018, 3, 10, 18, 0, 0, 0, time.UTC)
+	used, unused, other := leaderboardKey{metric: "wins"}, leaderboardKey{metric: "winnings"}, leaderboardKey{metric: "net_profit"}
+
+	cache := newLeaderboardCache()
+	usedBoard := cache.acquire(used, now)
+	usedBoard.computedAt = now
+	unusedBoard := cache.acquire(unused, now)
+	unusedBoard.computedAt = now
+	cache.release(unusedBoard)
+
+	cases := []struct {
+		name string
+		at   time.Time
+		kept map[leaderboardKey]bool
+	}{
+		{"fresh boards are kept", now.Add(LEADERBOARD_CACHE_TTL / 2), map[leaderboardKey]bool{used: true, unused: true, other: true}},
+		{"expired unused board is evicted", now.Add(LEADERBOARD_CACHE_TTL), map[leaderboardKey]bool{used: true, other: true}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cache.release(cache.acquire(other, c.at))
+			kept := map[leaderboardKey]bool{}
+			for key := range cache.boards {
+				kept[key] = true
+			}
+			if !reflect.DeepEqual(kept, c.kept) {
+				t.Errorf("got boards %v, want %v", kept, c.kept)
+			}
+		})
+	}
+	if cache.acquire(used, now) != usedBoard || usedBoard.users != 2 {
+		t.Errorf("board in use is not shared, it has %d users", usedBoard.users)
+	}
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Deposits of players, backers && team members of every tournament debited from their balances,
+// seats paid by tickets bring nothing
+const COLLECTED_DEPOSITS_QUERY = `SELECT t.id AS tournament_id, COALESCE(p.sum, 0) + COALESCE(bk.sum, 0) + COALESCE(tm.sum, 0) AS collected
+	FROM tournaments t
+	LEFT JOIN (SELECT tournament_id, SUM(user_deposit) AS sum FROM tournament_players
+		WHERE deleted_at IS NULL AND ticket_id = 0 GROUP BY tournament_id) p ON p.tournament_id = t.id
+	LEFT JOIN (SELECT tournament_id, SUM(backer_deposit) AS sum FROM tournament_backers
+		WHERE deleted_at IS NULL GROUP BY tournament_id) bk ON bk.tournament_id = t.id
+	LEFT JOIN (SELECT tournament_id, SUM(deposit) AS sum FROM tournament_team_members
+		GROUP BY tournament_id) tm ON tm.tournament_id = t.id`
+
+//Fetches page of finished tournaments having guaranteed prize pool, newest first,
+//with their overlays funded by the house
+func (s *Storage) FetchOverlays(query *types.OverlaysQuery) (interface{}, error) {
+	var (
+		overlays []*types.TournamentOverlay
+		totals   struct {
+			Total        int
+			TotalOverlay int
+		}
+		err error
+	)
+	db := s.db.Table("tournaments t").
+		Joins("JOIN ("+COLLECTED_DEPOSITS_QUERY+") c ON c.tournament_id = t.id").
+		Where("t.deleted_at IS NULL AND t.guaranteed_pool > 0 AND t.state = ?", types.TOURNAMENT_STATE_FINISHED)
+	if query.Currency != "" {
+		db = db.Where("t.currency = ?", query.Currency)
+	}
+	if query.OverlaidOnly {
+		db = db.Where("t.overlay > 0")
+	}
+	if err = db.Select("COUNT(*) AS total, COALESCE(SUM(t.overlay), 0) AS total_overlay").Scan(&totals).Error; err != nil {
+		return nil, errors.New("An error occured during overlays counting")
+	}
+	overlays = []*types.TournamentOverlay{}
+	if err = db.Select("t.id AS tournament_id, t.date, t.currency, t.guaranteed_pool, c.collected, t.prize_pool, t.overlay").
+		Order("t.date DESC, t.id DESC").Limit(query.Limit).Offset(query.Offset).Scan(&overlays).Error; err != nil {
+		return nil, errors.New("An error occured during overlays fetching")
+	}
+	return &types.OverlaysPage{
+		Overlays:     overlays,
+		Total:        totals.Total,
+		TotalOverlay: totals.TotalOverlay,
+	}, nil
+}
+
+//Returns shortfall of collected deposits && prize pool against guaranteed pool of tournament, 0 if there is none
+func (s *Storage) tournamentOverlay(tx *gorm.DB, tournament *types.Tournament) (int, error) {
+	if tournament.GuaranteedPool == 0 {
+		return 0, nil
+	}
+	var deposits struct {
+		Collected int
+	}
+	if err := tx.Raw(COLLECTED_DEPOSITS_QUERY+` WHERE t.id = ?`, tournament.ID).Scan(&deposits).Error; err != nil {
+		return 0, err
+	}
+	overlay := tournament.GuaranteedPool - deposits.Collected - tournament.PrizePool
+	if overlay < 0 {
+		return 0, nil
+	}
+	return overlay, nil
+}
+
+//Debits overlay of guaranteed prize pool from the house wallet of tournament currency by "overlay" operation,
+//the house guarantees the pool, so its wallet may go negative. Tournament must be locked
+func (s *Storage) fundOverlay(tx *gorm.DB, tournament *types.Tournament, overlay int) error {
+	if err := s.debitHouse(tx, tournament.Currency, types.OPERATION_KIND_OVERLAY, tournament.ID, overlay); err != nil {
+		return err
+	}
+	tournament.Overlay = overlay
+	return tx.Model(tournament).UpdateColumn("overlay", overlay).Error
+}
+
+//Debits sum from the house wallet of currency by operation of given kind creating the wallet if absent,
+//the wallet may go negative
+func (s *Storage) debitHouse(tx *gorm.DB, currency string, kind string, tournamentId uint, sum int) error {
+	houseId := s.transfersConf.HouseUserId
+	if err := s.ensureWallet(tx, houseId, currency); err != nil {
+		return err
+	}
+	if err := tx.Create(
+		&UserPointsOperations{
+			UserId:        houseId,
+			OperationType: USER_POINTS_OPERATION_CREDIT,
+			Kind:          kind,
+			TournamentId:  tournamentId,
+			Currency:      currency,
+			Sum:           sum,
+		}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&types.UserPointsBalance{}).Where(&types.UserPointsBalance{UserId: houseId, Currency: currency}).
+		UpdateColumn(`balance`, gorm.Expr(`balance - ?`, sum)).Error; err != nil {
+		return err
+	}
+	return nil
+}
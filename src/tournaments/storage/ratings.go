@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Fetches user's ratings in every game or in given one, best first
+func (s *Storage) FetchRatings(query *types.RatingsQuery) (interface{}, error) {
+	ratings := []*types.Rating{}
+	if err := s.db.Where(&types.Rating{UserId: query.UserId, GameId: query.GameId}).Order("rating DESC, game_id").Find(&ratings).Error; err != nil {
+		return nil, errors.New("An error occured during ratings fetching")
+	}
+	return ratings, nil
+}
+
+//Fetches changes of user's ratings by tournaments, newest first
+func (s *Storage) FetchRatingHistory(query *types.RatingHistoryQuery) (interface{}, error) {
+	var (
+		changes []*types.RatingChange
+		total   int
+		err     error
+	)
+	db := s.db.Model(&types.RatingChange{}).Where(&types.RatingChange{UserId: query.UserId, GameId: query.GameId})
+	if err = db.Count(&total).Error; err != nil {
+		return nil, errors.New("An error occured during rating history counting")
+	}
+	changes = []*types.RatingChange{}
+	if err = db.Order("id DESC").Limit(query.Limit).Offset(query.Offset).Find(&changes).Error; err != nil {
+		return nil, errors.New("An error occured during rating history fetching")
+	}
+	return &types.RatingHistoryPage{
+		Changes: changes,
+		Total:   total,
+	}, nil
+}
+
+//Rebuilds every rating && its history rating finished tournaments one by one in order they were played
+func (s *Storage) RecomputeRatings() (result interface{}, err error) {
+	var tournaments []*types.Tournament
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	// Tournaments finishing meanwhile would be rated against ratings being rebuilt
+	if err = tx.Exec(`LOCK TABLE ratings, rating_changes IN EXCLUSIVE MODE`).Error; err != nil {
+		return nil, err
+	}
+	if err = tx.Delete(&types.RatingChange{}).Error; err != nil {
+		return nil, err
+	}
+	if err = tx.Delete(&types.Rating{}).Error; err != nil {
+		return nil, err
+	}
+	tournaments = []*types.Tournament{}
+	if err = tx.Where(&types.Tournament{State: types.TOURNAMENT_STATE_FINISHED}).Order("date, id").Find(&tournaments).Error; err != nil {
+		return nil, err
+	}
+	recomputation := &types.RatingsRecomputation{RecomputedAt: time.Now()}
+	for _, tournament := range tournaments {
+		if err = s.rateTournament(tx, tournament); err != nil {
+			return nil, err
+		}
+		recomputation.Tournaments++
+	}
+	if err = tx.Model(&types.Rating{}).Count(&recomputation.Ratings).Error; err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return recomputation, nil
+}
+
+//Rates players of finished tournament in its game as a rating period by outcomes of its matches
+//if it had a bracket, by placements (prizes won) otherwise, recording changes to rating history
+func (s *Storage) rateTournament(tx *gorm.DB, tournament *types.Tournament) error {
+	// Ratings are individual
+	if tournament.IsTeamTournament() {
+		return nil
+	}
+	games, err := s.tournamentGames(tx, tournament)
+	if err != nil || len(games) == 0 {
+		return err
+	}
+	userIds := []int{}
+	seen := map[uint]bool{}
+	for _, game := range games {
+		for _, userId := range []uint{game.player1, game.player2} {
+			if !seen[userId] {
+				seen[userId] = true
+				userIds = append(userIds, int(userId))
+			}
+		}
+	}
+	// Ratings are locked in the same order by every tournament
+	sort.Ints(userIds)
+
+	ratings := make(map[uint]*types.Rating, len(userIds))
+	before := make(map[uint]*glickoRating, len(userIds))
+	for _, userId := range userIds {
+		rating := &types.Rating{}
+		if err = tx.Set("gorm:query_option", "FOR UPDATE").
+			Where(map[string]interface{}{"user_id": uint(userId), "game_id": tournament.GameId}).
+			Attrs(&types.Rating{
+				Rating:     types.RATING_INITIAL,
+				Deviation:  types.RATING_INITIAL_DEVIATION,
+				Volatility: types.RATING_INITIAL_VOLATILITY,
+			}).
+			FirstOrCreate(rating).Error; err != nil {
+			return err
+		}
+		ratings[uint(userId)] = rating
+		before[uint(userId)] = newGlickoRating(rating.Rating, rating.Deviation, rating.Volatility)
+	}
+
+	rated := rateGames(before, games)
+	for _, userId := range userIds {
+		rating, after := ratings[uint(userId)], rated[uint(userId)]
+		if err = tx.Create(&types.RatingChange{
+			UserId:          rating.UserId,
+			GameId:          rating.GameId,
+			TournamentId:    tournament.ID,
+			RatingBefore:    rating.Rating,
+			Rating:          after.rating(),
+			DeviationBefore: rating.Deviation,
+			Deviation:       after.deviation(),
+			Volatility:      after.sigma,
+		}).Error; err != nil {
+			return err
+		}
+		if err = tx.Model(rating).Updates(map[string]interface{}{
+			"rating":      after.rating(),
+			"deviation":   after.deviation(),
+			"volatility":  after.sigma,
+			"tournaments": gorm.Expr(`tournaments + 1`),
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Returns games of finished matches of tournament bracket,
+//for tournaments without bracket every pair of players plays a fractional game won by player having bigger prize
+func (s *Storage) tournamentGames(tx *gorm.DB, tournament *types.Tournament) ([]*ratedGame, error) {
+	matches := []*types.Match{}
+	if err := tx.Where("tournament_id = ? AND state = ? AND NOT bye", tournament.ID, types.MATCH_STATE_FINISHED).
+		Order("id").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	if len(matches) > 0 {
+		games := make([]*ratedGame, 0, len(matches))
+		for _, match := range matches {
+			game := &ratedGame{player1: match.Player1Id, player2: match.Player2Id, score1: 0.5, weight: 1}
+			if match.WinnerId == match.Player1Id {
+				game.score1 = 1
+			} else if match.WinnerId == match.Player2Id {
+				game.score1 = 0
+			}
+			games = append(games, game)
+		}
+		return games, nil
+	}
+
+	var playerIds []uint
+	if err := tx.Model(&types.TournamentPlayer{}).Where(&types.TournamentPlayer{TournamentId: tournament.ID}).
+		Pluck("user_id", &playerIds).Error; err != nil {
+		return nil, err
+	}
+	winners := []*types.TournamentWinner{}
+	if err := tx.Where(&types.TournamentWinner{TournamentId: tournament.ID}).Find(&winners).Error; err != nil {
+		return nil, err
+	}
+	// Players not winning anything share the last place
+	scores := make(map[uint]int, len(playerIds))
+	for _, playerId := range playerIds {
+		scores[playerId] = -1
+	}
+	for _, winner := range winners {
+		scores[winner.UserId] = winner.Prize
+	}
+	return placementGames(scores), nil
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Sum of user wallet operations, credited points positive, debited ones negative
+const LEDGER_SUM_QUERY = `SELECT user_id, currency, SUM(CASE WHEN operation_type = %d THEN sum ELSE -sum END) AS expected
+	FROM user_points_operations
+	WHERE deleted_at IS NULL
+	GROUP BY user_id, currency`
+
+// Sum of user wallet holds not captured or released yet
+const HELD_SUM_QUERY = `SELECT user_id, currency, SUM(amount) AS expected_held
+	FROM balance_holds
+	WHERE state = '%s'
+	GROUP BY user_id, currency`
+
+const BALANCE_DISCREPANCIES_QUERY = `SELECT b.user_id, b.currency, b.balance, COALESCE(o.expected, 0) AS expected,
+	b.held, COALESCE(h.expected_held, 0) AS expected_held
+	FROM user_points_balances b
+	LEFT JOIN (%s) o ON o.user_id = b.user_id AND o.currency = b.currency
+	LEFT JOIN (%s) h ON h.user_id = b.user_id AND h.currency = b.currency
+	WHERE b.deleted_at IS NULL AND (b.balance <> COALESCE(o.expected, 0) OR b.held <> COALESCE(h.expected_held, 0))
+	ORDER BY b.user_id, b.currency`
+
+// Prizes are spread by integer division between winner and backers,
+// so a remainder of prize is never credited and must not be expected in ledger.
+// Seats paid by tickets are not debited, team entries are paid by members' deposits
+const ESCROW_MISMATCHES_QUERY = `SELECT * FROM (SELECT t.id AS tournament_id, t.state,
+	CASE WHEN t.state = %d THEN 0 ELSE COALESCE(p.sum, 0) + COALESCE(bk.sum, 0) + COALESCE(tm.sum, 0) END AS deposits,
+	COALESCE(j.sum, 0) - COALESCE(rf.sum, 0) AS debited,
+	COALESCE(h.sum, 0) AS held,
+	COALESCE(w.sum, 0) AS prizes,
+	COALESCE(pr.sum, 0) AS credited
+	FROM tournaments t
+	LEFT JOIN (SELECT tournament_id, SUM(user_deposit) AS sum FROM tournament_players
+		WHERE deleted_at IS NULL AND ticket_id = 0 GROUP BY tournament_id) p ON p.tournament_id = t.id
+	LEFT JOIN (SELECT tournament_id, SUM(backer_deposit) AS sum FROM tournament_backers
+		WHERE deleted_at IS NULL GROUP BY tournament_id) bk ON bk.tournament_id = t.id
+	LEFT JOIN (SELECT tournament_id, SUM(deposit) AS sum FROM tournament_team_members
+		GROUP BY tournament_id) tm ON tm.tournament_id = t.id
+	LEFT JOIN (SELECT tournament_id, SUM(sum) AS sum FROM user_points_operations
+		WHERE deleted_at IS NULL AND kind = '%s' GROUP BY tournament_id) j ON j.tournament_id = t.id
+	LEFT JOIN (SELECT tournament_id, SUM(sum) AS sum FROM user_points_operations
+		WHERE deleted_at IS NULL AND kind = '%s' GROUP BY tournament_id) rf ON rf.tournament_id = t.id
+	LEFT JOIN (SELECT tournament_id, SUM(amount) AS sum FROM balance_holds
+		WHERE state = '%s' GROUP BY tournament_id) h ON h.tournament_id = t.id
+	LEFT JOIN (SELECT w.tournament_id, SUM((w.prize / (1 + COALESCE(b.count, 0))) * (1 + COALESCE(b.count, 0))) AS sum
+		FROM tournament_winners w
+		LEFT JOIN (SELECT tournament_id, user_id, COUNT(*) AS count FROM tournament_backers
+			WHERE deleted_at IS NULL GROUP BY tournament_id, user_id) b ON b.tournament_id = w.tournament_id AND b.user_id = w.user_id
+		WHERE w.deleted_at IS NULL GROUP BY w.tournament_id) w ON w.tournament_id = t.id
+	LEFT JOIN (SELECT tournament_id, SUM(sum) AS sum FROM user_points_operations
+		WHERE deleted_at IS NULL AND kind = '%s' GROUP BY tournament_id) pr ON pr.tournament_id = t.id
+	WHERE t.deleted_at IS NULL AND NOT t.legacy_ledger) AS escrow
+	WHERE deposits <> debited + held OR prizes <> credited
+	ORDER BY tournament_id`
+
+const ORPHAN_ROWS_QUERY = `SELECT '%s' AS kind, 'user_points_operations' AS "table", o.id FROM user_points_operations o
+	WHERE o.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM user_points_balances b
+		WHERE b.user_id = o.user_id AND b.currency = o.currency AND b.deleted_at IS NULL)
+	UNION ALL
+	SELECT '%s', 'user_points_operations', o.id FROM user_points_operations o
+	WHERE o.deleted_at IS NULL AND COALESCE(o.tournament_id, 0) > 0
+	AND NOT EXISTS (SELECT 1 FROM tournaments t WHERE t.id = o.tournament_id AND t.deleted_at IS NULL)
+	UNION ALL
+	SELECT '%s', 'tournament_players', p.id FROM tournament_players p
+	WHERE p.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM tournaments t WHERE t.id = p.tournament_id AND t.deleted_at IS NULL)
+	UNION ALL
+	SELECT '%s', 'tournament_backers', b.id FROM tournament_backers b
+	WHERE b.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM tournament_players p
+		WHERE p.tournament_id = b.tournament_id AND p.user_id = b.user_id AND p.deleted_at IS NULL)
+	UNION ALL
+	SELECT '%s', 'tournament_winners', w.id FROM tournament_winners w
+	WHERE w.deleted_at IS NULL AND w.team_id = 0 AND NOT EXISTS (SELECT 1 FROM tournament_players p
+		WHERE p.tournament_id = w.tournament_id AND p.user_id = w.user_id AND p.deleted_at IS NULL)
+	UNION ALL
+	SELECT '%s', 'tournament_winners', w.id FROM tournament_winners w
+	WHERE w.deleted_at IS NULL AND w.team_id <> 0 AND NOT EXISTS (SELECT 1 FROM tournament_team_members m
+		WHERE m.tournament_id = w.tournament_id AND m.team_id = w.team_id AND m.user_id = w.user_id)
+	ORDER BY kind, id`
+
+//Recomputes every user wallet balance from operations ledger && held points from holds,
+//checks tournaments' deposits && prizes against ledger and looks for orphan rows.
+//If fix is true writes "correction" operation making ledger sum of every drifted balance equal it
+//&& resets held points to sum of holds recording a types.BalanceCorrection for each one
+func (s *Storage) Reconcile(fix bool) (interface{}, error) {
+	var err error
+	report := &types.ReconciliationReport{
+		CheckedAt:        time.Now(),
+		Discrepancies:    []*types.BalanceDiscrepancy{},
+		EscrowMismatches: []*types.EscrowMismatch{},
+		Orphans:          []*types.OrphanRow{},
+		Corrections:      []*types.BalanceCorrection{},
+	}
+	if err = s.db.Model(&types.UserPointsBalance{}).Count(&report.BalancesChecked).Error; err != nil {
+		return nil, err
+	}
+	if err = s.db.Raw(fmt.Sprintf(BALANCE_DISCREPANCIES_QUERY,
+		fmt.Sprintf(LEDGER_SUM_QUERY, USER_POINTS_OPERATION_DEBT),
+		fmt.Sprintf(HELD_SUM_QUERY, types.HOLD_STATE_HELD))).Scan(&report.Discrepancies).Error; err != nil {
+		return nil, err
+	}
+	if err = s.db.Raw(fmt.Sprintf(ESCROW_MISMATCHES_QUERY,
+		types.TOURNAMENT_STATE_CANCELLED,
+		types.OPERATION_KIND_JOIN, types.OPERATION_KIND_REFUND, types.HOLD_STATE_HELD,
+		types.OPERATION_KIND_PRIZE)).Scan(&report.EscrowMismatches).Error; err != nil {
+		return nil, err
+	}
+	if err = s.db.Raw(fmt.Sprintf(ORPHAN_ROWS_QUERY,
+		types.ORPHAN_OPERATION_WITHOUT_BALANCE,
+		types.ORPHAN_OPERATION_WITHOUT_TOURNAMENT,
+		types.ORPHAN_PLAYER_WITHOUT_TOURNAMENT,
+		types.ORPHAN_BACKER_WITHOUT_PLAYER,
+		types.ORPHAN_WINNER_WITHOUT_PLAYER,
+		types.ORPHAN_WINNER_WITHOUT_PLAYER)).Scan(&report.Orphans).Error; err != nil {
+		return nil, err
+	}
+	if fix {
+		for _, discrepancy := range report.Discrepancies {
+			correction, err := s.correctBalance(discrepancy.UserId, discrepancy.Currency)
+			if err != nil {
+				return nil, err
+			}
+			if correction != nil {
+				report.Corrections = append(report.Corrections, correction)
+			}
+		}
+	}
+	return report, nil
+}
+
+//Writes "correction" operation of difference between user wallet balance && its ledger sum,
+//resets held points to sum of holds, under balance row lock, so operations committed
+//since discrepancy was found are taken into account.
+//Returns nil correction if balance is consistent by now
+func (s *Storage) correctBalance(userId uint, currency string) (correction *types.BalanceCorrection, err error) {
+	var ledger struct {
+		Expected     int
+		ExpectedHeld int
+	}
+	balance := &types.UserPointsBalance{}
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").Where(&types.UserPointsBalance{UserId: userId, Currency: currency}).First(balance).Error; err != nil {
+		return nil, err
+	}
+	if err = tx.Raw(`SELECT
+		(SELECT COALESCE(SUM(CASE WHEN operation_type = ? THEN sum ELSE -sum END), 0)
+			FROM user_points_operations WHERE user_id = ? AND currency = ? AND deleted_at IS NULL) AS expected,
+		(SELECT COALESCE(SUM(amount), 0) FROM balance_holds WHERE user_id = ? AND currency = ? AND state = ?) AS expected_held`,
+		USER_POINTS_OPERATION_DEBT, userId, currency, userId, currency, types.HOLD_STATE_HELD).Scan(&ledger).Error; err != nil {
+		return nil, err
+	}
+	if ledger.Expected == balance.Balance && ledger.ExpectedHeld == balance.Held {
+		err = tx.Commit().Error
+		return nil, err
+	}
+	correction = &types.BalanceCorrection{
+		UserId:      userId,
+		Currency:    currency,
+		Balance:     balance.Balance,
+		OldExpected: ledger.Expected,
+		Amount:      balance.Balance - ledger.Expected,
+		OldHeld:     balance.Held,
+		NewHeld:     ledger.ExpectedHeld,
+	}
+	if correction.Amount != 0 {
+		operation := &UserPointsOperations{
+			UserId:        userId,
+			OperationType: USER_POINTS_OPERATION_DEBT,
+			Kind:          types.OPERATION_KIND_CORRECTION,
+			Currency:      currency,
+			Sum:           correction.Amount,
+		}
+		if correction.Amount < 0 {
+			operation.OperationType, operation.Sum = USER_POINTS_OPERATION_CREDIT, -correction.Amount
+		}
+		if err = tx.Create(operation).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Model(balance).UpdateColumn("held", ledger.ExpectedHeld).Error; err != nil {
+		return nil, err
+	}
+	if err = tx.Create(correction).Error; err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return correction, nil
+}
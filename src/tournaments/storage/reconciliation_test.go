@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+func reconcile(t *testing.T, s *Storage, fix bool) *types.ReconciliationReport {
+	t.Helper()
+	report, err := s.Reconcile(fix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report.(*types.ReconciliationReport)
+}
+
+func TestReconcileCorrectsDriftedBalance(t *testing.T) {
+	s := newTestStorage(t, nil)
+	mustTopUp(t, s, 1, 100)
+	mustTopUp(t, s, 2, 100)
+	tournament := mustAnnounce(t, s, &types.AnnounceTournamentRequest{Deposit: 30})
+	mustJoin(t, s, &types.JoinTournamentRequest{TournamentId: tournament.ID, PlayerId: 1, BackerIds: []uint{2}})
+	if err := s.CloseTournamentRegistration(tournament.ID); err != nil {
+		t.Fatal(err)
+	}
+	if report := reconcile(t, s, false); !report.Consistent() || len(report.Discrepancies) > 0 {
+		t.Fatalf("got inconsistent ledger %+v", report)
+	}
+
+	// Balance drifts from its operations by a write bypassing the ledger
+	if err := s.db.Exec(`UPDATE user_points_balances SET balance = balance + 7 WHERE user_id = 1`).Error; err != nil {
+		t.Fatal(err)
+	}
+	report := reconcile(t, s, false)
+	if len(report.Discrepancies) != 1 || report.Discrepancies[0].Balance != 92 || report.Discrepancies[0].Expected != 85 {
+		t.Fatalf("got discrepancies %+v, want balance 92 of expected 85", report.Discrepancies)
+	}
+	if len(report.Corrections) > 0 {
+		t.Errorf("got corrections %+v without fix", report.Corrections)
+	}
+
+	report = reconcile(t, s, true)
+	if len(report.Corrections) != 1 || report.Corrections[0].Amount != 7 {
+		t.Fatalf("got corrections %+v, want one of 7", report.Corrections)
+	}
+	// Correction makes the ledger match the balance, the balance itself stays
+	if balance := balanceOf(t, s, 1); balance.Balance != 92 {
+		t.Errorf("got balance %d, want 92", balance.Balance)
+	}
+	operation := &UserPointsOperations{}
+	if err := s.db.Where(&UserPointsOperations{UserId: 1, Kind: types.OPERATION_KIND_CORRECTION}).First(operation).Error; err != nil {
+		t.Fatal(err)
+	}
+	if operation.OperationType != USER_POINTS_OPERATION_DEBT || operation.Sum != 7 {
+		t.Errorf("got correction operation of type %d and sum %d, want credit of 7", operation.OperationType, operation.Sum)
+	}
+	if report := reconcile(t, s, false); len(report.Discrepancies) > 0 {
+		t.Errorf("got discrepancies %+v after fix", report.Discrepancies)
+	}
+}
+
+func TestReconcileFindsEscrowMismatch(t *testing.T) {
+	s := newTestStorage(t, nil)
+	mustTopUp(t, s, 1, 100)
+	tournament := mustAnnounce(t, s, &types.AnnounceTournamentRequest{Deposit: 40})
+	mustJoin(t, s, &types.JoinTournamentRequest{TournamentId: tournament.ID, PlayerId: 1})
+	if err := s.db.Exec(`UPDATE tournament_players SET user_deposit = 50`).Error; err != nil {
+		t.Fatal(err)
+	}
+	report := reconcile(t, s, true)
+	if len(report.EscrowMismatches) != 1 || report.EscrowMismatches[0].Deposits != 50 || report.EscrowMismatches[0].Held != 40 {
+		t.Fatalf("got escrow mismatches %+v, want deposits 50 of held 40", report.EscrowMismatches)
+	}
+	if report.Consistent() {
+		t.Error("escrow mismatch is never fixed, got consistent report")
+	}
+}
+
+func TestTagLegacyOperations(t *testing.T) {
+	s := newTestStorage(t, nil)
+	played := mustAnnounce(t, s, &types.AnnounceTournamentRequest{Deposit: 10})
+	empty := mustAnnounce(t, s, &types.AnnounceTournamentRequest{Deposit: 10})
+	// Entry debited before operations recorded kinds && tournaments
+	if err := s.db.Create(&UserPointsOperations{UserId: 1, OperationType: USER_POINTS_OPERATION_CREDIT, Currency: types.CURRENCY_POINTS, Sum: 10}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := s.db.Create(&types.TournamentPlayer{TournamentId: played.ID, UserId: 1, UserDeposit: 10}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := s.tagLegacyOperations(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		tournament *types.Tournament
+		legacy     bool
+	}{{played, true}, {empty, false}} {
+		tournament := &types.Tournament{}
+		if err := s.db.First(tournament, c.tournament.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if tournament.LegacyLedger != c.legacy {
+			t.Errorf("tournament %d got legacy ledger %v, want %v", tournament.ID, tournament.LegacyLedger, c.legacy)
+		}
+	}
+	untagged := 0
+	s.db.Model(&UserPointsOperations{}).Where("COALESCE(kind, '') = ''").Count(&untagged)
+	if untagged > 0 {
+		t.Errorf("got %d untagged operations", untagged)
+	}
+}
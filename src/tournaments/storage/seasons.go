@@ -0,0 +1,316 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Tournament winners along with prizes of their entries: members of winning team share the whole team prize
+const WINNER_ENTRY_PRIZES_QUERY = `SELECT w.*,
+		SUM(w.prize) OVER (PARTITION BY w.tournament_id, CASE WHEN w.team_id = 0 THEN -w.id ELSE w.team_id END) AS entry_prize
+	FROM tournament_winners w
+	WHERE w.deleted_at IS NULL`
+
+// Places players took in tournaments: standings of completed brackets,
+// entries of tournaments resulted by a winners list ranked by entry prize, satellite ticket winners first,
+// members of a team take the place of their entry
+const TOURNAMENT_PLACES_QUERY = `SELECT tournament_id, user_id, place FROM tournament_standings WHERE place > 0
+	UNION ALL
+	SELECT w.tournament_id, w.user_id, e.place
+	FROM tournament_winners w
+	JOIN (SELECT tournament_id, entry_id, RANK() OVER (PARTITION BY tournament_id ORDER BY ticket DESC, entry_prize DESC) AS place
+		FROM (SELECT tournament_id, CASE WHEN team_id = 0 THEN -id ELSE team_id END AS entry_id,
+			MAX(CASE WHEN ticket_id <> 0 THEN 1 ELSE 0 END) AS ticket, SUM(prize) AS entry_prize
+			FROM tournament_winners
+			WHERE deleted_at IS NULL
+			GROUP BY tournament_id, CASE WHEN team_id = 0 THEN -id ELSE team_id END) entries) e
+		ON e.tournament_id = w.tournament_id AND e.entry_id = CASE WHEN w.team_id = 0 THEN -w.id ELSE w.team_id END
+	WHERE w.deleted_at IS NULL
+	AND NOT EXISTS (SELECT 1 FROM tournament_standings s WHERE s.tournament_id = w.tournament_id AND s.place > 0)`
+
+//Creates season with points of tournament places && rewards of season places
+func (s *Storage) CreateSeason(request *types.SeasonRequest) (result interface{}, err error) {
+	if len(request.Rewards) > 0 && s.transfersConf.HouseUserId == 0 {
+		return nil, errors.New(`Season rewards require house account!`)
+	}
+	season := &types.Season{
+		Name:        request.Name,
+		GameId:      request.GameId,
+		StartsAt:    request.StartsAt,
+		EndsAt:      request.EndsAt,
+		Currency:    types.CurrencyOrDefault(request.Currency),
+		PlacePoints: make([]*types.SeasonPlacePoints, 0, len(request.PlacePoints)),
+		Rewards:     make([]*types.SeasonReward, 0, len(request.Rewards)),
+	}
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	if err = tx.Create(season).Error; err != nil {
+		return nil, err
+	}
+	for i, points := range request.PlacePoints {
+		placePoints := &types.SeasonPlacePoints{SeasonId: season.ID, Place: i + 1, Points: points}
+		if err = tx.Create(placePoints).Error; err != nil {
+			return nil, err
+		}
+		season.PlacePoints = append(season.PlacePoints, placePoints)
+	}
+	for i, amount := range request.Rewards {
+		reward := &types.SeasonReward{SeasonId: season.ID, Place: i + 1, Amount: amount}
+		if err = tx.Create(reward).Error; err != nil {
+			return nil, err
+		}
+		season.Rewards = append(season.Rewards, reward)
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+//Fetches season with its place points && rewards
+func (s *Storage) FetchSeason(id uint) (interface{}, error) {
+	season := &types.Season{}
+	if err := s.db.First(season, id).Error; err != nil {
+		return nil, err
+	}
+	if err := s.fetchSeasonPlaces(s.db, season); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+//Fetches page of seasons, latest starting first
+func (s *Storage) FetchSeasons(query *types.SeasonsQuery) (interface{}, error) {
+	var (
+		seasons []*types.Season
+		total   int
+		err     error
+	)
+	db := s.db.Model(&types.Season{}).Where(&types.Season{GameId: query.GameId})
+	if err = db.Count(&total).Error; err != nil {
+		return nil, errors.New("An error occured during seasons counting")
+	}
+	seasons = []*types.Season{}
+	if err = db.Order("starts_at DESC, id DESC").Limit(query.Limit).Offset(query.Offset).Find(&seasons).Error; err != nil {
+		return nil, errors.New("An error occured during seasons fetching")
+	}
+	for _, season := range seasons {
+		if err = s.fetchSeasonPlaces(s.db, season); err != nil {
+			return nil, errors.New("An error occured during seasons fetching")
+		}
+	}
+	return &types.SeasonsPage{
+		Seasons: seasons,
+		Total:   total,
+	}, nil
+}
+
+func (s *Storage) fetchSeasonPlaces(db *gorm.DB, season *types.Season) error {
+	season.PlacePoints = []*types.SeasonPlacePoints{}
+	if err := db.Where(&types.SeasonPlacePoints{SeasonId: season.ID}).Order("place").Find(&season.PlacePoints).Error; err != nil {
+		return err
+	}
+	season.Rewards = []*types.SeasonReward{}
+	return db.Where(&types.SeasonReward{SeasonId: season.ID}).Order("place").Find(&season.Rewards).Error
+}
+
+//Fetches page of season standings, best first, with rewards paid if season is over
+func (s *Storage) FetchSeasonStandings(query *types.SeasonStandingsQuery) (interface{}, error) {
+	season := &types.Season{}
+	if err := s.db.First(season, query.SeasonId).Error; err != nil {
+		return nil, err
+	}
+	standings, err := s.seasonStandings(s.db, season)
+	if err != nil {
+		return nil, errors.New("An error occured during season standings computing")
+	}
+	if season.PaidAt != nil {
+		payouts := []*types.SeasonPayout{}
+		if err = s.db.Where(&types.SeasonPayout{SeasonId: season.ID}).Find(&payouts).Error; err != nil {
+			return nil, errors.New("An error occured during season payouts fetching")
+		}
+		rewards := make(map[uint]int, len(payouts))
+		for _, payout := range payouts {
+			rewards[payout.UserId] = payout.Amount
+		}
+		for _, standing := range standings {
+			standing.Reward = rewards[standing.UserId]
+		}
+	}
+
+	page := &types.SeasonStandingsPage{
+		Standings: []*types.SeasonStanding{},
+		Total:     len(standings),
+	}
+	if query.Offset < len(standings) {
+		end := query.Offset + query.Limit
+		if end > len(standings) {
+			end = len(standings)
+		}
+		page.Standings = standings[query.Offset:end]
+	}
+	return page, nil
+}
+
+//Sums season points of places players took in finished tournaments of season game dated within the season
+func (s *Storage) seasonStandings(db *gorm.DB, season *types.Season) ([]*types.SeasonStanding, error) {
+	standings := []*types.SeasonStanding{}
+	if err := db.Table("tournaments t").
+		Joins("JOIN ("+TOURNAMENT_PLACES_QUERY+") p ON p.tournament_id = t.id").
+		Joins("JOIN season_place_points sp ON sp.season_id = ? AND sp.place = p.place", season.ID).
+		Where("t.deleted_at IS NULL AND t.state = ? AND t.game_id = ?", types.TOURNAMENT_STATE_FINISHED, season.GameId).
+		Where("t.date >= ? AND t.date < ? AND sp.points > 0", season.StartsAt, season.EndsAt).
+		Group("p.user_id").
+		Select("p.user_id, SUM(sp.points) AS points, COUNT(*) AS tournaments").
+		Scan(&standings).Error; err != nil {
+		return nil, err
+	}
+	rankSeasonStandings(standings)
+	return standings, nil
+}
+
+//Orders standings by points, best first, ties by user ID, ranking tied players equally
+func rankSeasonStandings(standings []*types.SeasonStanding) {
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		return standings[i].UserId < standings[j].UserId
+	})
+	for i, standing := range standings {
+		standing.Rank = i + 1
+		if i > 0 && standing.Points == standings[i-1].Points {
+			standing.Rank = standings[i-1].Rank
+		}
+	}
+}
+
+//Returns rewards of ranked standings by user, players sharing a rank split rewards of places they take evenly,
+//indivisible remainder goes to the first of them one by one
+func seasonRewards(standings []*types.SeasonStanding, rewards []*types.SeasonReward) map[uint]int {
+	byPlace := make(map[int]int, len(rewards))
+	for _, reward := range rewards {
+		byPlace[reward.Place] = reward.Amount
+	}
+	result := map[uint]int{}
+	for first := 0; first < len(standings); {
+		last := first
+		for last+1 < len(standings) && standings[last+1].Rank == standings[first].Rank {
+			last++
+		}
+		sum := 0
+		for place := first + 1; place <= last+1; place++ {
+			sum += byPlace[place]
+		}
+		tied := last - first + 1
+		for i := first; i <= last; i++ {
+			amount := sum / tied
+			if i-first < sum%tied {
+				amount++
+			}
+			if amount > 0 {
+				result[standings[i].UserId] = amount
+			}
+		}
+		first = last + 1
+	}
+	return result
+}
+
+//Pays rewards of every season ended by now, which tournaments are all finished or cancelled,
+//returns number of seasons paid
+func (s *Storage) PaySeasonRewards(now time.Time) (int, error) {
+	var ids []uint
+	if err := s.db.Model(&types.Season{}).Where("ends_at <= ? AND paid_at IS NULL", now).Order("ends_at, id").Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	paid := 0
+	for _, id := range ids {
+		ok, err := s.paySeason(id, now)
+		if err != nil {
+			s.logger.Printf("Could not pay rewards of season %d: %s", id, err.Error())
+			continue
+		}
+		if ok {
+			paid++
+		}
+	}
+	return paid, nil
+}
+
+//Credits rewards of season places to players by "season_reward" operations debited from the house,
+//notifying them; returns false if season is paid already or some of its tournaments are still open
+func (s *Storage) paySeason(id uint, now time.Time) (paid bool, err error) {
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	season := &types.Season{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(season, id).Error; err != nil {
+		return false, err
+	}
+	var open int
+	if err = tx.Model(&types.Tournament{}).
+		Where("state = ? AND game_id = ? AND date >= ? AND date < ?", types.TOURNAMENT_STATE_OPEN, season.GameId, season.StartsAt, season.EndsAt).
+		Count(&open).Error; err != nil {
+		return false, err
+	}
+	if season.PaidAt != nil || open > 0 {
+		return false, tx.Commit().Error
+	}
+	if err = s.fetchSeasonPlaces(tx, season); err != nil {
+		return false, err
+	}
+	var standings []*types.SeasonStanding
+	if standings, err = s.seasonStandings(tx, season); err != nil {
+		return false, err
+	}
+	rewards := seasonRewards(standings, season.Rewards)
+	for _, standing := range standings {
+		amount := rewards[standing.UserId]
+		if amount == 0 {
+			continue
+		}
+		var operation *UserPointsOperations
+		if _, operation, err = s.creditWallet(tx, standing.UserId, season.Currency, amount, types.OPERATION_KIND_SEASON_REWARD, nil); err != nil {
+			return false, err
+		}
+		if err = s.debitHouse(tx, season.Currency, types.OPERATION_KIND_SEASON_REWARD, 0, amount); err != nil {
+			return false, err
+		}
+		if err = tx.Create(
+			&types.SeasonPayout{
+				SeasonId:    season.ID,
+				UserId:      standing.UserId,
+				Rank:        standing.Rank,
+				Amount:      amount,
+				OperationId: operation.ID,
+			}).Error; err != nil {
+			return false, err
+		}
+		if err = tx.Create(
+			&types.Notification{
+				UserId:   standing.UserId,
+				Kind:     types.NOTIFICATION_KIND_SEASON_REWARD,
+				Currency: season.Currency,
+				Amount:   amount,
+				Message:  fmt.Sprintf("%d %s rewarded for rank %d in season %s", amount, season.Currency, standing.Rank, season.Name),
+			}).Error; err != nil {
+			return false, err
+		}
+	}
+	if err = tx.Model(season).UpdateColumn("paid_at", now).Error; err != nil {
+		return false, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
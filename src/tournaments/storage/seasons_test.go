@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+func TestSeasonRewards(t *testing.T) {
+	rewards := []*types.SeasonReward{{Place: 1, Amount: 100}, {Place: 2, Amount: 50}, {Place: 3, Amount: 20}}
+	standing := func(rank int, userId uint) *types.SeasonStanding {
+		return &types.SeasonStanding{Rank: rank, UserId: userId}
+	}
+	cases := []struct {
+		name      string
+		standings []*types.SeasonStanding
+		rewards   []*types.SeasonReward
+		result    map[uint]int
+	}{
+		{
+			name:      "no standings",
+			standings: []*types.SeasonStanding{},
+			rewards:   rewards,
+			result:    map[uint]int{},
+		},
+		{
+			name:      "rewards by place",
+			standings: []*types.SeasonStanding{standing(1, 5), standing(2, 3), standing(3, 9), standing(4, 1)},
+			rewards:   rewards,
+			result:    map[uint]int{5: 100, 3: 50, 9: 20},
+		},
+		{
+			name:      "tied players split rewards of places they take",
+			standings: []*types.SeasonStanding{standing(1, 5), standing(2, 3), standing(2, 9), standing(4, 1)},
+			rewards:   rewards,
+			result:    map[uint]int{5: 100, 3: 35, 9: 35},
+		},
+		{
+			name:      "indivisible remainder goes to the first tied players",
+			standings: []*types.SeasonStanding{standing(1, 5), standing(1, 3), standing(1, 9)},
+			rewards:   rewards,
+			result:    map[uint]int{5: 57, 3: 57, 9: 56},
+		},
+		{
+			name:      "tie beyond rewarded places",
+			standings: []*types.SeasonStanding{standing(1, 5), standing(2, 3), standing(3, 9), standing(3, 1), standing(3, 2)},
+			rewards:   rewards,
+			result:    map[uint]int{5: 100, 3: 50, 9: 7, 1: 7, 2: 6},
+		},
+		{
+			name:      "unrewarded place in between",
+			standings: []*types.SeasonStanding{standing(1, 5), standing(2, 3), standing(3, 9)},
+			rewards:   []*types.SeasonReward{{Place: 1, Amount: 100}, {Place: 3, Amount: 10}},
+			result:    map[uint]int{5: 100, 9: 10},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if result := seasonRewards(c.standings, c.rewards); !reflect.DeepEqual(result, c.result) {
+				t.Errorf("got %v, want %v", result, c.result)
+			}
+		})
+	}
+}
+
+func TestTournamentPlaces(t *testing.T) {
+	winner := func(userId uint, prize int, teamId uint) *types.TournamentWinner {
+		return &types.TournamentWinner{TournamentId: 1, UserId: userId, Prize: prize, TeamId: teamId}
+	}
+	cases := []struct {
+		name      string
+		winners   []*types.TournamentWinner
+		standings []*types.TournamentStanding
+		places    map[uint]int
+	}{
+		{
+			name:    "entries ranked by prize",
+			winners: []*types.TournamentWinner{winner(1, 50, 0), winner(2, 100, 0), winner(3, 50, 0), winner(4, 10, 0)},
+			places:  map[uint]int{2: 1, 1: 2, 3: 2, 4: 4},
+		},
+		{
+			name: "ticket winners first",
+			winners: []*types.TournamentWinner{winner(1, 100, 0),
+				{TournamentId: 1, UserId: 2, TicketId: 7}},
+			places: map[uint]int{2: 1, 1: 2},
+		},
+		{
+			name: "team members take the place of their team",
+			winners: []*types.TournamentWinner{
+				winner(1, 40, 10), winner(2, 30, 10), winner(3, 30, 10),
+				winner(4, 20, 20), winner(5, 20, 20), winner(6, 20, 20),
+			},
+			places: map[uint]int{1: 1, 2: 1, 3: 1, 4: 2, 5: 2, 6: 2},
+		},
+		{
+			name: "team ranked by its whole prize",
+			winners: []*types.TournamentWinner{
+				winner(1, 40, 10), winner(2, 40, 10),
+				winner(3, 50, 20), winner(4, 0, 20),
+			},
+			places: map[uint]int{1: 1, 2: 1, 3: 2, 4: 2},
+		},
+		{
+			name:      "bracket standings win over winners list",
+			winners:   []*types.TournamentWinner{winner(1, 100, 0), winner(2, 50, 0)},
+			standings: []*types.TournamentStanding{{TournamentId: 1, UserId: 2, Place: 1}, {TournamentId: 1, UserId: 1, Place: 2}},
+			places:    map[uint]int{2: 1, 1: 2},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := newTestStorage(t, nil)
+			for _, row := range c.winners {
+				if err := s.db.Create(row).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, row := range c.standings {
+				if err := s.db.Create(row).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+			var rows []*struct {
+				UserId uint
+				Place  int
+			}
+			if err := s.db.Raw(TOURNAMENT_PLACES_QUERY).Scan(&rows).Error; err != nil {
+				t.Fatal(err)
+			}
+			places := map[uint]int{}
+			for _, row := range rows {
+				places[row.UserId] = row.Place
+			}
+			if !reflect.DeepEqual(places, c.places) {
+				t.Errorf("got places %v, want %v", places, c.places)
+			}
+		})
+	}
+}
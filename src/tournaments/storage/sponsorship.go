@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Debits prize pool of sponsored tournament from sponsor's wallet of tournament currency
+//spending points expiring soonest first
+func (s *Storage) fundPrizePool(tx *gorm.DB, tournament *types.Tournament) error {
+	balance := &types.UserPointsBalance{}
+	found := tx.Set("gorm:query_option", "FOR UPDATE").Where(&types.UserPointsBalance{UserId: tournament.SponsorId, Currency: tournament.Currency}).First(balance)
+	if found.RecordNotFound() {
+		return errors.New("Sponsor has no balance in tournament currency!")
+	}
+	if found.Error != nil {
+		return found.Error
+	}
+	if balance.Available < tournament.PrizePool {
+		return errors.New("Not enough points in sponsor balance!")
+	}
+	if err := tx.Create(
+		&UserPointsOperations{
+			UserId:        tournament.SponsorId,
+			OperationType: USER_POINTS_OPERATION_CREDIT,
+			Kind:          types.OPERATION_KIND_SPONSOR,
+			TournamentId:  tournament.ID,
+			Currency:      tournament.Currency,
+			Sum:           tournament.PrizePool,
+		}).Error; err != nil {
+		return err
+	}
+	if err := s.consumeLots(tx, tournament.SponsorId, tournament.Currency, tournament.PrizePool, 0); err != nil {
+		return err
+	}
+	return tx.Model(balance).UpdateColumn(`balance`, gorm.Expr(`balance - ?`, tournament.PrizePool)).Error
+}
+
+//Returns points of prize pool not paid as prizes to sponsor's wallet by "sponsor_return" operation,
+//tournament must be locked
+func (s *Storage) returnPrizePool(tx *gorm.DB, tournament *types.Tournament, points int) error {
+	if err := tx.Create(
+		&UserPointsOperations{
+			UserId:        tournament.SponsorId,
+			OperationType: USER_POINTS_OPERATION_DEBT,
+			Kind:          types.OPERATION_KIND_SPONSOR_RETURN,
+			TournamentId:  tournament.ID,
+			Currency:      tournament.Currency,
+			Sum:           points,
+		}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&types.UserPointsBalance{}).Where(&types.UserPointsBalance{UserId: tournament.SponsorId, Currency: tournament.Currency}).
+		UpdateColumn(`balance`, gorm.Expr(`balance + ?`, points)).Error; err != nil {
+		return err
+	}
+	tournament.PrizePoolReturned += points
+	return tx.Model(tournament).UpdateColumn("prize_pool_returned", gorm.Expr(`prize_pool_returned + ?`, points)).Error
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// User wallet operations with running balance computed over the whole wallet's ledger,
+// so it stays correct whatever filters are applied to the outer query
+const STATEMENT_QUERY = `SELECT * FROM (SELECT id, created_at, COALESCE(kind, '') AS kind, COALESCE(tournament_id, 0) AS tournament_id,
+	CASE WHEN operation_type = ? THEN sum ELSE -sum END AS amount,
+	SUM(CASE WHEN operation_type = ? THEN sum ELSE -sum END) OVER (ORDER BY id) AS running_balance
+	FROM user_points_operations
+	WHERE user_id = ? AND currency = ? AND deleted_at IS NULL) AS statement`
+
+//Fetches page of user balance operations matching query filters,
+//every entry carries balance right after the operation
+func (s *Storage) FetchStatement(query *types.StatementQuery) (interface{}, error) {
+	var (
+		entries []*types.StatementEntry
+		counted struct {
+			Total int
+		}
+		err error
+	)
+	statementQuery, args := statement(query)
+	if err = s.db.Raw(`SELECT COUNT(*) AS total FROM (`+statementQuery+`) AS filtered`, args...).Scan(&counted).Error; err != nil {
+		return nil, errors.New("An error occured during statement counting")
+	}
+	order := " ORDER BY id DESC"
+	if query.Ascending {
+		order = " ORDER BY id ASC"
+	}
+	entries = []*types.StatementEntry{}
+	if err = s.db.Raw(statementQuery+order+" LIMIT ? OFFSET ?", append(args, query.Limit, query.Offset)...).Scan(&entries).Error; err != nil {
+		return nil, errors.New("An error occured during statement fetching")
+	}
+	return &types.StatementPage{
+		Entries: entries,
+		Total:   counted.Total,
+	}, nil
+}
+
+//Returns statement query with conditions of query filters && its bind params
+func statement(query *types.StatementQuery) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{USER_POINTS_OPERATION_DEBT, USER_POINTS_OPERATION_DEBT, query.UserId, types.CurrencyOrDefault(query.Currency)}
+	if len(query.Kinds) > 0 {
+		conditions = append(conditions, "kind IN (?)")
+		args = append(args, query.Kinds)
+	}
+	if !query.DateFrom.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.DateFrom)
+	}
+	if !query.DateTo.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, query.DateTo)
+	}
+	if query.TournamentId > 0 {
+		conditions = append(conditions, "tournament_id = ?")
+		args = append(args, query.TournamentId)
+	}
+	if query.AfterId > 0 {
+		if query.Ascending {
+			conditions = append(conditions, "id > ?")
+		} else {
+			conditions = append(conditions, "id < ?")
+		}
+		args = append(args, query.AfterId)
+	}
+	if len(conditions) == 0 {
+		return STATEMENT_QUERY, args
+	}
+	return STATEMENT_QUERY + " WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+func TestStatementConditions(t *testing.T) {
+	date := time.Date(2018, 3, 10, 0, 0, 0, 0, time.UTC)
+	common := []interface{}{USER_POINTS_OPERATION_DEBT, USER_POINTS_OPERATION_DEBT, uint(1)}
+	cases := []struct {
+		name       string
+		query      *types.StatementQuery
+		conditions string
+		args       []interface{}
+	}{
+		{
+			name:  "default currency",
+			query: &types.StatementQuery{UserId: 1},
+			args:  append(common, types.CURRENCY_POINTS),
+		},
+		{
+			name:       "every filter",
+			query:      &types.StatementQuery{UserId: 1, Currency: types.CURRENCY_BONUS, Kinds: []string{"fund", "take"}, DateFrom: date, DateTo: date.AddDate(0, 1, 0), TournamentId: 3},
+			conditions: " WHERE kind IN (?) AND created_at >= ? AND created_at < ? AND tournament_id = ?",
+			args:       append(common, types.CURRENCY_BONUS, []string{"fund", "take"}, date, date.AddDate(0, 1, 0), uint(3)),
+		},
+		{
+			name:       "page after operation, newest first",
+			query:      &types.StatementQuery{UserId: 1, AfterId: 100},
+			conditions: " WHERE id < ?",
+			args:       append(common, types.CURRENCY_POINTS, uint(100)),
+		},
+		{
+			name:       "page after operation, oldest first",
+			query:      &types.StatementQuery{UserId: 1, AfterId: 100, Ascending: true},
+			conditions: " WHERE id > ?",
+			args:       append(common, types.CURRENCY_POINTS, uint(100)),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			query, args := statement(c.query)
+			if query != STATEMENT_QUERY+c.conditions {
+				t.Errorf("got conditions %q, want %q", query[len(STATEMENT_QUERY):], c.conditions)
+			}
+			if !reflect.DeepEqual(args, c.args) {
+				t.Errorf("got args %v, want %v", args, c.args)
+			}
+		})
+	}
+}
@@ -23,12 +23,30 @@ type DsnColfig struct {
 	DbName string
 }
 
+// Creates wallet unless there is one, wallets are unique by user && currency
+const ENSURE_WALLET_QUERY = `INSERT INTO user_points_balances (created_at, updated_at, user_id, currency, balance, held)
+	VALUES (NOW(), NOW(), ?, ?, 0, 0)
+	ON CONFLICT (user_id, currency) DO NOTHING`
+
+// Adds balances && held points of duplicate wallets to the oldest wallet of user && currency
+const MERGE_DUPLICATE_WALLETS_QUERY = `UPDATE user_points_balances b SET balance = d.balance, held = d.held, deleted_at = NULL
+	FROM (SELECT MIN(id) AS id,
+		SUM(CASE WHEN deleted_at IS NULL THEN balance ELSE 0 END) AS balance,
+		SUM(CASE WHEN deleted_at IS NULL THEN held ELSE 0 END) AS held
+		FROM user_points_balances GROUP BY user_id, currency HAVING COUNT(*) > 1) d
+	WHERE b.id = d.id`
+
+const DELETE_DUPLICATE_WALLETS_QUERY = `DELETE FROM user_points_balances b
+	WHERE EXISTS (SELECT 1 FROM user_points_balances o WHERE o.user_id = b.user_id AND o.currency = b.currency AND o.id < b.id)`
+
 type Storage struct {
-	db     *gorm.DB
-	logger *log.Logger
+	db            *gorm.DB
+	transfersConf *TransfersConf
+	logger        *log.Logger
+	leaderboards  *leaderboardCache
 }
 
-func NewStorage(conf *DsnColfig, logger *log.Logger) (interface{}, error) {
+func NewStorage(conf *DsnColfig, transfersConf *TransfersConf, logger *log.Logger) (interface{}, error) {
 	var (
 		db  *gorm.DB
 		err error
@@ -50,8 +68,10 @@ func NewStorage(conf *DsnColfig, logger *log.Logger) (interface{}, error) {
 		time.Sleep(CONNECTION_ATTEMPTS_INTERVAL_SECONDS * time.Second)
 	}
 	s := &Storage{
-		db:     db,
-		logger: logger,
+		db:            db,
+		transfersConf: transfersConf,
+		logger:        logger,
+		leaderboards:  newLeaderboardCache(),
 	}
 	s.autoMigrate()
 	return s, nil
@@ -65,16 +85,109 @@ func (s *Storage) Close() error {
 }
 
 func (s *Storage) autoMigrate() {
+	// Wallets are unique per user && currency since multi-currency wallets were introduced
+	if s.db.HasTable(&types.UserPointsBalance{}) && !s.db.Dialect().HasIndex("user_points_balances", "idx_user_points_balances_user_id_currency") {
+		if err := s.mergeDuplicateWallets(); err != nil {
+			s.logger.Printf("Could not merge duplicate wallets: %s", err.Error())
+		}
+	}
 	s.db.AutoMigrate(
 		&User{},
 		&UserAuth{},
 		&types.Tournament{},
-		&TournamentPlayer{},
-		&TournamentBacker{},
-		&TournamentWinner{},
+		&types.TournamentPlayer{},
+		&types.TournamentBacker{},
+		&types.TournamentWinner{},
 		&UserPointsOperations{},
 		&types.UserPointsBalance{},
+		&types.BalanceCorrection{},
+		&types.BalanceSnapshot{},
+		&types.Transfer{},
+		&types.TransferLimit{},
+		&types.BalanceHold{},
+		&types.BalanceLot{},
+		&types.BalanceLotUse{},
+		&types.Notification{},
+		&types.Voucher{},
+		&types.VoucherRedemption{},
+		&types.Bracket{},
+		&types.BracketPrize{},
+		&types.Match{},
+		&types.TournamentStanding{},
+		&types.Rating{},
+		&types.RatingChange{},
+		&types.Season{},
+		&types.SeasonPlacePoints{},
+		&types.SeasonReward{},
+		&types.SeasonPayout{},
+		&types.TournamentInvitation{},
+		&types.Ticket{},
+		&types.Team{},
+		&types.TeamMember{},
+		&types.TournamentTeam{},
+		&types.TournamentTeamMember{},
 	)
+	// Snapshots are unique per wallet since multi-currency wallets were introduced
+	if s.db.Dialect().HasIndex("balance_snapshots", "idx_balance_snapshots_user_id_taken_at") {
+		s.db.Model(&types.BalanceSnapshot{}).RemoveIndex("idx_balance_snapshots_user_id_taken_at")
+	}
+	if err := s.tagLegacyOperations(); err != nil {
+		s.logger.Printf("Could not tag legacy operations: %s", err.Error())
+	}
+}
+
+//Merges wallets created twice for the same user && currency by concurrent first credits into the oldest one,
+//so unique index of wallets could be created
+func (s *Storage) mergeDuplicateWallets() (err error) {
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	// Wallets of databases older than currencies && holds are merged before the columns are added by auto migration
+	if err = tx.Exec(`ALTER TABLE user_points_balances ADD COLUMN IF NOT EXISTS currency text NOT NULL DEFAULT 'points',
+		ADD COLUMN IF NOT EXISTS held integer NOT NULL DEFAULT 0`).Error; err != nil {
+		return err
+	}
+	if err = tx.Exec(MERGE_DUPLICATE_WALLETS_QUERY).Error; err != nil {
+		return err
+	}
+	if err = tx.Exec(DELETE_DUPLICATE_WALLETS_QUERY).Error; err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+//Operations recorded before kinds were introduced have neither kind nor tournament, so deposits && prizes
+//of tournaments played by then could not be told from other operations. Such operations get "legacy" kind
+//&& every tournament having no operations of known kind but entrants or winners with untagged operations
+//is marked as having legacy ledger, it's done once as there are no untagged operations since then
+func (s *Storage) tagLegacyOperations() (err error) {
+	untagged := 0
+	if err = s.db.Model(&UserPointsOperations{}).Where("COALESCE(kind, '') = ''").Count(&untagged).Error; err != nil || untagged == 0 {
+		return err
+	}
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	if err = tx.Exec(`UPDATE tournaments SET legacy_ledger = true WHERE NOT EXISTS (SELECT 1 FROM user_points_operations o
+		WHERE o.tournament_id = tournaments.id AND COALESCE(o.kind, '') <> '')
+		AND EXISTS (SELECT 1 FROM (SELECT user_id FROM tournament_players WHERE tournament_id = tournaments.id
+			UNION SELECT user_id FROM tournament_backers WHERE tournament_id = tournaments.id
+			UNION SELECT user_id FROM tournament_winners WHERE tournament_id = tournaments.id) e
+			JOIN user_points_operations o ON o.user_id = e.user_id AND COALESCE(o.kind, '') = '')`).Error; err != nil {
+		return err
+	}
+	if err = tx.Model(&UserPointsOperations{}).Where("COALESCE(kind, '') = ''").
+		UpdateColumn("kind", types.OPERATION_KIND_LEGACY).Error; err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+	return nil
 }
 
 func (s *Storage) FetchTournament(id uint) (interface{}, error) {
@@ -98,22 +211,19 @@ func (s *Storage) FetchTournaments(limit, offset int) (interface{}, error) {
 		err         error
 	)
 	tournaments = []*types.Tournament{}
-	if err = s.db.Debug().Limit(limit).Offset(offset).Find(&tournaments).Error; err != nil {
+	if err = s.db.Order("id").Limit(limit).Offset(offset).Find(&tournaments).Error; err != nil {
 		return nil, errors.New("An error occured during tournaments fetching")
 	}
-	if len(tournaments) == 0 {
-		return nil, errors.New("Tournaments not found")
-	}
 	return tournaments, nil
 }
 
-func (s *Storage) FetchBalance(id uint) (interface{}, error) {
+func (s *Storage) FetchBalance(id uint, currency string) (interface{}, error) {
 	var (
 		balance *types.UserPointsBalance
 		err     error
 	)
 	balance = &types.UserPointsBalance{}
-	if err = s.db.Where(&types.UserPointsBalance{UserId: id}).First(&balance).Error; err != nil {
+	if err = s.db.Where(&types.UserPointsBalance{UserId: id, Currency: currency}).First(&balance).Error; err != nil {
 		return nil, errors.New("An error occured during Balance fetching")
 	}
 	if balance == nil {
@@ -122,6 +232,39 @@ func (s *Storage) FetchBalance(id uint) (interface{}, error) {
 	return balance, nil
 }
 
+//Fetches wallets of every currency of given users
+func (s *Storage) FetchBalances(userIds []uint) (interface{}, error) {
+	balances := []*types.UserPointsBalance{}
+	if err := s.db.Where("user_id IN (?)", userIds).Order("user_id, currency").Find(&balances).Error; err != nil {
+		return nil, errors.New("An error occured during Balances fetching")
+	}
+	return balances, nil
+}
+
+func (s *Storage) FetchTournamentPlayers(tournamentIds []uint) (interface{}, error) {
+	players := []*types.TournamentPlayer{}
+	if err := s.db.Where("tournament_id IN (?)", tournamentIds).Order("id").Find(&players).Error; err != nil {
+		return nil, errors.New("An error occured during tournament players fetching")
+	}
+	return players, nil
+}
+
+func (s *Storage) FetchTournamentBackers(tournamentIds []uint) (interface{}, error) {
+	backers := []*types.TournamentBacker{}
+	if err := s.db.Where("tournament_id IN (?)", tournamentIds).Order("id").Find(&backers).Error; err != nil {
+		return nil, errors.New("An error occured during tournament backers fetching")
+	}
+	return backers, nil
+}
+
+func (s *Storage) FetchTournamentWinners(tournamentIds []uint) (interface{}, error) {
+	winners := []*types.TournamentWinner{}
+	if err := s.db.Where("tournament_id IN (?)", tournamentIds).Order("id").Find(&winners).Error; err != nil {
+		return nil, errors.New("An error occured during tournament winners fetching")
+	}
+	return winners, nil
+}
+
 func (s *Storage) finishTransaction(tx *gorm.DB, err error) {
 	if err != nil {
 		s.logger.Printf("Rollback transaction due to error %#v\n", err.Error())
@@ -132,43 +275,74 @@ func (s *Storage) finishTransaction(tx *gorm.DB, err error) {
 	}
 }
 
-func (s *Storage) TopUpBalance(id uint, points int) (interface{}, error) {
+//Funds user wallet with points creating the wallet if absent,
+//points expire at expiresAt unless spent by then if it's not nil
+func (s *Storage) TopUpBalance(id uint, currency string, points int, expiresAt *time.Time) (interface{}, error) {
 	var (
 		balance *types.UserPointsBalance
 		err     error
 	)
-	balance = &types.UserPointsBalance{}
 	tx := s.db.Begin()
 	defer func() { s.finishTransaction(tx, err) }()
 
-	if err = tx.FirstOrInit(balance, &types.UserPointsBalance{UserId: id}).Error; err != nil {
+	if balance, _, err = s.creditWallet(tx, id, currency, points, types.OPERATION_KIND_FUND, expiresAt); err != nil {
 		return nil, err
 	}
-	if balance == nil {
-		balance = &types.UserPointsBalance{
-			UserId:  id,
-			Balance: 0,
-		}
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+//Creates user wallet of currency unless there is one, so the wallet row could be locked right then.
+//Wallet created by concurrent transaction is waited for && kept
+func (s *Storage) ensureWallet(tx *gorm.DB, id uint, currency string) error {
+	return tx.Exec(ENSURE_WALLET_QUERY, id, currency).Error
+}
+
+//Credits points to user wallet by operation of given kind under wallet row lock creating the wallet if absent,
+//points expire at expiresAt unless spent by then if it's not nil
+func (s *Storage) creditWallet(tx *gorm.DB, id uint, currency string, points int, kind string, expiresAt *time.Time) (*types.UserPointsBalance, *UserPointsOperations, error) {
+	balance := &types.UserPointsBalance{}
+	if err := s.ensureWallet(tx, id, currency); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").Where(&types.UserPointsBalance{UserId: id, Currency: currency}).First(balance).Error; err != nil {
+		return nil, nil, err
 	}
 	balance.Balance += points
 	operation := &UserPointsOperations{
 		UserId:        id,
 		OperationType: USER_POINTS_OPERATION_DEBT,
+		Kind:          kind,
+		Currency:      currency,
 		Sum:           points,
 	}
-	if err = tx.Save(balance).Error; err != nil {
-		return nil, err
+	if err := tx.Save(balance).Error; err != nil {
+		return nil, nil, err
 	}
-	if err = tx.Create(operation).Error; err != nil {
-		return nil, err
+	if err := tx.Create(operation).Error; err != nil {
+		return nil, nil, err
 	}
-	if err = tx.Commit().Error; err != nil {
-		return nil, err
+	if expiresAt != nil {
+		if err := s.addLot(tx, balance, points, *expiresAt); err != nil {
+			return nil, nil, err
+		}
 	}
-	return balance, nil
+	return balance, operation, nil
 }
 
-func (s *Storage) TakeAwayBalance(id uint, points int) (interface{}, error) {
+//Credits points to user wallet by operation of given kind made for tournament, see creditWallet
+func (s *Storage) creditTournamentWallet(tx *gorm.DB, id uint, currency string, points int, kind string, tournamentId uint) error {
+	_, operation, err := s.creditWallet(tx, id, currency, points, kind, nil)
+	if err != nil {
+		return err
+	}
+	return tx.Model(operation).UpdateColumn("tournament_id", tournamentId).Error
+}
+
+//Takes points away from user wallet spending points expiring soonest first
+func (s *Storage) TakeAwayBalance(id uint, currency string, points int) (interface{}, error) {
 	var (
 		balance *types.UserPointsBalance
 		err     error
@@ -177,21 +351,26 @@ func (s *Storage) TakeAwayBalance(id uint, points int) (interface{}, error) {
 	tx := s.db.Begin()
 	defer func() { s.finishTransaction(tx, err) }()
 
-	if err = tx.FirstOrInit(balance, &types.UserPointsBalance{UserId: id}).Error; err != nil {
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").FirstOrInit(balance, &types.UserPointsBalance{UserId: id, Currency: currency}).Error; err != nil {
 		return nil, err
 	}
-	if balance == nil || balance.Balance < points {
+	if balance == nil || balance.Available < points {
 		return nil, errors.New(`Not enough points in user balance!`)
 	}
 	balance.Balance -= points
 	operation := &UserPointsOperations{
 		UserId:        id,
 		OperationType: USER_POINTS_OPERATION_CREDIT,
+		Kind:          types.OPERATION_KIND_TAKE,
+		Currency:      currency,
 		Sum:           points,
 	}
 	if err = tx.Save(balance).Error; err != nil {
 		return nil, err
 	}
+	if err = s.consumeLots(tx, id, currency, points, 0); err != nil {
+		return nil, err
+	}
 	if err = tx.Create(operation).Error; err != nil {
 		return nil, err
 	}
@@ -201,20 +380,70 @@ func (s *Storage) TakeAwayBalance(id uint, points int) (interface{}, error) {
 	return balance, nil
 }
 
-func (s *Storage) CreateNewTournament(announceTournamentRequest *types.AnnounceTournamentRequest) (interface{}, error) {
+//Creates tournament debiting its prize pool from sponsor's wallet by "sponsor" operation if it's sponsored,
+//guaranteed prize pool requires the house to fund its overlay, see fundOverlay
+func (s *Storage) CreateNewTournament(announceTournamentRequest *types.AnnounceTournamentRequest) (result interface{}, err error) {
 	// TODO(h.lazar) add game IDs somethere
 	if announceTournamentRequest.GameId <= 0 {
 		announceTournamentRequest.GameId = 1
 	}
+	if announceTournamentRequest.GuaranteedPool > 0 && s.transfersConf.HouseUserId == 0 {
+		return nil, errors.New(`Guaranteed prize pool requires house account!`)
+	}
+	if announceTournamentRequest.QualifierId != 0 && s.db.First(&types.Tournament{}, announceTournamentRequest.QualifierId).RecordNotFound() {
+		return nil, errors.New(`Qualifier tournament not found!`)
+	}
+	if announceTournamentRequest.TicketTournamentId != 0 {
+		if err = s.checkSatelliteTarget(announceTournamentRequest); err != nil {
+			return nil, err
+		}
+	}
 	tournament := &types.Tournament{
-		Deposit: announceTournamentRequest.Deposit,
-		Date:    announceTournamentRequest.Date,
-		GameId:  announceTournamentRequest.GameId,
+		Deposit:        announceTournamentRequest.Deposit,
+		Date:           announceTournamentRequest.Date,
+		GameId:         announceTournamentRequest.GameId,
+		MaxPlayers:     announceTournamentRequest.MaxPlayers,
+		Currency:       types.CurrencyOrDefault(announceTournamentRequest.Currency),
+		SponsorId:      announceTournamentRequest.SponsorId,
+		PrizePool:      announceTournamentRequest.PrizePool,
+		GuaranteedPool: announceTournamentRequest.GuaranteedPool,
+		Format:         announceTournamentRequest.Format,
+		MinRating:      announceTournamentRequest.MinRating,
+		MaxRating:      announceTournamentRequest.MaxRating,
+		InvitationOnly: len(announceTournamentRequest.InvitedUserIds) > 0 || announceTournamentRequest.InvitationCodes > 0,
+		QualifierId:    announceTournamentRequest.QualifierId,
+
+		TicketTournamentId: announceTournamentRequest.TicketTournamentId,
+		Tickets:            announceTournamentRequest.Tickets,
+		TicketRefundable:   announceTournamentRequest.TicketRefundable,
+		TeamSize:           announceTournamentRequest.TeamSize,
 	}
-	err := s.db.Save(tournament).Error
-	return tournament, err
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	if err = tx.Save(tournament).Error; err != nil {
+		return nil, err
+	}
+	if tournament.PrizePool > 0 {
+		if err = s.fundPrizePool(tx, tournament); err != nil {
+			return nil, err
+		}
+	}
+	if tournament.InvitationOnly {
+		if err = s.createInvitations(tx, tournament, announceTournamentRequest); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return tournament, nil
 }
 
+//Holds tournament deposit shares on player's and backers' balances,
+//they are debited when registration closes, see CloseTournamentRegistration;
+//entry by ticket holds nothing, see joinByTicket
 func (s *Storage) JoinTournamentAndTakePointsFromUserBalances(joinTournamentRequest *types.JoinTournamentRequest) (err error) {
 	var (
 		tournament     *types.Tournament
@@ -237,27 +466,61 @@ func (s *Storage) JoinTournamentAndTakePointsFromUserBalances(joinTournamentRequ
 
 	// TODO (h.lazar) add a check to all users be unique (do not allow user to back himself)
 	tournament = &types.Tournament{}
-	if err = tx.First(tournament, joinTournamentRequest.TournamentId).Error; err != nil {
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(tournament, joinTournamentRequest.TournamentId).Error; err != nil {
 		return err
 	}
-	if tournament.Date.Before(time.Now()) {
-		err = errors.New(`Tournament out of date!`)
+	if err = checkRegistrationOpen(tournament); err != nil {
 		return err
 	}
-	if tournament.State != 0 {
-		err = errors.New(`Tournament already finished!`)
+	if tournament.IsTeamTournament() {
+		err = errors.New(`Tournament is entered by teams only!`)
 		return err
 	}
 
-	if !tx.Where(&TournamentPlayer{UserId: joinTournamentRequest.PlayerId, TournamentId: tournament.ID}).First(&TournamentPlayer{}).RecordNotFound() {
+	if !tx.Where(&types.TournamentPlayer{UserId: joinTournamentRequest.PlayerId, TournamentId: tournament.ID}).First(&types.TournamentPlayer{}).RecordNotFound() {
 		err = errors.New(`User already perticipates tournament!`)
 		return err
 	}
+	if err = s.checkEntryRestrictions(tx, tournament, joinTournamentRequest); err != nil {
+		return err
+	}
+
+	if tournament.IsFreeRoll() {
+		if len(joinTournamentRequest.BackerIds) > 0 {
+			err = errors.New(`Free-roll entries could not be backed!`)
+			return err
+		}
+		// Player gets a wallet of tournament currency to be paid prizes to
+		if err = s.ensureWallet(tx, joinTournamentRequest.PlayerId, tournament.Currency); err != nil {
+			return err
+		}
+	}
+
+	if tournament.MaxPlayers > 0 {
+		playersCount := 0
+		if err = tx.Model(&types.TournamentPlayer{}).Where(&types.TournamentPlayer{TournamentId: tournament.ID}).Count(&playersCount).Error; err != nil {
+			return err
+		}
+		if playersCount >= tournament.MaxPlayers {
+			err = errors.New(`No free seats in tournament!`)
+			return err
+		}
+	}
+
+	if joinTournamentRequest.TicketId != 0 {
+		if err = s.joinByTicket(tx, tournament, joinTournamentRequest); err != nil {
+			return err
+		}
+		if err = tx.Commit().Error; err != nil {
+			return err
+		}
+		return nil
+	}
 
 	stake := tournament.Deposit / stakesCount
 	balances = []*types.UserPointsBalance{}
 
-	if err = tx.Where("user_id IN (?)", stakeholderIds).Find(&balances).Error; err != nil {
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").Where("user_id IN (?) AND currency = ?", stakeholderIds, tournament.Currency).Order("user_id").Find(&balances).Error; err != nil {
 		return err
 	}
 	if len(balances) == 0 {
@@ -271,20 +534,20 @@ func (s *Storage) JoinTournamentAndTakePointsFromUserBalances(joinTournamentRequ
 	}
 
 	for _, balance := range balances {
-		if balance.Balance < stake {
+		if balance.Available < stake {
 			err = errors.New("One or more participants have not enough balance")
 			return err
 		}
 		if balance.UserId == joinTournamentRequest.PlayerId {
 			err = tx.Create(
-				&TournamentPlayer{
+				&types.TournamentPlayer{
 					TournamentId: joinTournamentRequest.TournamentId,
 					UserId:       joinTournamentRequest.PlayerId,
 					UserDeposit:  stake,
 				}).Error
 		} else {
 			err = tx.Create(
-				&TournamentBacker{
+				&types.TournamentBacker{
 					TournamentId:  joinTournamentRequest.TournamentId,
 					UserId:        joinTournamentRequest.PlayerId,
 					BackerId:      balance.UserId,
@@ -294,15 +557,10 @@ func (s *Storage) JoinTournamentAndTakePointsFromUserBalances(joinTournamentRequ
 		if err != nil {
 			return err
 		}
-		if err = tx.Create(
-			&UserPointsOperations{
-				UserId:        balance.UserId,
-				OperationType: USER_POINTS_OPERATION_CREDIT,
-				Sum:           stake,
-			}).Error; err != nil {
-			return err
+		if stake == 0 {
+			continue
 		}
-		if err = tx.Model(balance).Update(`balance`, balance.Balance-stake).Error; err != nil {
+		if err = s.placeHold(tx, tournament, joinTournamentRequest.PlayerId, balance, stake); err != nil {
 			return err
 		}
 	}
@@ -313,21 +571,28 @@ func (s *Storage) JoinTournamentAndTakePointsFromUserBalances(joinTournamentRequ
 	return nil
 }
 
+func checkRegistrationOpen(tournament *types.Tournament) error {
+	if tournament.Date.Before(time.Now()) {
+		return errors.New(`Tournament out of date!`)
+	}
+	if tournament.State != types.TOURNAMENT_STATE_OPEN {
+		return errors.New(`Tournament already finished or cancelled!`)
+	}
+	if tournament.RegistrationClosedAt != nil {
+		return errors.New(`Tournament registration already closed!`)
+	}
+	return nil
+}
+
 func (s *Storage) CheckAndSpreadTournamentPrize(resultTournamentRequest *types.ResultTournamentRequest) (err error) {
-	var (
-		tournament        *types.Tournament
-		balances          []*types.UserPointsBalance
-		tournamentPlayer  *TournamentPlayer
-		tournamentBackers []*TournamentBacker
-		stakeholderIds    []uint
-	)
+	var tournament *types.Tournament
 
 	tx := s.db.Begin()
 	//tx.LogMode(true)
 	defer func() { s.finishTransaction(tx, err) }()
 
 	tournament = &types.Tournament{}
-	if err = tx.First(tournament, resultTournamentRequest.TournamentId).Error; err != nil {
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(tournament, resultTournamentRequest.TournamentId).Error; err != nil {
 		return err
 	}
 	//TODO(h.lazar) commented just for testing conveniency. To be uncommented
@@ -335,38 +600,122 @@ func (s *Storage) CheckAndSpreadTournamentPrize(resultTournamentRequest *types.R
 	//	err = errors.New(`Tournament still did not started!`)
 	//	return err
 	//}
-	if tournament.State != 0 {
-		err = errors.New(`Tournament already finished!`)
+	if tournament.State != types.TOURNAMENT_STATE_OPEN {
+		err = errors.New(`Tournament already finished or cancelled!`)
+		return err
+	}
+	if tournament.Format != "" {
+		if err = s.checkBracketResults(tx, tournament); err != nil {
+			return err
+		}
+	}
+	if err = s.spreadTournamentPrize(tx, tournament, resultTournamentRequest.Winners); err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
 		return err
 	}
+	return nil
+}
 
-	for _, winner := range resultTournamentRequest.Winners {
+//Pays prizes to winners && their backers closing registration if it's still open, splits prizes of team winners
+//between members, issues tickets won in satellite,
+//funds overlay of guaranteed pool, returns unspent sponsored pool, finishes tournament && rates its players.
+//Tournament must be locked
+func (s *Storage) spreadTournamentPrize(tx *gorm.DB, tournament *types.Tournament, winners []*types.TournamentWinnerRequest) (err error) {
+	var (
+		balances          []*types.UserPointsBalance
+		tournamentPlayer  *types.TournamentPlayer
+		tournamentBackers []*types.TournamentBacker
+		stakeholderIds    []uint
+	)
 
-		tournamentPlayer = &TournamentPlayer{}
+	if tournament.RegistrationClosedAt == nil {
+		if err = s.closeRegistration(tx, tournament); err != nil {
+			return err
+		}
+	}
+
+	prizes, tickets := 0, 0
+	for _, winner := range winners {
+		prizes += winner.Prize
+		if winner.Ticket {
+			tickets++
+		}
+		if (winner.TeamId != 0) != tournament.IsTeamTournament() {
+			err = errors.New(`Winners of team tournaments are teams, winners of others are players!`)
+			return err
+		}
+	}
+	if tickets > 0 && !tournament.IsSatellite() {
+		err = errors.New(`Tickets are awarded by satellites only!`)
+		return err
+	}
+	if tickets > tournament.Tickets {
+		err = errors.New(`Winners get more tickets than satellite awards!`)
+		return err
+	}
+	if prizes < tournament.GuaranteedPool {
+		err = errors.New(`Prizes must add up to guaranteed prize pool at least!`)
+		return err
+	}
+	var overlay int
+	if overlay, err = s.tournamentOverlay(tx, tournament); err != nil {
+		return err
+	}
+	if tournament.IsFreeRoll() && prizes > tournament.PrizePool+overlay {
+		err = errors.New(`Prizes exceed free-roll prize pool!`)
+		return err
+	}
+	if overlay > 0 {
+		if err = s.fundOverlay(tx, tournament, overlay); err != nil {
+			return err
+		}
+	}
+
+	for _, winner := range winners {
+		if winner.TeamId != 0 {
+			if err = s.spreadTeamPrize(tx, tournament, winner); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tournamentPlayer = &types.TournamentPlayer{}
 
 		if err = tx.Where(
-			&TournamentPlayer{
+			&types.TournamentPlayer{
 				UserId:       winner.PlayerId,
 				TournamentId: tournament.ID,
 			}).First(&tournamentPlayer).Error; err != nil {
 			return err
 		}
 
-		if err = tx.Create(
-			&TournamentWinner{
-				TournamentId: tournament.ID,
-				UserId:       winner.PlayerId,
-				Prize:        winner.Prize,
-			}).Error; err != nil {
+		tournamentWinner := &types.TournamentWinner{
+			TournamentId: tournament.ID,
+			UserId:       winner.PlayerId,
+			Prize:        winner.Prize,
+		}
+		if winner.Ticket {
+			var ticket *types.Ticket
+			if ticket, err = s.issueTicket(tx, tournament, winner.PlayerId); err != nil {
+				return err
+			}
+			tournamentWinner.TicketId = ticket.ID
+		}
+		if err = tx.Create(tournamentWinner).Error; err != nil {
 			return err
 		}
+		if winner.Ticket && winner.Prize == 0 {
+			continue
+		}
 
 		stakeholderIds = []uint{winner.PlayerId}
 
-		tournamentBackers = []*TournamentBacker{}
+		tournamentBackers = []*types.TournamentBacker{}
 
 		if err = tx.Where(
-			&TournamentBacker{
+			&types.TournamentBacker{
 				UserId:       winner.PlayerId,
 				TournamentId: tournament.ID,
 			}).Find(&tournamentBackers).Error; err != nil {
@@ -378,7 +727,7 @@ func (s *Storage) CheckAndSpreadTournamentPrize(resultTournamentRequest *types.R
 
 		balances = []*types.UserPointsBalance{}
 
-		if err = tx.Where("user_id IN (?)", stakeholderIds).Find(&balances).Error; err != nil {
+		if err = tx.Set("gorm:query_option", "FOR UPDATE").Where("user_id IN (?) AND currency = ?", stakeholderIds, tournament.Currency).Order("user_id").Find(&balances).Error; err != nil {
 			return err
 		}
 		if len(balances) < len(stakeholderIds) {
@@ -394,27 +743,29 @@ func (s *Storage) CheckAndSpreadTournamentPrize(resultTournamentRequest *types.R
 				&UserPointsOperations{
 					UserId:        balance.UserId,
 					OperationType: USER_POINTS_OPERATION_DEBT,
+					Kind:          types.OPERATION_KIND_PRIZE,
+					TournamentId:  tournament.ID,
+					Currency:      tournament.Currency,
 					Sum:           stake,
 				}).Error; err != nil {
 				return err
 			}
-			if err = tx.Model(balance).Update(
-				&types.UserPointsBalance{
-					Balance: balance.Balance + stake,
-				}).Error; err != nil {
+			if err = tx.Model(balance).UpdateColumn(`balance`, gorm.Expr(`balance + ?`, stake)).Error; err != nil {
 				return err
 			}
 		}
 	}
 
-	if err = tx.Model(tournament).Update(&types.Tournament{State: 1}).Error; err != nil {
-		return err
+	if prizes < tournament.PrizePool {
+		if err = s.returnPrizePool(tx, tournament, tournament.PrizePool-prizes); err != nil {
+			return err
+		}
 	}
 
-	if err = tx.Commit().Error; err != nil {
+	if err = tx.Model(tournament).Update(&types.Tournament{State: types.TOURNAMENT_STATE_FINISHED}).Error; err != nil {
 		return err
 	}
-	return nil
+	return s.rateTournament(tx, tournament)
 }
 
 func getConnectionString(conf *DsnColfig) string {
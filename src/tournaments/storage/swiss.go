@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"sort"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Pairing search gives up avoiding rematches after so many tries
+const SWISS_PAIRING_MAX_STEPS = 100000
+
+// Player of swiss tournament scored by rounds played so far
+type swissEntry struct {
+	userId    uint
+	seed      int
+	points    int
+	hadBye    bool
+	opponents map[uint]bool
+}
+
+//Makes swiss entries of players ordered by seed, strongest first
+func newSwissEntries(playerIds []uint) []*swissEntry {
+	entries := make([]*swissEntry, 0, len(playerIds))
+	for i, userId := range playerIds {
+		entries = append(entries, &swissEntry{userId: userId, seed: i + 1, opponents: map[uint]bool{}})
+	}
+	return entries
+}
+
+//Makes swiss entries of players of standings scoring finished matches
+func swissEntries(standings []*types.TournamentStanding, matches map[uint]*types.Match) []*swissEntry {
+	entries := make([]*swissEntry, 0, len(standings))
+	byUser := make(map[uint]*swissEntry, len(standings))
+	for _, standing := range standings {
+		entry := &swissEntry{userId: standing.UserId, seed: standing.Seed, opponents: map[uint]bool{}}
+		entries = append(entries, entry)
+		byUser[standing.UserId] = entry
+	}
+	for _, match := range matches {
+		if match.State != types.MATCH_STATE_FINISHED {
+			continue
+		}
+		if match.Bye {
+			if winner, ok := byUser[match.WinnerId]; ok {
+				winner.hadBye = true
+				winner.points += types.SWISS_POINTS_WIN
+			}
+			continue
+		}
+		player1, player2 := byUser[match.Player1Id], byUser[match.Player2Id]
+		player1.opponents[player2.userId] = true
+		player2.opponents[player1.userId] = true
+		switch match.WinnerId {
+		case 0:
+			player1.points += types.SWISS_POINTS_DRAW
+			player2.points += types.SWISS_POINTS_DRAW
+		case player1.userId:
+			player1.points += types.SWISS_POINTS_WIN
+		default:
+			player2.points += types.SWISS_POINTS_WIN
+		}
+	}
+	return entries
+}
+
+//Returns default number of swiss rounds for players count, enough to leave a single player with all wins
+func swissRounds(players int) int {
+	rounds := 1
+	for 1<<uint(rounds) < players {
+		rounds++
+	}
+	return rounds
+}
+
+//Plans swiss round pairing players of equal points, top half of score group against bottom half,
+//players left unpaired in their group float down to the next one; rematches are avoided if possible.
+//If players are odd, the lowest ranked one not having a bye yet gets one
+func planSwissRound(entries []*swissEntry, round int) *bracketPlan {
+	ranked := append([]*swissEntry{}, entries...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].points != ranked[j].points {
+			return ranked[i].points > ranked[j].points
+		}
+		return ranked[i].seed < ranked[j].seed
+	})
+	pairs, bye := pairSwiss(ranked)
+
+	plan := &bracketPlan{}
+	for position, pair := range pairs {
+		planned := plan.addRound(types.MATCH_BRACKET_SWISS, round, 1, 0)[0]
+		planned.match.Position = position
+		plan.seats = append(plan.seats,
+			&plannedSeat{match: planned, slot: 1, playerId: pair[0].userId},
+			&plannedSeat{match: planned, slot: 2, playerId: pair[1].userId})
+	}
+	if bye != nil {
+		planned := plan.addRound(types.MATCH_BRACKET_SWISS, round, 1, 0)[0]
+		planned.match.Position = len(pairs)
+		plan.seats = append(plan.seats,
+			&plannedSeat{match: planned, slot: 1, playerId: bye.userId},
+			&plannedSeat{match: planned, slot: 2, playerId: 0})
+	}
+	return plan
+}
+
+//Pairs ranked players trying bye candidates from the lowest ranked one,
+//allows rematches only if there is no pairing without them
+func pairSwiss(ranked []*swissEntry) ([][2]*swissEntry, *swissEntry) {
+	byeCandidates := []int{-1}
+	if len(ranked)%2 == 1 {
+		byeCandidates = byeCandidates[:0]
+		for _, hadBye := range []bool{false, true} {
+			for i := len(ranked) - 1; i >= 0; i-- {
+				if ranked[i].hadBye == hadBye {
+					byeCandidates = append(byeCandidates, i)
+				}
+			}
+		}
+	}
+	for _, avoidRematches := range []bool{true, false} {
+		steps := SWISS_PAIRING_MAX_STEPS
+		for _, i := range byeCandidates {
+			rest := ranked
+			var bye *swissEntry
+			if i >= 0 {
+				bye = ranked[i]
+				rest = append(append([]*swissEntry{}, ranked[:i]...), ranked[i+1:]...)
+			}
+			if pairs, ok := pairSwissEntries(rest, avoidRematches, &steps); ok {
+				return pairs, bye
+			}
+		}
+	}
+	// Pairing allowing rematches never fails
+	return nil, nil
+}
+
+func pairSwissEntries(ranked []*swissEntry, avoidRematches bool, steps *int) ([][2]*swissEntry, bool) {
+	if len(ranked) == 0 {
+		return nil, true
+	}
+	first := ranked[0]
+	for _, i := range swissOpponents(ranked) {
+		if *steps <= 0 {
+			return nil, false
+		}
+		*steps--
+		opponent := ranked[i]
+		if avoidRematches && first.opponents[opponent.userId] {
+			continue
+		}
+		rest := make([]*swissEntry, 0, len(ranked)-2)
+		rest = append(rest, ranked[1:i]...)
+		rest = append(rest, ranked[i+1:]...)
+		if pairs, ok := pairSwissEntries(rest, avoidRematches, steps); ok {
+			return append([][2]*swissEntry{{first, opponent}}, pairs...), true
+		}
+	}
+	return nil, false
+}
+
+//Returns indexes of opponents of the first ranked player in order of preference:
+//the one heading bottom half of player's score group, the rest of the bottom half,
+//top half from its end and lower score groups then
+func swissOpponents(ranked []*swissEntry) []int {
+	group := 1
+	for group < len(ranked) && ranked[group].points == ranked[0].points {
+		group++
+	}
+	opponents := make([]int, 0, len(ranked)-1)
+	for i := group / 2; i < group; i++ {
+		if i > 0 {
+			opponents = append(opponents, i)
+		}
+	}
+	for i := group/2 - 1; i > 0; i-- {
+		opponents = append(opponents, i)
+	}
+	for i := group; i < len(ranked); i++ {
+		opponents = append(opponents, i)
+	}
+	return opponents
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Makes swiss entry of player ranked by seed having met opponents
+func swissEntryOf(userId uint, points int, hadBye bool, opponents ...uint) *swissEntry {
+	entry := &swissEntry{userId: userId, seed: int(userId), points: points, hadBye: hadBye, opponents: map[uint]bool{}}
+	for _, opponentId := range opponents {
+		entry.opponents[opponentId] = true
+	}
+	return entry
+}
+
+func TestPairSwiss(t *testing.T) {
+	cases := []struct {
+		name   string
+		ranked []*swissEntry
+		pairs  [][2]uint
+		bye    uint
+	}{
+		{
+			name:   "top half meets bottom half",
+			ranked: []*swissEntry{swissEntryOf(1, 0, false), swissEntryOf(2, 0, false), swissEntryOf(3, 0, false), swissEntryOf(4, 0, false)},
+			pairs:  [][2]uint{{1, 3}, {2, 4}},
+		},
+		{
+			name: "rematch is avoided",
+			ranked: []*swissEntry{swissEntryOf(1, 2, false, 3), swissEntryOf(2, 2, false, 4),
+				swissEntryOf(3, 2, false, 1), swissEntryOf(4, 2, false, 2)},
+			pairs: [][2]uint{{1, 4}, {2, 3}},
+		},
+		{
+			name: "unpaired leader floats down to lower score group",
+			ranked: []*swissEntry{swissEntryOf(1, 2, false, 2), swissEntryOf(2, 2, false, 1),
+				swissEntryOf(3, 0, false, 4), swissEntryOf(4, 0, false, 3)},
+			pairs: [][2]uint{{1, 3}, {2, 4}},
+		},
+		{
+			name:   "lowest ranked player gets bye",
+			ranked: []*swissEntry{swissEntryOf(1, 0, false), swissEntryOf(2, 0, false), swissEntryOf(3, 0, false)},
+			pairs:  [][2]uint{{1, 2}},
+			bye:    3,
+		},
+		{
+			name:   "player gets bye once if possible",
+			ranked: []*swissEntry{swissEntryOf(1, 2, false), swissEntryOf(2, 2, false), swissEntryOf(3, 2, true)},
+			pairs:  [][2]uint{{1, 3}},
+			bye:    2,
+		},
+		{
+			name:   "rematch is allowed if unavoidable",
+			ranked: []*swissEntry{swissEntryOf(1, 2, false, 2), swissEntryOf(2, 0, false, 1)},
+			pairs:  [][2]uint{{1, 2}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pairs, bye := pairSwiss(c.ranked)
+			pairIds := [][2]uint{}
+			for _, pair := range pairs {
+				pairIds = append(pairIds, [2]uint{pair[0].userId, pair[1].userId})
+			}
+			byeId := uint(0)
+			if bye != nil {
+				byeId = bye.userId
+			}
+			if !reflect.DeepEqual(pairIds, c.pairs) || byeId != c.bye {
+				t.Errorf("got pairs %v bye %d, want pairs %v bye %d", pairIds, byeId, c.pairs, c.bye)
+			}
+		})
+	}
+}
+
+func TestSwissTieBreaks(t *testing.T) {
+	finished := func(id uint, player1Id uint, player2Id uint, winnerId uint) *types.Match {
+		return &types.Match{ID: id, Player1Id: player1Id, Player2Id: player2Id, WinnerId: winnerId, State: types.MATCH_STATE_FINISHED}
+	}
+	bye := finished(4, 2, 0, 2)
+	bye.Bye = true
+	engine := newBracketEngine([]*types.Match{
+		finished(1, 1, 2, 1),
+		finished(2, 3, 4, 0),
+		finished(3, 1, 3, 0),
+		bye,
+		{ID: 5, Player1Id: 2, Player2Id: 4, State: types.MATCH_STATE_READY},
+	})
+	// Points are win 2 && draw 1 each, bye scores a win
+	byUser := map[uint]*types.TournamentStanding{
+		1: {UserId: 1, Points: 3},
+		2: {UserId: 2, Points: 2},
+		3: {UserId: 3, Points: 2},
+		4: {UserId: 4, Points: 1},
+	}
+	engine.swissTieBreaks(byUser)
+
+	cases := []struct {
+		userId          uint
+		buchholz        float64
+		sonnebornBerger float64
+	}{
+		{1, 4, 3},
+		{2, 3, 0},
+		{3, 4, 2},
+		{4, 2, 1},
+	}
+	for _, c := range cases {
+		standing := byUser[c.userId]
+		if standing.Buchholz != c.buchholz || standing.SonnebornBerger != c.sonnebornBerger {
+			t.Errorf("player %d got Buchholz %v Sonneborn-Berger %v, want %v %v",
+				c.userId, standing.Buchholz, standing.SonnebornBerger, c.buchholz, c.sonnebornBerger)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Creates team with its members && their shares
+func (s *Storage) CreateTeam(request *types.TeamRequest) (result interface{}, err error) {
+	team := &types.Team{
+		Name:      request.Name,
+		CaptainId: request.CaptainId,
+		Members:   make([]*types.TeamMember, 0, len(request.Members)),
+	}
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	if err = tx.Create(team).Error; err != nil {
+		return nil, err
+	}
+	for _, memberRequest := range request.Members {
+		member := &types.TeamMember{TeamId: team.ID, UserId: memberRequest.UserId, Share: memberRequest.Share}
+		if err = tx.Create(member).Error; err != nil {
+			return nil, err
+		}
+		team.Members = append(team.Members, member)
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+//Fetches team with its members
+func (s *Storage) FetchTeam(id uint) (interface{}, error) {
+	team := &types.Team{}
+	if err := s.db.First(team, id).Error; err != nil {
+		return nil, err
+	}
+	team.Members = []*types.TeamMember{}
+	if err := s.db.Where(&types.TeamMember{TeamId: team.ID}).Order("user_id").Find(&team.Members).Error; err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+//Registers team in team tournament holding shares of its deposit on members' balances by their shares,
+//the holds belong to entry of team captain and are debited when registration closes like any others.
+//Every member must qualify for tournament entry restrictions, invitations count by user ID only
+func (s *Storage) JoinTournamentByTeam(request *types.TeamEntryRequest) (err error) {
+	var (
+		members  []*types.TeamMember
+		balances []*types.UserPointsBalance
+	)
+
+	team := &types.Team{}
+	if err = s.db.First(team, request.TeamId).Error; err != nil {
+		return err
+	}
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	tournament := &types.Tournament{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(tournament, request.TournamentId).Error; err != nil {
+		return err
+	}
+	if err = checkRegistrationOpen(tournament); err != nil {
+		return err
+	}
+	if !tournament.IsTeamTournament() {
+		err = errors.New(`Tournament is not entered by teams!`)
+		return err
+	}
+
+	members = []*types.TeamMember{}
+	if err = tx.Where(&types.TeamMember{TeamId: team.ID}).Order("user_id").Find(&members).Error; err != nil {
+		return err
+	}
+	if len(members) != tournament.TeamSize {
+		err = errors.New(`Team size does not match tournament team size!`)
+		return err
+	}
+	memberIds := make([]uint, 0, len(members))
+	for _, member := range members {
+		memberIds = append(memberIds, member.UserId)
+	}
+	if !tx.Where(&types.TournamentTeam{TournamentId: tournament.ID, TeamId: team.ID}).First(&types.TournamentTeam{}).RecordNotFound() {
+		err = errors.New(`Team already participates tournament!`)
+		return err
+	}
+	entered := 0
+	if err = tx.Model(&types.TournamentTeamMember{}).Where("tournament_id = ? AND user_id IN (?)", tournament.ID, memberIds).
+		Count(&entered).Error; err != nil {
+		return err
+	}
+	if entered > 0 {
+		err = errors.New(`One or more team members already participate tournament!`)
+		return err
+	}
+	for _, member := range members {
+		if err = s.checkEntryRestrictions(tx, tournament, &types.JoinTournamentRequest{TournamentId: tournament.ID, PlayerId: member.UserId}); err != nil {
+			return err
+		}
+	}
+
+	if tournament.MaxPlayers > 0 {
+		teamsCount := 0
+		if err = tx.Model(&types.TournamentTeam{}).Where(&types.TournamentTeam{TournamentId: tournament.ID}).Count(&teamsCount).Error; err != nil {
+			return err
+		}
+		if teamsCount >= tournament.MaxPlayers {
+			err = errors.New(`No free seats in tournament!`)
+			return err
+		}
+	}
+
+	// Members get wallets of tournament currency to be paid prizes to
+	for _, memberId := range memberIds {
+		if err = s.ensureWallet(tx, memberId, tournament.Currency); err != nil {
+			return err
+		}
+	}
+	balances = []*types.UserPointsBalance{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").Where("user_id IN (?) AND currency = ?", memberIds, tournament.Currency).
+		Order("user_id").Find(&balances).Error; err != nil {
+		return err
+	}
+	stakes := teamShares(tournament.Deposit, members, team.CaptainId)
+	for _, balance := range balances {
+		if balance.Available < stakes[balance.UserId] {
+			err = errors.New("One or more team members have not enough balance")
+			return err
+		}
+	}
+
+	if err = tx.Create(
+		&types.TournamentTeam{
+			TournamentId: tournament.ID,
+			TeamId:       team.ID,
+			CaptainId:    team.CaptainId,
+			Deposit:      tournament.Deposit,
+		}).Error; err != nil {
+		return err
+	}
+	for _, member := range members {
+		if err = tx.Create(
+			&types.TournamentTeamMember{
+				TournamentId: tournament.ID,
+				TeamId:       team.ID,
+				UserId:       member.UserId,
+				Share:        member.Share,
+				Deposit:      stakes[member.UserId],
+			}).Error; err != nil {
+			return err
+		}
+	}
+	for _, balance := range balances {
+		if stakes[balance.UserId] == 0 {
+			continue
+		}
+		if err = s.placeHold(tx, tournament, team.CaptainId, balance, stakes[balance.UserId]); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+//Withdraws team entry from tournament releasing deposits held on members' balances,
+//possible until registration closes
+func (s *Storage) WithdrawTeamFromTournament(request *types.TeamEntryRequest) (err error) {
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	tournament := &types.Tournament{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(tournament, request.TournamentId).Error; err != nil {
+		return err
+	}
+	if tournament.State != types.TOURNAMENT_STATE_OPEN || tournament.RegistrationClosedAt != nil {
+		err = errors.New(`Tournament registration already closed!`)
+		return err
+	}
+	entry := &types.TournamentTeam{}
+	if tx.Where(&types.TournamentTeam{TournamentId: tournament.ID, TeamId: request.TeamId}).First(entry).RecordNotFound() {
+		err = errors.New(`Team does not participate tournament!`)
+		return err
+	}
+	if err = s.releaseEntry(tx, tournament.ID, entry.CaptainId, types.HOLD_STATE_RELEASED); err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+//Removes team entry owned by captain, if any, along with its members
+func (s *Storage) removeTeamEntry(tx *gorm.DB, tournamentId uint, captainId uint) error {
+	entry := &types.TournamentTeam{}
+	if tx.Where(&types.TournamentTeam{TournamentId: tournamentId, CaptainId: captainId}).First(entry).RecordNotFound() {
+		return nil
+	}
+	if err := tx.Where(&types.TournamentTeamMember{TournamentId: tournamentId, TeamId: entry.TeamId}).Delete(&types.TournamentTeamMember{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(entry).Error
+}
+
+//Splits team prize between members by their shares fixed as team registered,
+//records every member as winner of its part of the prize && credits it by "prize" operation
+func (s *Storage) spreadTeamPrize(tx *gorm.DB, tournament *types.Tournament, winner *types.TournamentWinnerRequest) error {
+	entry := &types.TournamentTeam{}
+	if tx.Where(&types.TournamentTeam{TournamentId: tournament.ID, TeamId: winner.TeamId}).First(entry).RecordNotFound() {
+		return errors.New(`Team does not participate tournament!`)
+	}
+	entryMembers := []*types.TournamentTeamMember{}
+	if err := tx.Where(&types.TournamentTeamMember{TournamentId: tournament.ID, TeamId: entry.TeamId}).
+		Order("user_id").Find(&entryMembers).Error; err != nil {
+		return err
+	}
+	members := make([]*types.TeamMember, 0, len(entryMembers))
+	for _, entryMember := range entryMembers {
+		members = append(members, &types.TeamMember{UserId: entryMember.UserId, Share: entryMember.Share})
+	}
+	payouts := teamShares(winner.Prize, members, entry.CaptainId)
+	for _, member := range members {
+		payout := payouts[member.UserId]
+		if err := tx.Create(
+			&types.TournamentWinner{
+				TournamentId: tournament.ID,
+				UserId:       member.UserId,
+				Prize:        payout,
+				TeamId:       entry.TeamId,
+			}).Error; err != nil {
+			return err
+		}
+		if payout == 0 {
+			continue
+		}
+		if err := s.creditTournamentWallet(tx, member.UserId, tournament.Currency, payout, types.OPERATION_KIND_PRIZE, tournament.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Splits amount between members by their shares, indivisible remainder goes to the captain
+func teamShares(amount int, members []*types.TeamMember, captainId uint) map[uint]int {
+	shares := make(map[uint]int, len(members))
+	rest := amount
+	for _, member := range members {
+		shares[member.UserId] = amount * member.Share / types.TEAM_SHARES_TOTAL
+		rest -= shares[member.UserId]
+	}
+	shares[captainId] += rest
+	return shares
+}
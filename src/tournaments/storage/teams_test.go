@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+func TestTeamShares(t *testing.T) {
+	member := func(userId uint, share int) *types.TeamMember {
+		return &types.TeamMember{UserId: userId, Share: share}
+	}
+	cases := []struct {
+		name      string
+		amount    int
+		members   []*types.TeamMember
+		captainId uint
+		shares    map[uint]int
+	}{
+		{
+			name:      "even shares",
+			amount:    1000,
+			members:   []*types.TeamMember{member(1, 50), member(2, 50)},
+			captainId: 1,
+			shares:    map[uint]int{1: 500, 2: 500},
+		},
+		{
+			name:      "uneven shares",
+			amount:    1000,
+			members:   []*types.TeamMember{member(1, 33), member(2, 33), member(3, 34)},
+			captainId: 2,
+			shares:    map[uint]int{1: 330, 2: 330, 3: 340},
+		},
+		{
+			name:      "indivisible remainder goes to the captain",
+			amount:    101,
+			members:   []*types.TeamMember{member(1, 33), member(2, 33), member(3, 34)},
+			captainId: 3,
+			shares:    map[uint]int{1: 33, 2: 33, 3: 35},
+		},
+		{
+			name:      "rounding remainders add up to the captain",
+			amount:    10,
+			members:   []*types.TeamMember{member(1, 33), member(2, 33), member(3, 34)},
+			captainId: 1,
+			shares:    map[uint]int{1: 4, 2: 3, 3: 3},
+		},
+		{
+			name:      "member of zero share gets nothing",
+			amount:    999,
+			members:   []*types.TeamMember{member(1, 0), member(2, 100)},
+			captainId: 1,
+			shares:    map[uint]int{1: 0, 2: 999},
+		},
+		{
+			name:      "nothing to split",
+			amount:    0,
+			members:   []*types.TeamMember{member(1, 60), member(2, 40)},
+			captainId: 2,
+			shares:    map[uint]int{1: 0, 2: 0},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			shares := teamShares(c.amount, c.members, c.captainId)
+			if !reflect.DeepEqual(shares, c.shares) {
+				t.Errorf("got %v, want %v", shares, c.shares)
+			}
+			sum := 0
+			for _, share := range shares {
+				sum += share
+			}
+			if sum != c.amount {
+				t.Errorf("shares add up to %d, want %d", sum, c.amount)
+			}
+		})
+	}
+}
+
+func mustCreateTeam(t *testing.T, s *Storage, captainId uint, members ...*types.TeamMemberRequest) *types.Team {
+	t.Helper()
+	team, err := s.CreateTeam(&types.TeamRequest{Name: "team", CaptainId: captainId, Members: members})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return team.(*types.Team)
+}
+
+func TestJoinTournamentByTeam(t *testing.T) {
+	s := newTestStorage(t, nil)
+	for _, userId := range []uint{1, 2, 3} {
+		mustTopUp(t, s, userId, 100)
+	}
+	tournament := mustAnnounce(t, s, &types.AnnounceTournamentRequest{Deposit: 101, TeamSize: 2})
+	first := mustCreateTeam(t, s, 1, &types.TeamMemberRequest{UserId: 1, Share: 50}, &types.TeamMemberRequest{UserId: 2, Share: 50})
+	second := mustCreateTeam(t, s, 3, &types.TeamMemberRequest{UserId: 3, Share: 60}, &types.TeamMemberRequest{UserId: 2, Share: 40})
+	trio := mustCreateTeam(t, s, 4, &types.TeamMemberRequest{UserId: 4, Share: 34},
+		&types.TeamMemberRequest{UserId: 5, Share: 33}, &types.TeamMemberRequest{UserId: 6, Share: 33})
+
+	if err := s.JoinTournamentByTeam(&types.TeamEntryRequest{TournamentId: tournament.ID, TeamId: first.ID}); err != nil {
+		t.Fatal(err)
+	}
+	// Captain covers indivisible remainder, holds belong to the captain's entry
+	expectBalance(t, s, 1, 100, 51)
+	expectBalance(t, s, 2, 100, 50)
+	holds := []*types.BalanceHold{}
+	s.db.Where(&types.BalanceHold{TournamentId: tournament.ID, PlayerId: 1}).Find(&holds)
+	if len(holds) != 2 {
+		t.Errorf("got %d holds of captain's entry, want 2", len(holds))
+	}
+
+	rejected := map[string]*types.TeamEntryRequest{
+		"Team already participates tournament!":                    {TournamentId: tournament.ID, TeamId: first.ID},
+		"One or more team members already participate tournament!": {TournamentId: tournament.ID, TeamId: second.ID},
+		"Team size does not match tournament team size!":           {TournamentId: tournament.ID, TeamId: trio.ID},
+	}
+	for want, request := range rejected {
+		if err := s.JoinTournamentByTeam(request); err == nil || err.Error() != want {
+			t.Errorf("team %d got error %v, want %q", request.TeamId, err, want)
+		}
+	}
+	if err := s.JoinTournamentAndTakePointsFromUserBalances(&types.JoinTournamentRequest{TournamentId: tournament.ID, PlayerId: 3}); err == nil {
+		t.Error("player joined team tournament alone")
+	}
+
+	// Withdrawal frees member 2 to enter along with another team
+	if err := s.WithdrawTeamFromTournament(&types.TeamEntryRequest{TournamentId: tournament.ID, TeamId: first.ID}); err != nil {
+		t.Fatal(err)
+	}
+	expectBalance(t, s, 1, 100, 0)
+	expectBalance(t, s, 2, 100, 0)
+	if err := s.JoinTournamentByTeam(&types.TeamEntryRequest{TournamentId: tournament.ID, TeamId: second.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Team prize is split by shares fixed as the team entered
+	if err := s.CheckAndSpreadTournamentPrize(&types.ResultTournamentRequest{TournamentId: tournament.ID,
+		Winners: []*types.TournamentWinnerRequest{{TeamId: second.ID, Prize: 101}}}); err != nil {
+		t.Fatal(err)
+	}
+	expectBalance(t, s, 3, 100, 0)
+	expectBalance(t, s, 2, 100, 0)
+	winners, _ := s.FetchTournamentWinners([]uint{tournament.ID})
+	prizes := map[uint]int{}
+	for _, winner := range winners.([]*types.TournamentWinner) {
+		if winner.TeamId != second.ID {
+			t.Errorf("got winner %+v, want member of team %d", winner, second.ID)
+		}
+		prizes[winner.UserId] = winner.Prize
+	}
+	if !reflect.DeepEqual(prizes, map[uint]int{3: 61, 2: 40}) {
+		t.Errorf("got prizes %v, want 61 and 40", prizes)
+	}
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Fetches page of user tickets, newest first
+func (s *Storage) FetchTickets(query *types.TicketsQuery) (interface{}, error) {
+	var (
+		tickets []*types.Ticket
+		total   int
+		err     error
+	)
+	db := s.db.Model(&types.Ticket{}).Where(&types.Ticket{UserId: query.UserId, State: query.State})
+	if err = db.Count(&total).Error; err != nil {
+		return nil, errors.New("An error occured during tickets counting")
+	}
+	tickets = []*types.Ticket{}
+	if err = db.Order("id DESC").Limit(query.Limit).Offset(query.Offset).Find(&tickets).Error; err != nil {
+		return nil, errors.New("An error occured during tickets fetching")
+	}
+	return &types.TicketsPage{
+		Tickets: tickets,
+		Total:   total,
+	}, nil
+}
+
+//Checks satellite being announced awards tickets to tournament still open for registration
+//which starts after the satellite
+func (s *Storage) checkSatelliteTarget(request *types.AnnounceTournamentRequest) error {
+	target := &types.Tournament{}
+	if s.db.First(target, request.TicketTournamentId).RecordNotFound() {
+		return errors.New(`Ticket tournament not found!`)
+	}
+	if target.State != types.TOURNAMENT_STATE_OPEN || target.RegistrationClosedAt != nil {
+		return errors.New(`Ticket tournament registration already closed!`)
+	}
+	if !request.Date.IsZero() && !request.Date.Before(target.Date) {
+		return errors.New(`Satellite must start before its ticket tournament!`)
+	}
+	return nil
+}
+
+//Issues ticket to satellite's ticket tournament to its winner, the ticket is refunded at once
+//if the tournament could not be joined anymore
+func (s *Storage) issueTicket(tx *gorm.DB, satellite *types.Tournament, userId uint) (*types.Ticket, error) {
+	target := &types.Tournament{}
+	if err := tx.First(target, satellite.TicketTournamentId).Error; err != nil {
+		return nil, err
+	}
+	ticket := &types.Ticket{
+		UserId:       userId,
+		SatelliteId:  satellite.ID,
+		TournamentId: target.ID,
+		Value:        target.Deposit,
+		Currency:     target.Currency,
+		State:        types.TICKET_STATE_ISSUED,
+		ExpiresAt:    target.Date,
+	}
+	if err := tx.Create(ticket).Error; err != nil {
+		return nil, err
+	}
+	if target.State != types.TOURNAMENT_STATE_OPEN || target.RegistrationClosedAt != nil {
+		return ticket, s.refundTicket(tx, ticket, "its tournament registration is closed")
+	}
+	return ticket, tx.Create(
+		&types.Notification{
+			UserId:  userId,
+			Kind:    types.NOTIFICATION_KIND_TICKET,
+			Message: fmt.Sprintf("Ticket #%d to tournament %d won in satellite %d", ticket.ID, target.ID, satellite.ID),
+		}).Error
+}
+
+//Enters player to locked tournament by ticket of request in place of deposits, the seat is marked by the ticket
+//as nothing is debited for it, so it's not counted in deposits collected by tournament.
+//Returns types.ErrEntryInvalidTicket unless it's player's issued ticket to the tournament
+func (s *Storage) joinByTicket(tx *gorm.DB, tournament *types.Tournament, request *types.JoinTournamentRequest) error {
+	ticket := &types.Ticket{}
+	if tx.Set("gorm:query_option", "FOR UPDATE").First(ticket, request.TicketId).RecordNotFound() ||
+		ticket.UserId != request.PlayerId || ticket.TournamentId != tournament.ID ||
+		ticket.State != types.TICKET_STATE_ISSUED || !time.Now().Before(ticket.ExpiresAt) {
+		return types.ErrEntryInvalidTicket
+	}
+	// Player gets a wallet of tournament currency to be paid prizes to
+	if err := s.ensureWallet(tx, request.PlayerId, tournament.Currency); err != nil {
+		return err
+	}
+	if err := tx.Create(
+		&types.TournamentPlayer{
+			TournamentId: tournament.ID,
+			UserId:       request.PlayerId,
+			UserDeposit:  ticket.Value,
+			TicketId:     ticket.ID,
+		}).Error; err != nil {
+		return err
+	}
+	return tx.Model(ticket).UpdateColumns(map[string]interface{}{"state": types.TICKET_STATE_USED, "used_at": time.Now()}).Error
+}
+
+//Issues ticket player joined tournament by again as the entry is withdrawn,
+//returns false if the entry is not paid by a ticket
+func (s *Storage) restoreTicket(tx *gorm.DB, tournamentId uint, playerId uint) (bool, error) {
+	ticket := &types.Ticket{}
+	if tx.Set("gorm:query_option", "FOR UPDATE").
+		Where(&types.Ticket{TournamentId: tournamentId, UserId: playerId, State: types.TICKET_STATE_USED}).First(ticket).RecordNotFound() {
+		return false, nil
+	}
+	return true, tx.Model(ticket).UpdateColumns(map[string]interface{}{"state": types.TICKET_STATE_ISSUED, "used_at": nil}).Error
+}
+
+//Refunds every issued or used ticket of tournament being cancelled
+func (s *Storage) refundTournamentTickets(tx *gorm.DB, tournament *types.Tournament) error {
+	tickets := []*types.Ticket{}
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("tournament_id = ? AND state IN (?)", tournament.ID, []string{types.TICKET_STATE_ISSUED, types.TICKET_STATE_USED}).
+		Order("id").Find(&tickets).Error; err != nil {
+		return err
+	}
+	for _, ticket := range tickets {
+		if err := s.refundTicket(tx, ticket, "its tournament is cancelled"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Credits ticket value to its holder by "ticket_refund" operation notifying the holder why
+func (s *Storage) refundTicket(tx *gorm.DB, ticket *types.Ticket, reason string) error {
+	if ticket.Value > 0 {
+		if err := s.creditTournamentWallet(tx, ticket.UserId, ticket.Currency, ticket.Value, types.OPERATION_KIND_TICKET_REFUND, ticket.TournamentId); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	ticket.State, ticket.RefundedAt = types.TICKET_STATE_REFUNDED, &now
+	if err := tx.Model(ticket).UpdateColumns(map[string]interface{}{"state": ticket.State, "refunded_at": now}).Error; err != nil {
+		return err
+	}
+	return tx.Create(
+		&types.Notification{
+			UserId:   ticket.UserId,
+			Kind:     types.NOTIFICATION_KIND_TICKET,
+			Currency: ticket.Currency,
+			Amount:   ticket.Value,
+			Message:  fmt.Sprintf("Ticket #%d refunded by %d %s as %s", ticket.ID, ticket.Value, ticket.Currency, reason),
+		}).Error
+}
+
+//Refunds or expires, as their satellites say, tickets not used by now,
+//returns number of tickets processed
+func (s *Storage) ExpireTickets(now time.Time) (int, error) {
+	var ids []uint
+	if err := s.db.Model(&types.Ticket{}).Where("state = ? AND expires_at <= ?", types.TICKET_STATE_ISSUED, now).
+		Order("id").Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	expired := 0
+	for _, id := range ids {
+		if err := s.expireTicket(id, now); err != nil {
+			s.logger.Printf("Could not expire ticket %d: %s", id, err.Error())
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (s *Storage) expireTicket(id uint, now time.Time) (err error) {
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	ticket := &types.Ticket{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").First(ticket, id).Error; err != nil {
+		return err
+	}
+	// Ticket is used or withdrawn meanwhile
+	if ticket.State != types.TICKET_STATE_ISSUED || ticket.ExpiresAt.After(now) {
+		return tx.Commit().Error
+	}
+	satellite := &types.Tournament{}
+	if err = tx.Unscoped().First(satellite, ticket.SatelliteId).Error; err != nil {
+		return err
+	}
+	if satellite.TicketRefundable {
+		err = s.refundTicket(tx, ticket, "it expired unused")
+	} else {
+		err = s.expireTicketWorthless(tx, ticket)
+	}
+	if err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) expireTicketWorthless(tx *gorm.DB, ticket *types.Ticket) error {
+	if err := tx.Model(ticket).UpdateColumn("state", types.TICKET_STATE_EXPIRED).Error; err != nil {
+		return err
+	}
+	return tx.Create(
+		&types.Notification{
+			UserId:  ticket.UserId,
+			Kind:    types.NOTIFICATION_KIND_TICKET,
+			Message: fmt.Sprintf("Ticket #%d to tournament %d expired unused", ticket.ID, ticket.TournamentId),
+		}).Error
+}
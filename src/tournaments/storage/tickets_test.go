@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Announces tournament of deposit 50 along with satellite awarding tickets to it, refundable ones if refundable is true,
+//and issues ticket to user 1
+func satelliteTicket(t *testing.T, refundable bool) (*Storage, *types.Tournament, *types.Ticket) {
+	t.Helper()
+	s := newTestStorage(t, nil)
+	target := mustAnnounce(t, s, &types.AnnounceTournamentRequest{Deposit: 50, Date: time.Now().Add(2 * time.Hour)})
+	satellite := mustAnnounce(t, s, &types.AnnounceTournamentRequest{TicketTournamentId: target.ID, Tickets: 1, TicketRefundable: refundable})
+	ticket, err := s.issueTicket(s.db, satellite, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, target, ticket
+}
+
+func ticketState(t *testing.T, s *Storage, ticketId uint) string {
+	t.Helper()
+	ticket := &types.Ticket{}
+	if err := s.db.First(ticket, ticketId).Error; err != nil {
+		t.Fatal(err)
+	}
+	return ticket.State
+}
+
+func TestJoinByTicket(t *testing.T) {
+	t.Run("ticket pays the entry once", func(t *testing.T) {
+		s, target, ticket := satelliteTicket(t, false)
+		mustJoin(t, s, &types.JoinTournamentRequest{TournamentId: target.ID, PlayerId: 1, TicketId: ticket.ID})
+		if state := ticketState(t, s, ticket.ID); state != types.TICKET_STATE_USED {
+			t.Errorf("got ticket %s, want used", state)
+		}
+		// Wallet is created for prizes, nothing is held
+		expectBalance(t, s, 1, 0, 0)
+		players, _ := s.FetchTournamentPlayers([]uint{target.ID})
+		if player := players.([]*types.TournamentPlayer)[0]; player.TicketId != ticket.ID || player.UserDeposit != 50 {
+			t.Errorf("got player %+v, want seat marked by ticket", player)
+		}
+		err := s.JoinTournamentAndTakePointsFromUserBalances(&types.JoinTournamentRequest{TournamentId: target.ID, PlayerId: 1, TicketId: ticket.ID})
+		if err == nil {
+			t.Error("joined twice by the ticket")
+		}
+	})
+	t.Run("someone else's ticket is invalid", func(t *testing.T) {
+		s, target, ticket := satelliteTicket(t, false)
+		err := s.JoinTournamentAndTakePointsFromUserBalances(&types.JoinTournamentRequest{TournamentId: target.ID, PlayerId: 2, TicketId: ticket.ID})
+		if err != types.ErrEntryInvalidTicket {
+			t.Errorf("got error %v, want %v", err, types.ErrEntryInvalidTicket)
+		}
+	})
+	t.Run("withdrawal issues the ticket again", func(t *testing.T) {
+		s, target, ticket := satelliteTicket(t, false)
+		mustJoin(t, s, &types.JoinTournamentRequest{TournamentId: target.ID, PlayerId: 1, TicketId: ticket.ID})
+		if err := s.WithdrawFromTournament(&types.WithdrawTournamentRequest{TournamentId: target.ID, PlayerId: 1}); err != nil {
+			t.Fatal(err)
+		}
+		if state := ticketState(t, s, ticket.ID); state != types.TICKET_STATE_ISSUED {
+			t.Errorf("got ticket %s, want issued", state)
+		}
+		mustJoin(t, s, &types.JoinTournamentRequest{TournamentId: target.ID, PlayerId: 1, TicketId: ticket.ID})
+	})
+}
+
+func TestRefundTicket(t *testing.T) {
+	t.Run("used ticket is refunded as its tournament is cancelled", func(t *testing.T) {
+		s, target, ticket := satelliteTicket(t, false)
+		mustJoin(t, s, &types.JoinTournamentRequest{TournamentId: target.ID, PlayerId: 1, TicketId: ticket.ID})
+		if err := s.CancelTournament(target.ID); err != nil {
+			t.Fatal(err)
+		}
+		if state := ticketState(t, s, ticket.ID); state != types.TICKET_STATE_REFUNDED {
+			t.Errorf("got ticket %s, want refunded", state)
+		}
+		expectBalance(t, s, 1, 50, 0)
+	})
+	t.Run("ticket won after registration closed is refunded at once", func(t *testing.T) {
+		s, target, _ := satelliteTicket(t, false)
+		if err := s.CloseTournamentRegistration(target.ID); err != nil {
+			t.Fatal(err)
+		}
+		satellite := &types.Tournament{}
+		s.db.Where(&types.Tournament{TicketTournamentId: target.ID}).First(satellite)
+		ticket, err := s.issueTicket(s.db, satellite, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ticket.State != types.TICKET_STATE_REFUNDED {
+			t.Errorf("got ticket %s, want refunded", ticket.State)
+		}
+		expectBalance(t, s, 2, 50, 0)
+	})
+	for _, refundable := range []bool{true, false} {
+		state, balance := types.TICKET_STATE_EXPIRED, 0
+		if refundable {
+			state, balance = types.TICKET_STATE_REFUNDED, 50
+		}
+		t.Run("unused ticket gets "+state+" as it expires", func(t *testing.T) {
+			s, _, ticket := satelliteTicket(t, refundable)
+			expired, err := s.ExpireTickets(ticket.ExpiresAt)
+			if err != nil || expired != 1 {
+				t.Fatalf("got %d tickets expired, error %v, want 1", expired, err)
+			}
+			if actual := ticketState(t, s, ticket.ID); actual != state {
+				t.Errorf("got ticket %s, want %s", actual, state)
+			}
+			if actual, err := s.FetchBalance(1, types.CURRENCY_POINTS); (err == nil) != refundable ||
+				(refundable && actual.(*types.UserPointsBalance).Balance != balance) {
+				t.Errorf("got wallet %+v, want balance %d", actual, balance)
+			}
+		})
+	}
+}
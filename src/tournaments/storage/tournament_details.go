@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+//Collects tournament players with their deposits, players' backers with stakes, teams with members' deposits
+//and winners with prize payouts per stakeholder.
+//Deposits are totalled by their holds: held ones are not collected yet, captured ones are;
+//entries having no holds were debited at once before holds were introduced.
+//Payouts are computed the same way CheckAndSpreadTournamentPrize spreads prize:
+//equally between winner and his backers; members of winning teams are winners of their parts of team prize
+func (s *Storage) FetchTournamentDetails(id uint) (interface{}, error) {
+	var (
+		tournament *types.Tournament
+		players    []*types.TournamentPlayer
+		backers    []*types.TournamentBacker
+		winners    []*types.TournamentWinner
+		teams      []*types.TournamentTeam
+		members    []*types.TournamentTeamMember
+		holds      []*types.BalanceHold
+		err        error
+	)
+	tournament = &types.Tournament{}
+	if err = s.db.First(tournament, id).Error; err != nil {
+		return nil, errors.New("An error occured during tournament fetching")
+	}
+	players = []*types.TournamentPlayer{}
+	if err = s.db.Where(&types.TournamentPlayer{TournamentId: id}).Order("id").Find(&players).Error; err != nil {
+		return nil, errors.New("An error occured during tournament players fetching")
+	}
+	backers = []*types.TournamentBacker{}
+	if err = s.db.Where(&types.TournamentBacker{TournamentId: id}).Order("id").Find(&backers).Error; err != nil {
+		return nil, errors.New("An error occured during tournament backers fetching")
+	}
+	winners = []*types.TournamentWinner{}
+	if err = s.db.Where(&types.TournamentWinner{TournamentId: id}).Order("id").Find(&winners).Error; err != nil {
+		return nil, errors.New("An error occured during tournament winners fetching")
+	}
+
+	teams = []*types.TournamentTeam{}
+	if err = s.db.Where(&types.TournamentTeam{TournamentId: id}).Order("id").Find(&teams).Error; err != nil {
+		return nil, errors.New("An error occured during tournament teams fetching")
+	}
+	members = []*types.TournamentTeamMember{}
+	if err = s.db.Where(&types.TournamentTeamMember{TournamentId: id}).Order("user_id").Find(&members).Error; err != nil {
+		return nil, errors.New("An error occured during tournament team members fetching")
+	}
+
+	holds = []*types.BalanceHold{}
+	if err = s.db.Where("tournament_id = ?", id).Find(&holds).Error; err != nil {
+		return nil, errors.New("An error occured during tournament holds fetching")
+	}
+
+	details := &types.TournamentDetails{
+		Tournament: tournament,
+		Players:    []*types.TournamentPlayerDetails{},
+		Winners:    []*types.TournamentWinnerDetails{},
+	}
+	// Entries of players having holds of any state, the rest are debited at once
+	heldEntries := map[uint]bool{}
+	for _, hold := range holds {
+		heldEntries[hold.PlayerId] = true
+		switch hold.State {
+		case types.HOLD_STATE_HELD:
+			details.TotalHeld += hold.Amount
+		case types.HOLD_STATE_CAPTURED:
+			details.TotalDeposits += hold.Amount
+		}
+	}
+	backersByPlayer := map[uint][]*types.TournamentBackerDetails{}
+	for _, backer := range backers {
+		backersByPlayer[backer.UserId] = append(backersByPlayer[backer.UserId], &types.TournamentBackerDetails{
+			BackerId: backer.BackerId,
+			Deposit:  backer.BackerDeposit,
+		})
+		if !heldEntries[backer.UserId] {
+			details.TotalDeposits += backer.BackerDeposit
+		}
+	}
+	for _, player := range players {
+		playerBackers := backersByPlayer[player.UserId]
+		if playerBackers == nil {
+			playerBackers = []*types.TournamentBackerDetails{}
+		}
+		details.Players = append(details.Players, &types.TournamentPlayerDetails{
+			UserId:   player.UserId,
+			Deposit:  player.UserDeposit,
+			Backers:  playerBackers,
+			TicketId: player.TicketId,
+		})
+		if player.TicketId == 0 && !heldEntries[player.UserId] {
+			details.TotalDeposits += player.UserDeposit
+		}
+	}
+	membersByTeam := map[uint][]*types.TournamentTeamMember{}
+	for _, member := range members {
+		membersByTeam[member.TeamId] = append(membersByTeam[member.TeamId], member)
+	}
+	for _, team := range teams {
+		teamMembers := membersByTeam[team.TeamId]
+		if teamMembers == nil {
+			teamMembers = []*types.TournamentTeamMember{}
+		}
+		details.Teams = append(details.Teams, &types.TournamentTeamDetails{
+			TeamId:    team.TeamId,
+			CaptainId: team.CaptainId,
+			Deposit:   team.Deposit,
+			Members:   teamMembers,
+		})
+	}
+	for _, winner := range winners {
+		stake := winner.Prize / (len(backersByPlayer[winner.UserId]) + 1)
+		payouts := []*types.TournamentPayout{{UserId: winner.UserId, Points: stake}}
+		for _, backer := range backersByPlayer[winner.UserId] {
+			payouts = append(payouts, &types.TournamentPayout{UserId: backer.BackerId, Points: stake})
+		}
+		details.Winners = append(details.Winners, &types.TournamentWinnerDetails{
+			UserId:  winner.UserId,
+			Prize:   winner.Prize,
+			Payouts: payouts,
+		})
+		details.TotalPrizes += winner.Prize
+	}
+	return details, nil
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Seats taken in tournament, by teams in team tournaments && by players in others
+const TOURNAMENT_ENTRIES_COUNT_QUERY = `(CASE WHEN tournaments.team_size > 0
+	THEN (SELECT COUNT(*) FROM tournament_teams WHERE tournament_teams.tournament_id = tournaments.id)
+	ELSE (SELECT COUNT(*) FROM tournament_players WHERE tournament_players.tournament_id = tournaments.id AND tournament_players.deleted_at IS NULL) END)`
+
+//Fetches tournaments page matching query filters, sorted by query.Sort (id by default)
+//and paginated by query.Cursor if any, by query.Offset otherwise.
+//Page contains total count of matching tournaments && cursors of adjacent pages
+func (s *Storage) SearchTournaments(query *types.TournamentsQuery) (interface{}, error) {
+	var (
+		tournaments []*types.Tournament
+		total       int
+		err         error
+	)
+	sort := query.Sort
+	if sort == "" {
+		sort = "id"
+	}
+	descending := strings.HasPrefix(sort, "-")
+	column, ok := types.TournamentSortFields[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return nil, errors.New("Incorrect tournaments sort")
+	}
+
+	filtered := s.filterTournaments(s.db.Model(&types.Tournament{}), query)
+	if err = filtered.Count(&total).Error; err != nil {
+		return nil, errors.New("An error occured during tournaments counting")
+	}
+
+	paginated := filtered
+	backwards := query.Cursor != nil && query.Cursor.Direction == types.CURSOR_DIRECTION_PREV
+	if query.Cursor != nil {
+		if query.Cursor.Sort != sort {
+			return nil, errors.New("Cursor does not match sort")
+		}
+		value, err := cursorValue(column, query.Cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+		// Keyset condition: rows after cursor in walking direction
+		comparison := ">"
+		if descending != backwards {
+			comparison = "<"
+		}
+		paginated = paginated.Where("("+column+", id) "+comparison+" (?, ?)", value, query.Cursor.ID)
+	} else if query.Offset > 0 {
+		paginated = paginated.Offset(query.Offset)
+	}
+	direction := "ASC"
+	if descending != backwards {
+		direction = "DESC"
+	}
+	tournaments = []*types.Tournament{}
+	if err = paginated.Order(column + " " + direction).Order("id " + direction).Limit(query.Limit + 1).Find(&tournaments).Error; err != nil {
+		return nil, errors.New("An error occured during tournaments fetching")
+	}
+
+	hasMore := len(tournaments) > query.Limit
+	if hasMore {
+		tournaments = tournaments[:query.Limit]
+	}
+	if backwards {
+		for i, j := 0, len(tournaments)-1; i < j; i, j = i+1, j-1 {
+			tournaments[i], tournaments[j] = tournaments[j], tournaments[i]
+		}
+	}
+
+	page := &types.TournamentsPage{
+		Tournaments: tournaments,
+		Total:       total,
+	}
+	if len(tournaments) == 0 {
+		return page, nil
+	}
+	hasNext, hasPrev := hasMore, query.Offset > 0
+	if query.Cursor != nil {
+		hasNext, hasPrev = backwards || hasMore, !backwards || hasMore
+	}
+	if hasNext {
+		page.NextCursor = types.EncodeCursor(tournamentCursor(tournaments[len(tournaments)-1], sort, types.CURSOR_DIRECTION_NEXT))
+	}
+	if hasPrev {
+		page.PrevCursor = types.EncodeCursor(tournamentCursor(tournaments[0], sort, types.CURSOR_DIRECTION_PREV))
+	}
+	return page, nil
+}
+
+func (s *Storage) filterTournaments(db *gorm.DB, query *types.TournamentsQuery) *gorm.DB {
+	if query.State != nil {
+		db = db.Where("state = ?", *query.State)
+	}
+	if query.GameId > 0 {
+		db = db.Where("game_id = ?", query.GameId)
+	}
+	if !query.DateFrom.IsZero() {
+		db = db.Where("date >= ?", query.DateFrom)
+	}
+	if !query.DateTo.IsZero() {
+		db = db.Where("date < ?", query.DateTo)
+	}
+	if query.HasFreeSeats != nil {
+		if *query.HasFreeSeats {
+			db = db.Where("max_players = 0 OR " + TOURNAMENT_ENTRIES_COUNT_QUERY + " < max_players")
+		} else {
+			db = db.Where("max_players > 0 AND " + TOURNAMENT_ENTRIES_COUNT_QUERY + " >= max_players")
+		}
+	}
+	if query.PlayerId > 0 {
+		db = db.Where("EXISTS (SELECT 1 FROM tournament_players WHERE tournament_players.tournament_id = tournaments.id AND tournament_players.user_id = ? AND tournament_players.deleted_at IS NULL)"+
+			" OR EXISTS (SELECT 1 FROM tournament_team_members WHERE tournament_team_members.tournament_id = tournaments.id AND tournament_team_members.user_id = ?)",
+			query.PlayerId, query.PlayerId)
+	}
+	return db
+}
+
+func tournamentCursor(tournament *types.Tournament, sort string, direction string) *types.TournamentsCursor {
+	var value interface{}
+	switch strings.TrimPrefix(sort, "-") {
+	case "date":
+		value = tournament.Date.Format(time.RFC3339Nano)
+	case "deposit":
+		value = tournament.Deposit
+	case "created_at":
+		value = tournament.CreatedAt.Format(time.RFC3339Nano)
+	default:
+		value = tournament.ID
+	}
+	return &types.TournamentsCursor{
+		Sort:      sort,
+		Value:     value,
+		ID:        tournament.ID,
+		Direction: direction,
+	}
+}
+
+//Converts cursor value decoded from JSON to sort column type
+func cursorValue(column string, value interface{}) (interface{}, error) {
+	switch column {
+	case "date", "created_at":
+		if formatted, ok := value.(string); ok {
+			if parsed, err := time.Parse(time.RFC3339Nano, formatted); err == nil {
+				return parsed, nil
+			}
+		}
+	default:
+		if number, ok := value.(float64); ok {
+			return int64(number), nil
+		}
+	}
+	return nil, errors.New("Incorrect cursor value")
+}
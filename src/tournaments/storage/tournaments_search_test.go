@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+func TestTournamentCursorValue(t *testing.T) {
+	date := time.Date(2018, 3, 10, 18, 0, 0, 500, time.UTC)
+	tournament := &types.Tournament{ID: 7, CreatedAt: date.Add(-time.Hour), Date: date, Deposit: 100}
+
+	cases := []struct {
+		sort  string
+		value interface{}
+	}{
+		{"id", int64(7)},
+		{"-id", int64(7)},
+		{"deposit", int64(100)},
+		{"date", date},
+		{"-created_at", date.Add(-time.Hour)},
+	}
+	for _, c := range cases {
+		t.Run(c.sort, func(t *testing.T) {
+			encoded := types.EncodeCursor(tournamentCursor(tournament, c.sort, types.CURSOR_DIRECTION_NEXT))
+			cursor, err := types.DecodeCursor(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cursor.Sort != c.sort || cursor.ID != 7 || cursor.Direction != types.CURSOR_DIRECTION_NEXT {
+				t.Fatalf("got cursor %+v", cursor)
+			}
+			value, err := cursorValue(types.TournamentSortFields[strings.TrimPrefix(c.sort, "-")], cursor.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(value, c.value) {
+				t.Errorf("got value %v, want %v", value, c.value)
+			}
+		})
+	}
+}
+
+func TestCursorValueIncorrect(t *testing.T) {
+	cases := []struct {
+		column string
+		value  interface{}
+	}{
+		{"date", "10.03.2018"},
+		{"date", float64(1520704800)},
+		{"created_at", nil},
+		{"deposit", "100"},
+		{"id", nil},
+	}
+	for _, c := range cases {
+		if _, err := cursorValue(c.column, c.value); err == nil || err.Error() != "Incorrect cursor value" {
+			t.Errorf("cursorValue(%q, %v) got error %v", c.column, c.value, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+type TransfersConf struct {
+	// Sum of amounts every user may send per day (UTC) in each currency unless set by SetTransferLimit, 0 for unlimited
+	DailyLimit int
+	// Fee in basis points (1/100 of percent) of transferred amount
+	FeeBasisPoints int
+	// Fee charged if percentage one is less
+	MinFee int
+	// User collecting fees && funding overlays of guaranteed prize pools, fees are not charged if 0
+	HouseUserId uint
+}
+
+func (c *TransfersConf) fee(amount int) int {
+	if c.HouseUserId == 0 {
+		return 0
+	}
+	fee := amount * c.FeeBasisPoints / 10000
+	if fee < c.MinFee {
+		fee = c.MinFee
+	}
+	return fee
+}
+
+//Moves request.Amount points from sender to recipient wallet of request.Currency charging the house fee
+//in the same currency from sender spending sender's points expiring soonest first, checks sender's daily limit of the currency;
+//writes ledger operations for both legs of transfer and fee
+func (s *Storage) TransferPoints(request *types.TransferRequest) (result interface{}, err error) {
+	var (
+		balances  []*types.UserPointsBalance
+		limit     *types.TransferLimit
+		sentToday struct {
+			Sum int
+		}
+	)
+	transfer := &types.Transfer{
+		FromUserId: request.FromUserId,
+		ToUserId:   request.ToUserId,
+		Amount:     request.Amount,
+		Fee:        s.transfersConf.fee(request.Amount),
+		Currency:   types.CurrencyOrDefault(request.Currency),
+		Comment:    request.Comment,
+	}
+	stakeholderIds := []uint{request.FromUserId, request.ToUserId}
+	if transfer.Fee > 0 {
+		stakeholderIds = append(stakeholderIds, s.transfersConf.HouseUserId)
+	}
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	if transfer.Fee > 0 {
+		if err = s.ensureWallet(tx, s.transfersConf.HouseUserId, transfer.Currency); err != nil {
+			return nil, err
+		}
+	}
+	// Lock balances in the same order in every transfer to avoid deadlocks
+	balances = []*types.UserPointsBalance{}
+	if err = tx.Set("gorm:query_option", "FOR UPDATE").Where("user_id IN (?) AND currency = ?", stakeholderIds, transfer.Currency).Order("user_id").Find(&balances).Error; err != nil {
+		return nil, err
+	}
+	var sender *types.UserPointsBalance
+	recipientFound := false
+	for _, balance := range balances {
+		if balance.UserId == request.FromUserId {
+			sender = balance
+		}
+		if balance.UserId == request.ToUserId {
+			recipientFound = true
+		}
+	}
+	if sender == nil || !recipientFound {
+		err = errors.New("Sender or recipient has no balance")
+		return nil, err
+	}
+	if sender.Available < transfer.Amount+transfer.Fee {
+		err = errors.New("Not enough points in sender balance")
+		return nil, err
+	}
+
+	dailyLimit := s.transfersConf.DailyLimit
+	limit = &types.TransferLimit{}
+	found := tx.Where(&types.TransferLimit{UserId: request.FromUserId}).First(limit)
+	if found.Error != nil && !found.RecordNotFound() {
+		err = found.Error
+		return nil, err
+	}
+	if !found.RecordNotFound() {
+		dailyLimit = limit.DailyLimit
+	}
+	if dailyLimit > 0 {
+		if err = tx.Raw(`SELECT COALESCE(SUM(amount), 0) AS sum FROM transfers WHERE from_user_id = ? AND currency = ? AND created_at >= ?`,
+			request.FromUserId, transfer.Currency, time.Now().UTC().Truncate(24*time.Hour)).Scan(&sentToday).Error; err != nil {
+			return nil, err
+		}
+		if sentToday.Sum+transfer.Amount > dailyLimit {
+			err = errors.New("Sender's daily transfer limit exceeded")
+			return nil, err
+		}
+	}
+
+	if err = tx.Create(transfer).Error; err != nil {
+		return nil, err
+	}
+	if err = s.consumeLots(tx, transfer.FromUserId, transfer.Currency, transfer.Amount+transfer.Fee, 0); err != nil {
+		return nil, err
+	}
+	operations := []*UserPointsOperations{
+		{UserId: transfer.FromUserId, OperationType: USER_POINTS_OPERATION_CREDIT, Kind: types.OPERATION_KIND_TRANSFER, Sum: transfer.Amount},
+		{UserId: transfer.ToUserId, OperationType: USER_POINTS_OPERATION_DEBT, Kind: types.OPERATION_KIND_TRANSFER, Sum: transfer.Amount},
+	}
+	if transfer.Fee > 0 {
+		operations = append(operations,
+			&UserPointsOperations{UserId: transfer.FromUserId, OperationType: USER_POINTS_OPERATION_CREDIT, Kind: types.OPERATION_KIND_FEE, Sum: transfer.Fee},
+			&UserPointsOperations{UserId: s.transfersConf.HouseUserId, OperationType: USER_POINTS_OPERATION_DEBT, Kind: types.OPERATION_KIND_FEE, Sum: transfer.Fee},
+		)
+	}
+	for _, operation := range operations {
+		operation.TransferId = transfer.ID
+		operation.Currency = transfer.Currency
+		if err = tx.Create(operation).Error; err != nil {
+			return nil, err
+		}
+		expression := `balance + ?`
+		if operation.OperationType == USER_POINTS_OPERATION_CREDIT {
+			expression = `balance - ?`
+		}
+		if err = tx.Model(&types.UserPointsBalance{}).Where(&types.UserPointsBalance{UserId: operation.UserId, Currency: operation.Currency}).
+			UpdateColumn(`balance`, gorm.Expr(expression, operation.Sum)).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+//Fetches page of transfers sent and/or received by user, newest first
+func (s *Storage) FetchTransfers(query *types.TransfersQuery) (interface{}, error) {
+	var (
+		transfers []*types.Transfer
+		total     int
+		err       error
+	)
+	db := s.db.Model(&types.Transfer{})
+	switch query.Direction {
+	case types.TRANSFER_DIRECTION_IN:
+		db = db.Where("to_user_id = ?", query.UserId)
+	case types.TRANSFER_DIRECTION_OUT:
+		db = db.Where("from_user_id = ?", query.UserId)
+	default:
+		db = db.Where("from_user_id = ? OR to_user_id = ?", query.UserId, query.UserId)
+	}
+	if err = db.Count(&total).Error; err != nil {
+		return nil, errors.New("An error occured during transfers counting")
+	}
+	transfers = []*types.Transfer{}
+	if err = db.Order("id DESC").Limit(query.Limit).Offset(query.Offset).Find(&transfers).Error; err != nil {
+		return nil, errors.New("An error occured during transfers fetching")
+	}
+	return &types.TransfersPage{
+		Transfers: transfers,
+		Total:     total,
+	}, nil
+}
+
+//Sets user's daily transfers limit overriding TransfersConf.DailyLimit
+func (s *Storage) SetTransferLimit(request *types.TransferLimitRequest) (interface{}, error) {
+	limit := &types.TransferLimit{}
+	if err := s.db.Where(&types.TransferLimit{UserId: request.UserId}).
+		Assign(map[string]interface{}{"daily_limit": request.DailyLimit}).
+		FirstOrCreate(limit).Error; err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
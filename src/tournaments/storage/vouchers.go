@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+// Length of random part of generated voucher codes and batch IDs
+const VOUCHER_CODE_LENGTH = 10
+
+//Creates voucher having request.Code or generated one if it's empty
+func (s *Storage) CreateVoucher(request *types.VoucherRequest) (interface{}, error) {
+	voucher, err := newVoucher(request, "")
+	if err != nil {
+		return nil, err
+	}
+	if request.Code != "" && !s.db.Where(&types.Voucher{Code: voucher.Code}).First(&types.Voucher{}).RecordNotFound() {
+		return nil, errors.New("Voucher code already exists")
+	}
+	if err = s.db.Create(voucher).Error; err != nil {
+		return nil, err
+	}
+	return voucher, nil
+}
+
+//Creates request.Count vouchers like request.Voucher having codes made of request.Prefix and random part,
+//all of them or none
+func (s *Storage) CreateVoucherBatch(request *types.VoucherBatchRequest) (result interface{}, err error) {
+	batchId, err := randomCode(VOUCHER_CODE_LENGTH)
+	if err != nil {
+		return nil, err
+	}
+	batch := &types.VoucherBatch{
+		BatchId:  batchId,
+		Vouchers: make([]*types.Voucher, 0, request.Count),
+	}
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	for i := 0; i < request.Count; i++ {
+		var voucher *types.Voucher
+		if voucher, err = newVoucher(&request.Voucher, types.NormalizeVoucherCode(request.Prefix)); err != nil {
+			return nil, err
+		}
+		voucher.BatchId = batchId
+		if err = tx.Create(voucher).Error; err != nil {
+			return nil, err
+		}
+		batch.Vouchers = append(batch.Vouchers, voucher)
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+//Credits voucher amount to user wallet of voucher currency by "voucher" operation
+//checking voucher validity window, its redemptions limit and per-user one; records the redemption
+func (s *Storage) RedeemVoucher(request *types.RedeemVoucherRequest) (result interface{}, err error) {
+	var redeemedByUser int
+
+	tx := s.db.Begin()
+	defer func() { s.finishTransaction(tx, err) }()
+
+	voucher := &types.Voucher{}
+	found := tx.Set("gorm:query_option", "FOR UPDATE").Where(&types.Voucher{Code: types.NormalizeVoucherCode(request.Code)}).First(voucher)
+	if found.RecordNotFound() {
+		err = errors.New("Voucher not found")
+		return nil, err
+	}
+	if err = found.Error; err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if voucher.ValidFrom != nil && now.Before(*voucher.ValidFrom) {
+		err = errors.New("Voucher is not valid yet")
+		return nil, err
+	}
+	if voucher.ValidUntil != nil && !now.Before(*voucher.ValidUntil) {
+		err = errors.New("Voucher expired")
+		return nil, err
+	}
+	if voucher.MaxRedemptions > 0 && voucher.Redemptions >= voucher.MaxRedemptions {
+		err = errors.New("Voucher is fully redeemed")
+		return nil, err
+	}
+	if voucher.PerUserLimit > 0 {
+		if err = tx.Model(&types.VoucherRedemption{}).Where(&types.VoucherRedemption{VoucherId: voucher.ID, UserId: request.UserId}).
+			Count(&redeemedByUser).Error; err != nil {
+			return nil, err
+		}
+		if redeemedByUser >= voucher.PerUserLimit {
+			err = errors.New("User already redeemed voucher")
+			return nil, err
+		}
+	}
+
+	var expiresAt *time.Time
+	if voucher.ExpiresInDays > 0 {
+		lotExpiresAt := now.AddDate(0, 0, voucher.ExpiresInDays)
+		expiresAt = &lotExpiresAt
+	}
+	balance, operation, err := s.creditWallet(tx, request.UserId, voucher.Currency, voucher.Amount, types.OPERATION_KIND_VOUCHER, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	redemption := &types.VoucherRedemption{
+		VoucherId:   voucher.ID,
+		UserId:      request.UserId,
+		Code:        voucher.Code,
+		Amount:      voucher.Amount,
+		Currency:    voucher.Currency,
+		OperationId: operation.ID,
+	}
+	if err = tx.Create(redemption).Error; err != nil {
+		return nil, err
+	}
+	if err = tx.Model(voucher).UpdateColumn("redemptions", gorm.Expr("redemptions + 1")).Error; err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	redemption.Balance = balance
+	return redemption, nil
+}
+
+//Fetches page of voucher redemptions matching query filters, newest first
+func (s *Storage) FetchVoucherRedemptions(query *types.VoucherRedemptionsQuery) (interface{}, error) {
+	var (
+		redemptions []*types.VoucherRedemption
+		total       int
+		err         error
+	)
+	db := s.db.Model(&types.VoucherRedemption{}).Where(&types.VoucherRedemption{VoucherId: query.VoucherId, UserId: query.UserId})
+	if err = db.Count(&total).Error; err != nil {
+		return nil, errors.New("An error occured during voucher redemptions counting")
+	}
+	redemptions = []*types.VoucherRedemption{}
+	if err = db.Order("id DESC").Limit(query.Limit).Offset(query.Offset).Find(&redemptions).Error; err != nil {
+		return nil, errors.New("An error occured during voucher redemptions fetching")
+	}
+	return &types.VoucherRedemptionsPage{
+		Redemptions: redemptions,
+		Total:       total,
+	}, nil
+}
+
+//Makes voucher of request having code made of prefix and random part unless request.Code is given
+func newVoucher(request *types.VoucherRequest, prefix string) (*types.Voucher, error) {
+	code := types.NormalizeVoucherCode(request.Code)
+	if code == "" {
+		random, err := randomCode(VOUCHER_CODE_LENGTH)
+		if err != nil {
+			return nil, err
+		}
+		code = prefix + random
+	}
+	return &types.Voucher{
+		Code:           code,
+		Amount:         request.Amount,
+		Currency:       types.CurrencyOrDefault(request.Currency),
+		MaxRedemptions: request.MaxRedemptions,
+		PerUserLimit:   request.PerUserLimit,
+		ValidFrom:      request.ValidFrom,
+		ValidUntil:     request.ValidUntil,
+		ExpiresInDays:  request.ExpiresInDays,
+	}, nil
+}
+
+func randomCode(length int) (string, error) {
+	random := make([]byte, length)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	code := make([]byte, length)
+	for i, b := range random {
+		code[i] = types.VOUCHER_CODE_ALPHABET[int(b)%len(types.VOUCHER_CODE_ALPHABET)]
+	}
+	return string(code), nil
+}
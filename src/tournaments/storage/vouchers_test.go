@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morrah77/game_tournament_api/src/tournaments/api/types"
+)
+
+func TestRedeemVoucherLimits(t *testing.T) {
+	s := newTestStorage(t, nil)
+	if _, err := s.CreateVoucher(&types.VoucherRequest{Code: "SPRING", Amount: 10, MaxRedemptions: 3, PerUserLimit: 2}); err != nil {
+		t.Fatal(err)
+	}
+	// Redemptions in order along with error each of them fails with
+	steps := []struct {
+		userId uint
+		err    string
+	}{
+		{1, ""},
+		{1, ""},
+		{1, "User already redeemed voucher"},
+		{2, ""},
+		{3, "Voucher is fully redeemed"},
+	}
+	for i, step := range steps {
+		_, err := s.RedeemVoucher(&types.RedeemVoucherRequest{UserId: step.userId, Code: "spring"})
+		if (err == nil && step.err != "") || (err != nil && err.Error() != step.err) {
+			t.Fatalf("redemption %d by user %d got error %v, want %q", i+1, step.userId, err, step.err)
+		}
+	}
+
+	expectBalance(t, s, 1, 20, 0)
+	expectBalance(t, s, 2, 10, 0)
+	if _, err := s.FetchBalance(3, types.CURRENCY_POINTS); err == nil {
+		t.Error("user 3 got wallet credited")
+	}
+	voucher := &types.Voucher{}
+	if err := s.db.Where(&types.Voucher{Code: "SPRING"}).First(voucher).Error; err != nil {
+		t.Fatal(err)
+	}
+	redemptions := 0
+	s.db.Model(&types.VoucherRedemption{}).Where(&types.VoucherRedemption{VoucherId: voucher.ID}).Count(&redemptions)
+	if voucher.Redemptions != 3 || redemptions != 3 {
+		t.Errorf("got %d redemptions counted and %d recorded, want 3", voucher.Redemptions, redemptions)
+	}
+}
+
+func TestRedeemVoucherValidityWindow(t *testing.T) {
+	s := newTestStorage(t, nil)
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	vouchers := map[string]*types.VoucherRequest{
+		"EARLY":   {Code: "EARLY", Amount: 5, ValidFrom: &future},
+		"LATE":    {Code: "LATE", Amount: 5, ValidUntil: &past},
+		"CURRENT": {Code: "CURRENT", Amount: 5, ValidFrom: &past, ValidUntil: &future},
+	}
+	for _, request := range vouchers {
+		if _, err := s.CreateVoucher(request); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for code, want := range map[string]string{"EARLY": "Voucher is not valid yet", "LATE": "Voucher expired", "MISSING": "Voucher not found"} {
+		if _, err := s.RedeemVoucher(&types.RedeemVoucherRequest{UserId: 1, Code: code}); err == nil || err.Error() != want {
+			t.Errorf("%s got error %v, want %q", code, err, want)
+		}
+	}
+	redemption, err := s.RedeemVoucher(&types.RedeemVoucherRequest{UserId: 1, Code: "CURRENT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := redemption.(*types.VoucherRedemption).Balance; balance == nil || balance.Balance != 5 {
+		t.Errorf("got balance %+v after redemption, want 5", balance)
+	}
+}